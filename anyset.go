@@ -0,0 +1,204 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrElementType is returned by AnySet when a value is not of the element
+// type of the set.
+var ErrElementType = errors.New("mapset: value of the wrong element type")
+
+// AnySet is a type-erased view of a Set[T], whose methods take and return
+// values of type any and check their type at runtime. It lets plugin
+// systems and reflection-driven frameworks, such as ORMs or template
+// engines, manipulate sets without knowing their element type at compile
+// time.
+//
+// Values are converted with a type assertion to the element type, so for
+// instance an int64 is rejected by an AnySet of ints. Operations are
+// forwarded to the wrapped set and are thread-safe if it is.
+type AnySet interface {
+	// ElemType returns the element type of the set.
+	ElemType() reflect.Type
+
+	// Add adds v to the set and returns whether it was added.
+	Add(v any) (bool, error)
+
+	// Remove removes v from the set.
+	Remove(v any) error
+
+	// Contains returns whether v is in the set. A value of another type is
+	// reported as an error rather than as absent.
+	Contains(v any) (bool, error)
+
+	// Cardinality returns the number of elements in the set.
+	Cardinality() int
+
+	// Clear removes all elements from the set.
+	Clear()
+
+	// Each calls cb with every element of the set until it returns true.
+	Each(cb func(v any) bool)
+
+	// ToSlice returns the elements of the set.
+	ToSlice() []any
+
+	// Union, Intersect and Difference return the result of the operation
+	// on the wrapped sets as a new AnySet, or ErrElementType if the sets
+	// have different element types.
+	Union(other AnySet) (AnySet, error)
+	Intersect(other AnySet) (AnySet, error)
+	Difference(other AnySet) (AnySet, error)
+
+	// Equal returns whether both sets have the same element type and the
+	// same elements.
+	Equal(other AnySet) bool
+
+	// Unwrap returns the wrapped Set[T] as an any.
+	Unwrap() any
+
+	String() string
+}
+
+// NewAnySet returns a type-erased view of s.
+func NewAnySet[T comparable](s Set[T]) AnySet {
+	return &anySet[T]{s: s}
+}
+
+type anySet[T comparable] struct {
+	s Set[T]
+}
+
+func (a *anySet[T]) ElemType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// elem converts v to the element type.
+func (a *anySet[T]) elem(v any) (T, error) {
+	elem, ok := v.(T)
+	if !ok {
+		return elem, fmt.Errorf("%w: %T is not %v", ErrElementType, v, a.ElemType())
+	}
+	return elem, nil
+}
+
+// other returns the wrapped set of other if it has the same element type.
+func (a *anySet[T]) other(other AnySet) (Set[T], error) {
+	o, ok := other.Unwrap().(Set[T])
+	if !ok {
+		return nil, fmt.Errorf("%w: set of %v is not a set of %v", ErrElementType, other.ElemType(), a.ElemType())
+	}
+	return o, nil
+}
+
+func (a *anySet[T]) Add(v any) (bool, error) {
+	elem, err := a.elem(v)
+	if err != nil {
+		return false, err
+	}
+	return a.s.Add(elem), nil
+}
+
+func (a *anySet[T]) Remove(v any) error {
+	elem, err := a.elem(v)
+	if err != nil {
+		return err
+	}
+	a.s.Remove(elem)
+	return nil
+}
+
+func (a *anySet[T]) Contains(v any) (bool, error) {
+	elem, err := a.elem(v)
+	if err != nil {
+		return false, err
+	}
+	return a.s.ContainsOne(elem), nil
+}
+
+func (a *anySet[T]) Cardinality() int {
+	return a.s.Cardinality()
+}
+
+func (a *anySet[T]) Clear() {
+	a.s.Clear()
+}
+
+func (a *anySet[T]) Each(cb func(v any) bool) {
+	a.s.Each(func(elem T) bool {
+		return cb(elem)
+	})
+}
+
+func (a *anySet[T]) ToSlice() []any {
+	vs := make([]any, 0, a.s.Cardinality())
+	a.s.Each(func(elem T) bool {
+		vs = append(vs, elem)
+		return false
+	})
+	return vs
+}
+
+func (a *anySet[T]) Union(other AnySet) (AnySet, error) {
+	o, err := a.other(other)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnySet(a.s.Union(o)), nil
+}
+
+func (a *anySet[T]) Intersect(other AnySet) (AnySet, error) {
+	o, err := a.other(other)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnySet(a.s.Intersect(o)), nil
+}
+
+func (a *anySet[T]) Difference(other AnySet) (AnySet, error) {
+	o, err := a.other(other)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnySet(a.s.Difference(o)), nil
+}
+
+func (a *anySet[T]) Equal(other AnySet) bool {
+	o, err := a.other(other)
+	return err == nil && a.s.Equal(o)
+}
+
+func (a *anySet[T]) Unwrap() any {
+	return a.s
+}
+
+func (a *anySet[T]) String() string {
+	return a.s.String()
+}
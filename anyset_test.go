@@ -0,0 +1,98 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_AnySet(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2)
+		a := NewAnySet(s)
+
+		if a.ElemType() != reflect.TypeOf(0) {
+			t.Errorf("Expected int, got: %v", a.ElemType())
+		}
+		if added, err := a.Add(3); err != nil || !added || !s.ContainsOne(3) {
+			t.Errorf("3 should be added to the wrapped set, got %v (%v)", added, err)
+		}
+		if _, err := a.Add(int64(4)); !errors.Is(err, ErrElementType) {
+			t.Errorf("Expected ErrElementType, got: %v", err)
+		}
+		if found, err := a.Contains(2); err != nil || !found {
+			t.Errorf("2 should be found, got %v (%v)", found, err)
+		}
+		if _, err := a.Contains("2"); !errors.Is(err, ErrElementType) {
+			t.Errorf("Expected ErrElementType, got: %v", err)
+		}
+		if err := a.Remove(1); err != nil || s.ContainsOne(1) {
+			t.Errorf("1 should be removed from the wrapped set (%v)", err)
+		}
+		if err := a.Remove(nil); !errors.Is(err, ErrElementType) {
+			t.Errorf("Expected ErrElementType, got: %v", err)
+		}
+
+		sum := 0
+		for _, v := range a.ToSlice() {
+			sum += v.(int)
+		}
+		if sum != 5 || a.Cardinality() != 2 {
+			t.Errorf("Expected the elements 2 and 3, got: %v", a.ToSlice())
+		}
+
+		union, err := a.Union(NewAnySet(ctor(4)))
+		if err != nil || !union.Equal(NewAnySet(ctor(2, 3, 4))) {
+			t.Errorf("Expected {2, 3, 4}, got: %v (%v)", union, err)
+		}
+		if both, err := a.Intersect(union); err != nil || !both.Equal(a) {
+			t.Errorf("Expected %v, got: %v (%v)", a, both, err)
+		}
+		if _, err := a.Difference(NewAnySet(NewSet("x"))); !errors.Is(err, ErrElementType) {
+			t.Errorf("Expected ErrElementType, got: %v", err)
+		}
+		if a.Equal(NewAnySet(NewSet[string]())) {
+			t.Error("sets of different element types should not be equal")
+		}
+		if a.Unwrap().(Set[int]) != s {
+			t.Error("Unwrap should return the wrapped set")
+		}
+
+		a.Clear()
+		if !s.IsEmpty() {
+			t.Error("Clear should empty the wrapped set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
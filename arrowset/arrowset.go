@@ -0,0 +1,124 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package arrowset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// ErrInvalidOffsets is returned when the offsets of a string array are not
+// increasing or point outside of its data.
+var ErrInvalidOffsets = errors.New("arrowset: invalid offsets")
+
+// ErrTooLarge is returned when the elements of a set don't fit in a string
+// array with 32-bit offsets.
+var ErrTooLarge = errors.New("arrowset: too large for a string array")
+
+// Primitive is a constraint that permits the element types of the
+// fixed-width primitive Arrow arrays.
+type Primitive interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+// FromValues returns a new set with the elements of a primitive array. Its
+// values are the elements of values whose bit is set in the validity bitmap
+// of the array, starting at bit offset; a nil validity bitmap means that
+// the array has no nulls. Operations on the resulting set are thread-safe.
+func FromValues[T Primitive](values []T, validity []byte, offset int) mapset.Set[T] {
+	s := mapset.NewSetWithSize[T](len(values))
+	if validity == nil {
+		s.Append(values...)
+		return s
+	}
+	for i, v := range values {
+		if valid(validity, offset+i) {
+			s.Add(v)
+		}
+	}
+	return s
+}
+
+// FromStrings returns a new set with the elements of a string array, given
+// its offsets, data and validity buffers: element i is data[offsets[i]:
+// offsets[i+1]], unless its bit is clear in the validity bitmap, starting
+// at bit offset. A nil validity bitmap means that the array has no nulls.
+// Operations on the resulting set are thread-safe.
+func FromStrings(offsets []int32, data []byte, validity []byte, offset int) (mapset.Set[string], error) {
+	if len(offsets) == 0 {
+		return mapset.NewSet[string](), nil
+	}
+
+	n := len(offsets) - 1
+	elems := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		start, end := offsets[i], offsets[i+1]
+		if start < 0 || end < start || int(end) > len(data) {
+			return nil, fmt.Errorf("%w: element %d spans [%d, %d) of %d bytes", ErrInvalidOffsets, i, start, end, len(data))
+		}
+		if validity == nil || valid(validity, offset+i) {
+			elems = append(elems, string(data[start:end]))
+		}
+	}
+	return mapset.NewSet(elems...), nil
+}
+
+// Values returns the elements of s as the values buffer of a primitive
+// array without nulls, in no particular order.
+func Values[T Primitive](s mapset.Set[T]) []T {
+	return s.ToSlice()
+}
+
+// Strings returns the elements of s as the offsets and data buffers of a
+// string array without nulls, in no particular order. It reports
+// ErrTooLarge if the elements hold more than 2GiB.
+func Strings(s mapset.Set[string]) (offsets []int32, data []byte, err error) {
+	elems := s.ToSlice()
+	size := 0
+	for _, elem := range elems {
+		size += len(elem)
+	}
+	if size > math.MaxInt32 {
+		return nil, nil, ErrTooLarge
+	}
+
+	offsets = make([]int32, 1, len(elems)+1)
+	data = make([]byte, 0, size)
+	for _, elem := range elems {
+		data = append(data, elem...)
+		offsets = append(offsets, int32(len(data)))
+	}
+	return offsets, data, nil
+}
+
+// valid returns whether bit i of an Arrow validity bitmap is set. Bitmaps
+// are least significant bit first.
+func valid(bitmap []byte, i int) bool {
+	return bitmap[i/8]&(1<<(i%8)) != 0
+}
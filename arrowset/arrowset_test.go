@@ -0,0 +1,89 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package arrowset
+
+import (
+	"errors"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+func Test_FromValues(t *testing.T) {
+	values := []int64{1, 2, 2, 3, 4, 5}
+	if s := FromValues(values, nil, 0); !s.Equal(mapset.NewSet[int64](1, 2, 3, 4, 5)) {
+		t.Errorf("expected all values without a validity bitmap, got %v", s)
+	}
+
+	// bits 2 to 7 are the values; 3 (bit 5) and 5 (bit 7) are null
+	validity := []byte{0b01011100}
+	if s := FromValues(values, validity, 2); !s.Equal(mapset.NewSet[int64](1, 2, 4)) {
+		t.Errorf("expected nulls to be skipped, got %v", s)
+	}
+
+	floats := Values(mapset.NewSet(1.5, 2.5))
+	if !mapset.NewSet(floats...).Equal(mapset.NewSet(1.5, 2.5)) {
+		t.Errorf("expected the values 1.5 and 2.5, got %v", floats)
+	}
+}
+
+func Test_Strings(t *testing.T) {
+	s := mapset.NewSet("alice", "", "bob")
+	offsets, data, err := Strings(s)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if len(offsets) != 4 || len(data) != 8 {
+		t.Errorf("expected 4 offsets and 8 bytes, got %v and %q", offsets, data)
+	}
+
+	decoded, err := FromStrings(offsets, data, nil, 0)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !decoded.Equal(s) {
+		t.Errorf("expected %v, got %v", s, decoded)
+	}
+
+	// a slice of an array starting at its second element, where the
+	// third one is null
+	withNulls, err := FromStrings([]int32{1, 3, 4, 6}, []byte("xabcde"), []byte{0b1010}, 1)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !withNulls.Equal(mapset.NewSet("ab", "de")) {
+		t.Errorf("expected {ab, de}, got %v", withNulls)
+	}
+
+	if empty, err := FromStrings(nil, nil, nil, 0); err != nil || !empty.IsEmpty() {
+		t.Errorf("expected an empty set, got %v, %v", empty, err)
+	}
+	for _, offsets := range [][]int32{{0, 2, 1}, {0, 9}, {-1, 0}} {
+		if _, err := FromStrings(offsets, []byte("abc"), nil, 0); !errors.Is(err, ErrInvalidOffsets) {
+			t.Errorf("Expected %v for offsets %v, got: %v", ErrInvalidOffsets, offsets, err)
+		}
+	}
+}
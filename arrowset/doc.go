@@ -0,0 +1,41 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package arrowset converts between sets and the buffers of Apache Arrow
+// arrays, so that columnar data can be handed to set algebra and back
+// without boxing every element. It has no dependencies: the functions work
+// on the buffers of the Arrow columnar format, which the Arrow libraries
+// expose without copying. With github.com/apache/arrow-go, for instance:
+//
+//	// arr is an *array.Int64
+//	ids := arrowset.FromValues(arr.Int64Values(), arr.NullBitmapBytes(), arr.Offset())
+//
+//	// arr is an *array.String
+//	names, err := arrowset.FromStrings(arr.ValueOffsets(), arr.Data().Buffers()[2].Bytes(), arr.NullBitmapBytes(), arr.Offset())
+//
+// and back, with array.NewInt64Data or array.NewStringData over the
+// buffers returned by Values and Strings. Null elements are skipped when
+// importing, and exported arrays have no nulls.
+package arrowset
@@ -0,0 +1,181 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// AttrSet is a set whose members carry an attribute, such as the role of
+// a user in a membership list or the source that reported an element.
+// Membership is decided by the members alone. Operations on an AttrSet are
+// thread-safe.
+type AttrSet[T comparable, A any] struct {
+	mu    sync.RWMutex
+	elems map[T]A
+}
+
+// NewAttrSet creates and returns a new empty attribute set.
+func NewAttrSet[T comparable, A any]() *AttrSet[T, A] {
+	return newAttrSet[T, A](0)
+}
+
+func newAttrSet[T comparable, A any](cardinality int) *AttrSet[T, A] {
+	return &AttrSet[T, A]{elems: make(map[T]A, cardinality)}
+}
+
+// Add adds v with the attribute a and returns whether v was added. If v is
+// already a member its attribute is left unchanged, see Set.
+func (s *AttrSet[T, A]) Add(v T, a A) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.elems[v]; found {
+		return false
+	}
+	s.elems[v] = a
+	return true
+}
+
+// Set adds v with the attribute a, replacing the attribute of v if it is
+// already a member.
+func (s *AttrSet[T, A]) Set(v T, a A) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.elems[v] = a
+}
+
+// Get returns the attribute of v and whether v is a member.
+func (s *AttrSet[T, A]) Get(v T) (A, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, found := s.elems[v]
+	return a, found
+}
+
+// Contains returns whether all of the given elements are members.
+func (s *AttrSet[T, A]) Contains(vs ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range vs {
+		if _, found := s.elems[v]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Remove removes v and its attribute from the set.
+func (s *AttrSet[T, A]) Remove(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.elems, v)
+}
+
+// Cardinality returns the number of members of the set.
+func (s *AttrSet[T, A]) Cardinality() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.elems)
+}
+
+// Clone returns a copy of the set. The attributes are copied by assignment.
+func (s *AttrSet[T, A]) Clone() *AttrSet[T, A] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clone := newAttrSet[T, A](len(s.elems))
+	for v, a := range s.elems {
+		clone.elems[v] = a
+	}
+	return clone
+}
+
+// Each iterates over the members of the set and their attributes and
+// executes cb against each of them. If cb returns true, the iteration
+// stops. cb must not modify the set.
+func (s *AttrSet[T, A]) Each(cb func(v T, a A) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for v, a := range s.elems {
+		if cb(v, a) {
+			break
+		}
+	}
+}
+
+// Members returns the members of the set, without their attributes.
+func (s *AttrSet[T, A]) Members() Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := newThreadSafeSetWithSize[T](len(s.elems))
+	for v := range s.elems {
+		members.uss.add(v)
+	}
+	return members
+}
+
+// UnionResolve returns a new set with the members of both sets. Members of
+// a single set keep their attribute; for the members of both, resolve is
+// called with the attribute in s and the one in other and returns the
+// attribute of the member in the union, so that merging two annotated lists
+// never silently prefers one side.
+func (s *AttrSet[T, A]) UnionResolve(other *AttrSet[T, A], resolve func(a, b A) A) *AttrSet[T, A] {
+	union := other.Clone()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for v, a := range s.elems {
+		if b, found := union.elems[v]; found {
+			a = resolve(a, b)
+		}
+		union.elems[v] = a
+	}
+	return union
+}
+
+// String provides a convenient string representation of the current state
+// of the set, listing the members with their attributes.
+func (s *AttrSet[T, A]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]string, 0, len(s.elems))
+	for v, a := range s.elems {
+		items = append(items, fmt.Sprintf("%v: %v", v, a))
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
@@ -0,0 +1,100 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_AttrSet(t *testing.T) {
+	s := NewAttrSet[string, int]()
+
+	if !s.Add("a", 1) || s.Add("a", 2) {
+		t.Error("Add should report whether the member was added")
+	}
+	if a, ok := s.Get("a"); !ok || a != 1 {
+		t.Errorf("Add should not replace the attribute, got %d", a)
+	}
+	s.Set("a", 3)
+	s.Set("b", 4)
+	if a, _ := s.Get("a"); a != 3 {
+		t.Errorf("Set should replace the attribute, got %d", a)
+	}
+	if _, ok := s.Get("c"); ok {
+		t.Error("c should not be a member")
+	}
+	if !s.Contains("a", "b") || s.Contains("a", "c") || s.Cardinality() != 2 {
+		t.Errorf("unexpected members: %v", s)
+	}
+	if !s.Members().Equal(NewSet("a", "b")) {
+		t.Errorf("unexpected members: %v", s.Members())
+	}
+
+	clone := s.Clone()
+	s.Remove("a")
+	if s.Contains("a") || !clone.Contains("a") {
+		t.Error("clone should be independent of the original")
+	}
+
+	sum := 0
+	clone.Each(func(_ string, a int) bool {
+		sum += a
+		return false
+	})
+	if sum != 7 {
+		t.Errorf("expected attributes to sum to 7, got %d", sum)
+	}
+	if str := s.String(); str != "Set{b: 4}" {
+		t.Errorf("unexpected string: %s", str)
+	}
+}
+
+func Test_AttrSetUnionResolve(t *testing.T) {
+	a := NewAttrSet[string, int]()
+	a.Set("x", 1)
+	a.Set("both", 10)
+	b := NewAttrSet[string, int]()
+	b.Set("y", 2)
+	b.Set("both", 20)
+
+	var calls [][2]int
+	union := a.UnionResolve(b, func(x, y int) int {
+		calls = append(calls, [2]int{x, y})
+		return x + y
+	})
+
+	if len(calls) != 1 || calls[0] != [2]int{10, 20} {
+		t.Errorf("resolve should be called once with the attributes of both sides, got %v", calls)
+	}
+	for v, want := range map[string]int{"x": 1, "y": 2, "both": 30} {
+		if got, _ := union.Get(v); got != want {
+			t.Errorf("expected %d for %s, got %d", want, v, got)
+		}
+	}
+	if union.Cardinality() != 3 || a.Cardinality() != 2 || b.Cardinality() != 2 {
+		t.Errorf("unexpected union %v of %v and %v", union, a, b)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditRecord describes a single mutation of an audited set.
+type AuditRecord[T comparable] struct {
+	// Op is the kind of mutation.
+	Op Op
+	// Element is the element that was added or removed.
+	Element T
+	// Time is when the mutation happened.
+	Time time.Time
+	// Caller is the "file:line" location of the code that called the
+	// mutating set method, or "unknown" if it can't be determined.
+	Caller string
+	// Goroutine is the ID of the goroutine that called the mutating set
+	// method, as printed in stack traces, or 0 if it can't be determined.
+	Goroutine uint64
+}
+
+// AuditSink receives the records of an audited set.
+type AuditSink[T comparable] interface {
+	Audit(AuditRecord[T])
+}
+
+// AuditFunc is an adapter to allow the use of ordinary functions as audit
+// sinks.
+type AuditFunc[T comparable] func(AuditRecord[T])
+
+// Audit calls f(r).
+func (f AuditFunc[T]) Audit(r AuditRecord[T]) {
+	f(r)
+}
+
+// NewAuditedSet returns a set that forwards all operations to s and sends
+// an AuditRecord to sink for every element that was actually added or
+// removed, so that sensitive membership lists such as ACLs get an audit
+// trail for free. The sink is called synchronously after each mutation and
+// must be safe for concurrent use if the set is used concurrently.
+//
+// Mutations must go through the returned set to be audited; s itself
+// should no longer be used directly.
+func NewAuditedSet[T comparable](s Set[T], sink AuditSink[T]) Set[T] {
+	return newObservedSet(s, func(op Op, vs []T) {
+		now := time.Now()
+		caller, goroutine := auditCaller(), auditGoroutine()
+		for _, v := range vs {
+			sink.Audit(AuditRecord[T]{Op: op, Element: v, Time: now, Caller: caller, Goroutine: goroutine})
+		}
+	})
+}
+
+// auditCaller returns the location of the first caller outside of this
+// package. Test files of this package count as callers.
+func auditCaller() string {
+	pcs := make([]uintptr, 16)
+	// skip runtime.Callers and auditCaller
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePath+".") || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// auditGoroutine returns the ID of the calling goroutine, parsed from the
+// "goroutine N [status]:" header of its stack trace since the runtime
+// doesn't expose it otherwise.
+func auditGoroutine() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	fields := strings.Fields(string(buf))
+	if len(fields) < 2 || fields[0] != "goroutine" {
+		return 0
+	}
+	id, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// packagePath is the import path of this package, as it appears in
+// function names reported by the runtime.
+const packagePath = "github.com/deckarep/golang-set/v2"
@@ -0,0 +1,161 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingSink[T comparable] struct {
+	mu      sync.Mutex
+	records []AuditRecord[T]
+}
+
+func (r *recordingSink[T]) Audit(rec AuditRecord[T]) {
+	r.mu.Lock()
+	r.records = append(r.records, rec)
+	r.mu.Unlock()
+}
+
+func (r *recordingSink[T]) ops() map[Op]Set[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := map[Op]Set[T]{OpAdd: NewSet[T](), OpRemove: NewSet[T]()}
+	for _, rec := range r.records {
+		ops[rec.Op].Add(rec.Element)
+	}
+	return ops
+}
+
+func Test_AuditedSet(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		sink := &recordingSink[string]{}
+		acl := NewAuditedSet[string](ctor(), sink)
+
+		acl.Add("alice")
+		acl.Add("alice")
+		acl.Append("bob", "carol", "alice")
+		acl.Remove("dave")
+		acl.Remove("bob")
+
+		if len(sink.records) != 4 {
+			t.Fatalf("expected 4 records, got %d: %v", len(sink.records), sink.records)
+		}
+		ops := sink.ops()
+		if !ops[OpAdd].Equal(NewSet("alice", "bob", "carol")) {
+			t.Errorf("unexpected add records: %v", ops[OpAdd])
+		}
+		if !ops[OpRemove].Equal(NewSet("bob")) {
+			t.Errorf("unexpected remove records: %v", ops[OpRemove])
+		}
+
+		for _, rec := range sink.records {
+			if !strings.Contains(rec.Caller, "audit_test.go:") {
+				t.Errorf("expected the caller to be in audit_test.go, got %s", rec.Caller)
+			}
+			if rec.Time.IsZero() {
+				t.Error("expected the record to be timestamped")
+			}
+			if rec.Goroutine == 0 || rec.Goroutine != sink.records[0].Goroutine {
+				t.Errorf("expected the goroutine of the test, got %d", rec.Goroutine)
+			}
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acl.Add("erin")
+		}()
+		wg.Wait()
+		if last := sink.records[len(sink.records)-1]; last.Goroutine == sink.records[0].Goroutine {
+			t.Errorf("expected another goroutine to be recorded, got %d", last.Goroutine)
+		}
+
+		sink.records = nil
+		acl.Clear()
+		if len(sink.records) != 3 || !sink.ops()[OpRemove].Equal(NewSet("alice", "carol", "erin")) {
+			t.Errorf("Clear should record the removal of every element, got %v", sink.records)
+		}
+		if !acl.IsEmpty() {
+			t.Error("Clear should empty the set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_AuditedSetPop(t *testing.T) {
+	var records []AuditRecord[int]
+	s := NewAuditedSet[int](NewSet(1, 2, 3), AuditFunc[int](func(r AuditRecord[int]) {
+		records = append(records, r)
+	}))
+
+	v, _ := s.Pop()
+	items, _ := s.PopN(5)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	if records[0].Element != v || records[0].Op != OpRemove {
+		t.Errorf("expected the popped element to be recorded, got %v", records[0])
+	}
+	for i, item := range items {
+		if records[i+1].Element != item {
+			t.Errorf("expected the popped element %v to be recorded, got %v", item, records[i+1])
+		}
+	}
+
+	if _, ok := s.Pop(); ok || len(records) != 3 {
+		t.Error("popping an empty set should not be recorded")
+	}
+}
+
+func Test_AuditedSetUnmarshalJSON(t *testing.T) {
+	sink := &recordingSink[int]{}
+	s := NewAuditedSet[int](NewSet(1), sink)
+
+	if err := s.UnmarshalJSON([]byte(`[1, 2, 3]`)); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !sink.ops()[OpAdd].Equal(NewSet(2, 3)) {
+		t.Errorf("only the new elements should be recorded, got %v", sink.records)
+	}
+}
+
+func Test_AuditedSetAsOperand(t *testing.T) {
+	audited := NewAuditedSet[int](NewSet(1, 2), &recordingSink[int]{})
+	if !NewSet(2, 3).Union(audited).Equal(NewSet(1, 2, 3)) {
+		t.Error("an audited set should be usable as an operand of a plain set")
+	}
+}
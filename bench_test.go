@@ -361,6 +361,7 @@ func benchDifference(b *testing.B, n int, s, t Set[int]) {
 		t.Add(v)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		s.Difference(t)
@@ -539,6 +540,14 @@ func BenchmarkDifference100Unsafe(b *testing.B) {
 	benchDifference(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkDifference1000Safe(b *testing.B) {
+	benchDifference(b, 1000, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkDifference1000Unsafe(b *testing.B) {
+	benchDifference(b, 1000, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
 func benchIntersect(b *testing.B, n int, s, t Set[int]) {
 	nums := nrand(int(float64(n) * float64(1.5)))
 	for _, v := range nums[:n] {
@@ -548,6 +557,7 @@ func benchIntersect(b *testing.B, n int, s, t Set[int]) {
 		t.Add(v)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		s.Intersect(t)
@@ -578,6 +588,14 @@ func BenchmarkIntersect100Unsafe(b *testing.B) {
 	benchIntersect(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkIntersect1000Safe(b *testing.B) {
+	benchIntersect(b, 1000, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkIntersect1000Unsafe(b *testing.B) {
+	benchIntersect(b, 1000, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
 func benchSymmetricDifference(b *testing.B, n int, s, t Set[int]) {
 	nums := nrand(int(float64(n) * float64(1.5)))
 	for _, v := range nums[:n] {
@@ -587,6 +605,7 @@ func benchSymmetricDifference(b *testing.B, n int, s, t Set[int]) {
 		t.Add(v)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		s.SymmetricDifference(t)
@@ -617,6 +636,14 @@ func BenchmarkSymmetricDifference100Unsafe(b *testing.B) {
 	benchSymmetricDifference(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkSymmetricDifference1000Safe(b *testing.B) {
+	benchSymmetricDifference(b, 1000, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkSymmetricDifference1000Unsafe(b *testing.B) {
+	benchSymmetricDifference(b, 1000, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
 func benchUnion(b *testing.B, n int, s, t Set[int]) {
 	nums := nrand(n)
 	for _, v := range nums[:n/2] {
@@ -626,6 +653,7 @@ func benchUnion(b *testing.B, n int, s, t Set[int]) {
 		t.Add(v)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		s.Union(t)
@@ -656,6 +684,14 @@ func BenchmarkUnion100Unsafe(b *testing.B) {
 	benchUnion(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkUnion1000Safe(b *testing.B) {
+	benchUnion(b, 1000, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkUnion1000Unsafe(b *testing.B) {
+	benchUnion(b, 1000, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
 func benchEach(b *testing.B, n int, s Set[int]) {
 	nums := nrand(n)
 	for _, v := range nums {
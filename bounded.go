@@ -0,0 +1,162 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BoundedSet is a thread-safe set holding at most a fixed number of
+// elements: adding an element to a full set evicts the least recently used
+// member, the one that was added or found by Contains the longest time ago.
+// It suits deduplication caches, which only need to remember the recent
+// elements.
+type BoundedSet[T comparable] struct {
+	mu      sync.Mutex
+	max     int
+	members *recencyList[T, struct{}]
+	onEvict func(T)
+}
+
+// NewBoundedSet creates and returns a new BoundedSet holding at most max
+// elements. It panics if max is not positive.
+func NewBoundedSet[T comparable](max int) *BoundedSet[T] {
+	if max <= 0 {
+		panic(fmt.Sprintf("mapset: non-positive BoundedSet size %d", max))
+	}
+	return &BoundedSet[T]{
+		max:     max,
+		members: newRecencyList[T, struct{}](0),
+	}
+}
+
+// OnEvict registers fn to be called with every element evicted to make room
+// for a new one, but not with removed elements. fn is called with the lock
+// of the set held and must not use the set.
+func (s *BoundedSet[T]) OnEvict(fn func(T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onEvict = fn
+}
+
+// Add adds an element to the set, evicting the least recently used member
+// if the set is full, or marks it as used if it is already present. Returns
+// whether the element was added.
+func (s *BoundedSet[T]) Add(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.members.touch(v); found {
+		return false
+	}
+	if s.members.len() >= s.max {
+		s.evict()
+	}
+	s.members.push(v, struct{}{})
+	return true
+}
+
+// evict must be called with the lock held.
+func (s *BoundedSet[T]) evict() {
+	e, _ := s.members.oldest()
+	s.members.remove(e.value)
+	if s.onEvict != nil {
+		s.onEvict(e.value)
+	}
+}
+
+// Contains returns whether the given element is in the set, and marks it as
+// used if it is.
+func (s *BoundedSet[T]) Contains(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, found := s.members.touch(v)
+	return found
+}
+
+// Peek returns whether the given element is in the set, without marking it
+// as used.
+func (s *BoundedSet[T]) Peek(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, found := s.members.get(v)
+	return found
+}
+
+// Remove removes a single element from the set and returns whether it was
+// present.
+func (s *BoundedSet[T]) Remove(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.members.remove(v)
+}
+
+// Clear removes all elements from the set.
+func (s *BoundedSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.members.clear()
+}
+
+// Cardinality returns the number of elements in the set.
+func (s *BoundedSet[T]) Cardinality() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.members.len()
+}
+
+// Max returns the maximum number of elements of the set.
+func (s *BoundedSet[T]) Max() int {
+	return s.max
+}
+
+// ToSlice returns the members of the set, most recently used first.
+func (s *BoundedSet[T]) ToSlice() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vs := make([]T, 0, s.members.len())
+	s.members.newestFirst(func(e *recencyEntry[T, struct{}]) bool {
+		vs = append(vs, e.value)
+		return false
+	})
+	return vs
+}
+
+// ToSet returns the members of the set as a new thread-safe Set.
+func (s *BoundedSet[T]) ToSet() Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.members.toSet()
+}
@@ -0,0 +1,108 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_BoundedSet(t *testing.T) {
+	s := NewBoundedSet[string](3)
+	var evicted []string
+	s.OnEvict(func(v string) { evicted = append(evicted, v) })
+
+	s.Add("a")
+	s.Add("b")
+	s.Add("c")
+	if s.Add("a") {
+		t.Error("adding an existing element should report false")
+	} // order: a, c, b
+	if !s.Contains("c") {
+		t.Error("expected c to be in the set")
+	} // order: c, a, b
+	if !s.Peek("b") {
+		t.Error("expected b to be in the set")
+	} // Peek doesn't change the order
+
+	if !s.Add("d") {
+		t.Error("adding a new element should report true")
+	}
+	if s.Cardinality() != 3 || s.Peek("b") {
+		t.Errorf("expected b to be evicted, got %v", s.ToSlice())
+	}
+	if want := []string{"d", "c", "a"}; !reflect.DeepEqual(s.ToSlice(), want) {
+		t.Errorf("expected members %v, got %v", want, s.ToSlice())
+	}
+
+	if !s.Remove("c") || s.Remove("c") {
+		t.Error("Remove should report whether the element was present")
+	}
+	s.Add("e")
+	s.Add("f")
+	if want := []string{"b", "a"}; !reflect.DeepEqual(evicted, want) {
+		t.Errorf("expected %v to be evicted, got %v", want, evicted)
+	}
+	if !s.ToSet().Equal(NewSet("d", "e", "f")) {
+		t.Errorf("expected {d, e, f}, got %v", s.ToSet())
+	}
+
+	s.Clear()
+	if s.Cardinality() != 0 || s.Max() != 3 {
+		t.Error("Clear should remove all elements and keep the size")
+	}
+}
+
+func Test_BoundedSetInvalidSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected NewBoundedSet to panic on a non-positive size")
+		}
+	}()
+	NewBoundedSet[int](0)
+}
+
+func Test_BoundedSetConcurrent(t *testing.T) {
+	s := NewBoundedSet[int](100)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, v := range nrand(N) {
+				s.Add(v)
+				s.Contains(v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Cardinality() != 100 {
+		t.Errorf("expected the set to be full, got %d elements", s.Cardinality())
+	}
+}
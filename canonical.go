@@ -0,0 +1,94 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// CanonicalBytes returns a canonical serialized form of s that only depends
+// on its contents, not on the iteration order or implementation. It is
+// suitable for hashing, signing or content-addressing a set.
+//
+// The output is the number of elements as an unsigned varint followed by the
+// JSON encoding of every element in ascending byte order, each prefixed by
+// its length as an unsigned varint. Elements that cannot be JSON encoded use
+// their fmt representation instead.
+//
+// The dynamic type of an element is not part of its encoding, so in a
+// Set[any] the int 1 and the float64 1.0 both encode as 1 and the two sets
+// {1} and {1.0} have the same canonical form.
+func CanonicalBytes[T comparable](s Set[T]) []byte {
+	if c, ok := implementation[interface{ CanonicalBytes() []byte }](s); ok {
+		return c.CanonicalBytes()
+	}
+
+	encoded := make([][]byte, 0, s.Cardinality())
+	s.Each(func(v T) bool {
+		encoded = append(encoded, canonicalElement(v))
+		return false
+	})
+	return canonicalBytes(encoded)
+}
+
+// canonicalElement returns a deterministic byte encoding of v. Elements
+// are JSON encoded, which is stable for strings, numbers, booleans and
+// structs of those. Values that JSON can't represent (channels, ...)
+// fall back to their fmt representation.
+func canonicalElement[T comparable](v T) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte(fmt.Sprintf("%v", v))
+	}
+	return b
+}
+
+// canonicalBytes returns the canonical form of the given encoded elements:
+// the number of elements as an unsigned varint, followed by every element
+// in ascending byte order, each prefixed by its length as an unsigned varint.
+// The encoded elements are sorted in place.
+func canonicalBytes(encoded [][]byte) []byte {
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+
+	size := binary.MaxVarintLen64
+	for _, e := range encoded {
+		size += binary.MaxVarintLen64 + len(e)
+	}
+
+	buf := make([]byte, size)
+	n := binary.PutUvarint(buf, uint64(len(encoded)))
+	for _, e := range encoded {
+		n += binary.PutUvarint(buf[n:], uint64(len(e)))
+		n += copy(buf[n:], e)
+	}
+	return buf[:n]
+}
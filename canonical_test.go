@@ -0,0 +1,84 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func Test_CanonicalBytes(t *testing.T) {
+	safe := NewSet("pear", "apple", "banana")
+	unsafe := NewThreadUnsafeSet("banana", "pear", "apple")
+
+	b := CanonicalBytes(safe)
+	if !bytes.Equal(b, CanonicalBytes(unsafe)) {
+		t.Error("equal sets should have the same canonical form regardless of implementation")
+	}
+	for i := 0; i < 10; i++ {
+		if !bytes.Equal(b, CanonicalBytes(safe.Clone())) {
+			t.Fatal("the canonical form should not depend on iteration order")
+		}
+	}
+
+	count, n := binary.Uvarint(b)
+	if count != 3 {
+		t.Fatalf("expected an element count of 3, got %d", count)
+	}
+	b = b[n:]
+
+	var elems []string
+	for len(b) > 0 {
+		l, n := binary.Uvarint(b)
+		b = b[n:]
+		elems = append(elems, string(b[:l]))
+		b = b[l:]
+	}
+	expected := []string{`"apple"`, `"banana"`, `"pear"`}
+	for i := range expected {
+		if elems[i] != expected[i] {
+			t.Errorf("element %d: expected %s, got %s", i, expected[i], elems[i])
+		}
+	}
+
+	if bytes.Equal(CanonicalBytes(NewSet(1, 2)), CanonicalBytes(NewSet(1, 2, 3))) {
+		t.Error("different sets should have different canonical forms")
+	}
+	if !bytes.Equal(CanonicalBytes(NewSet[int]()), []byte{0}) {
+		t.Error("the empty set should be encoded as a zero count")
+	}
+}
+
+func Test_CanonicalBytesStruct(t *testing.T) {
+	type point struct{ X, Y int }
+
+	a := NewSet(point{1, 2}, point{3, 4})
+	b := NewThreadUnsafeSet(point{3, 4}, point{1, 2})
+	if !bytes.Equal(CanonicalBytes(a), CanonicalBytes(b)) {
+		t.Error("equal sets of structs should have the same canonical form")
+	}
+}
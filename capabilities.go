@@ -0,0 +1,95 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"strings"
+)
+
+// Capabilities describes the properties of a set implementation, so that
+// generic algorithms can pick a strategy at runtime, for instance ranging
+// over an ordered set instead of sorting its elements, or batching
+// operations on a remote set.
+type Capabilities uint
+
+const (
+	// CapThreadSafe is reported by sets that are safe for concurrent use.
+	CapThreadSafe Capabilities = 1 << iota
+
+	// CapOrdered is reported by sets that iterate over their elements, and
+	// return them from ToSlice, in ascending order.
+	CapOrdered
+
+	// CapBounded is reported by sets with a maximum cardinality, which may
+	// reject or evict elements when they are full.
+	CapBounded
+
+	// CapRemote is reported by sets whose elements are held by another
+	// process, where every operation is a round trip.
+	CapRemote
+
+	// CapPersistent is reported by sets whose elements are written to
+	// durable storage as they change, so that they survive a restart.
+	CapPersistent
+
+	// CapApproximate is reported by sets that trade exactness for space,
+	// whose Contains may report false positives and whose Cardinality may
+	// be an estimate. Algorithms that need exact answers should copy such
+	// sets first or refuse them.
+	CapApproximate
+)
+
+// CapabilitiesOf returns the capabilities reported by the implementation of
+// s. Sets that don't report any, such as implementations from other
+// packages, have none.
+func CapabilitiesOf[T comparable](s Set[T]) Capabilities {
+	if c, ok := implementation[interface{ Capabilities() Capabilities }](s); ok {
+		return c.Capabilities()
+	}
+	return 0
+}
+
+var capabilityNames = []string{"thread-safe", "ordered", "bounded", "remote", "persistent", "approximate"}
+
+// Has returns whether all the capabilities of c are reported.
+func (cs Capabilities) Has(c Capabilities) bool {
+	return cs&c == c
+}
+
+// String returns the names of the capabilities separated by "|", such as
+// "thread-safe|ordered", or "none".
+func (cs Capabilities) String() string {
+	var names []string
+	for i, name := range capabilityNames {
+		if cs.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
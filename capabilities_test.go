@@ -0,0 +1,65 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_Capabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set[int]
+		want Capabilities
+	}{
+		{"Safe", NewSet[int](), CapThreadSafe},
+		{"Unsafe", NewThreadUnsafeSet[int](), 0},
+		{"WithOptions", NewSetWithOptions(RejectZero[int]()), CapThreadSafe},
+		{"Bounded", NewThreadUnsafeSetWithOptions(WithMaxCardinality[int](2, RejectWhenFull[int]())), CapBounded},
+		{"History", NewHistorySet(NewSet[int](), 4), CapThreadSafe},
+	}
+	for _, tt := range tests {
+		if got := CapabilitiesOf(tt.set); got != tt.want {
+			t.Errorf("%s: expected capabilities %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func Test_CapabilitiesString(t *testing.T) {
+	caps := CapThreadSafe | CapOrdered
+	if !caps.Has(CapOrdered) || caps.Has(CapOrdered|CapRemote) {
+		t.Error("Has should report whether all the capabilities are present")
+	}
+	if got := caps.String(); got != "thread-safe|ordered" {
+		t.Errorf("expected thread-safe|ordered, got %q", got)
+	}
+	if got := (CapPersistent | CapApproximate).String(); got != "persistent|approximate" {
+		t.Errorf("expected persistent|approximate, got %q", got)
+	}
+	if got := Capabilities(0).String(); got != "none" {
+		t.Errorf("expected none, got %q", got)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Setgen generates a read-only set of strings backed by a perfect hash table,
+// for hot allow-lists and block-lists that are known at compile time. The
+// generated Contains never allocates and the table is a plain array literal,
+// so nothing is built at init time.
+//
+// Usage:
+//
+//	setgen -type Name [-pkg package] [-o file] [-f file] [element ...]
+//
+// Elements are taken from the arguments and, if -f is given, from a
+// newline-delimited file as written by mapset.WriteLines. A typical use is:
+//
+//	//go:generate setgen -type allowedMethods GET HEAD OPTIONS
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/deckarep/golang-set/v2/internal/phash"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the generated set type (required)")
+	pkgName := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to $GOPACKAGE")
+	output := flag.String("o", "", "output file, defaults to <type>_setgen.go")
+	input := flag.String("f", "", "newline-delimited file of elements")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: setgen -type Name [-pkg package] [-o file] [-f file] [element ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeName == "" || *pkgName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	elements := mapset.NewSet(flag.Args()...)
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			fatal(err)
+		}
+		lines, err := mapset.ReadLines(f)
+		f.Close()
+		if err != nil {
+			fatal(err)
+		}
+		elements.AppendFrom(lines)
+	}
+
+	src, err := generate(*pkgName, *typeName, elements.ToSlice())
+	if err != nil {
+		fatal(err)
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_setgen.go"
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "setgen: %v\n", err)
+	os.Exit(1)
+}
+
+// generate returns the formatted source of a file declaring the read-only
+// set type typeName in package pkgName, holding elements. The header only
+// records the type, so that the output doesn't depend on the paths or flag
+// order used to run setgen.
+func generate(pkgName, typeName string, elements []string) ([]byte, error) {
+	table, err := phash.Build(elements)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := table.Keys()
+	sort.Strings(sorted)
+
+	var buf bytes.Buffer
+	err = fileTemplate.Execute(&buf, map[string]any{
+		"Package":    pkgName,
+		"Type":       typeName,
+		"Prefix":     lowerFirst(typeName),
+		"Table":      table,
+		"Sorted":     sorted,
+		"BucketMask": len(table.Seeds) - 1,
+		"SlotMask":   len(table.Slots) - 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+var fileTemplate = template.Must(template.New("setgen").Parse(`// Code generated by "setgen -type {{.Type}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+// {{.Type}} is a read-only set of {{.Table.Len}} strings backed by a perfect hash table.
+type {{.Type}} struct{}
+
+// Contains returns whether s is in the set.
+func ({{.Type}}) Contains(s string) bool {
+	if s == "" {
+		return {{.Table.HasEmpty}}
+	}
+	b := {{.Prefix}}Hash(0, s) & {{.BucketMask}}
+	i := {{.Prefix}}Hash(uint64({{.Prefix}}Seeds[b]), s) & {{.SlotMask}}
+	return {{.Prefix}}Slots[i] == s
+}
+
+// Cardinality returns the number of elements in the set.
+func ({{.Type}}) Cardinality() int {
+	return {{.Table.Len}}
+}
+
+// ToSlice returns the members of the set as a slice, in ascending order.
+func ({{.Type}}) ToSlice() []string {
+	return []string{
+		{{- range .Sorted}}
+		{{printf "%q" .}},
+		{{- end}}
+	}
+}
+
+func {{.Prefix}}Hash(seed uint64, s string) uint64 {
+	h := uint64(14695981039346656037) ^ seed
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+var {{.Prefix}}Seeds = [...]uint32{
+	{{- range .Table.Seeds}}
+	{{.}},
+	{{- end}}
+}
+
+var {{.Prefix}}Slots = [...]string{
+	{{- range .Table.Slots}}
+	{{printf "%q" .}},
+	{{- end}}
+}
+`))
@@ -0,0 +1,106 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Generate(t *testing.T) {
+	src, err := generate("main", "Methods", []string{"GET", "HEAD", "OPTIONS", "GET"})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	for _, want := range []string{
+		`// Code generated by "setgen -type Methods"; DO NOT EDIT.`,
+		"package main",
+		"type Methods struct{}",
+		"func (Methods) Contains(s string) bool",
+		"return 3",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source should contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func Test_GenerateCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go run in short mode")
+	}
+
+	elements := []string{"", "GET", "HEAD", "OPTIONS", "naïve", "a\"quote"}
+	src, err := generate("main", "Methods", elements)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	dir := t.TempDir()
+	prog := `package main
+
+import "fmt"
+
+func main() {
+	var m Methods
+	for _, s := range []string{"", "GET", "HEAD", "OPTIONS", "naïve", "a\"quote", "POST", "get"} {
+		fmt.Println(s, m.Contains(s))
+	}
+	fmt.Println(m.Cardinality(), len(m.ToSlice()))
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "methods_setgen.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(prog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "run", "main.go", "methods_setgen.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run failed: %v\n%s", err, out)
+	}
+
+	expected := ` true
+GET true
+HEAD true
+OPTIONS true
+naïve true
+a"quote true
+POST false
+get false
+6 6
+`
+	if string(out) != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// setCodec implements the encodings of a set on top of the methods of
+// Set, for the decorators and implementations that must add the decoded
+// elements through their own Append, so that it records, guards or
+// notifies them. Inputs are decoded into a temporary set first, so that a
+// malformed input doesn't add anything.
+type setCodec[T comparable] struct {
+	set Set[T]
+}
+
+// decode adds the elements decoded by fn to the set.
+func (c setCodec[T]) decode(fn func(decoded *threadUnsafeSet[T]) error) error {
+	decoded := newThreadUnsafeSet[T]()
+	if err := fn(decoded); err != nil {
+		return err
+	}
+	c.set.Append(decoded.ToSlice()...)
+	return nil
+}
+
+func (c setCodec[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.set.ToSlice())
+}
+
+func (c setCodec[T]) UnmarshalJSON(b []byte) error {
+	return c.decode(func(decoded *threadUnsafeSet[T]) error {
+		return decoded.UnmarshalJSON(b)
+	})
+}
+
+func (c setCodec[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary[T](c.set)
+}
+
+func (c setCodec[T]) UnmarshalBinary(data []byte) error {
+	return c.decode(func(decoded *threadUnsafeSet[T]) error {
+		return decoded.UnmarshalBinary(data)
+	})
+}
+
+func (c setCodec[T]) MarshalText() ([]byte, error) {
+	return EncodeText[T](c.set, DefaultTextSeparator)
+}
+
+func (c setCodec[T]) UnmarshalText(text []byte) error {
+	return c.decode(func(decoded *threadUnsafeSet[T]) error {
+		return decoded.UnmarshalText(text)
+	})
+}
+
+func (c setCodec[T]) MarshalYAML() (interface{}, error) {
+	return c.set.ToSlice(), nil
+}
+
+func (c setCodec[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return c.decode(func(decoded *threadUnsafeSet[T]) error {
+		return decoded.UnmarshalYAML(unmarshal)
+	})
+}
+
+func (c setCodec[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(c.set.ToSlice())
+}
+
+func (c setCodec[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return c.decode(func(decoded *threadUnsafeSet[T]) error {
+		return decoded.UnmarshalBSONValue(bt, b)
+	})
+}
+
+func (c setCodec[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return reconcile[T](c.set, target, add, remove)
+}
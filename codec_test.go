@@ -0,0 +1,87 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_SetCodec(t *testing.T) {
+	var added []int
+	s := newObservedSet[int](NewSet[int](), func(op Op, vs []int) {
+		if op == OpAdd {
+			added = append(added, vs...)
+		}
+	})
+	c := setCodec[int]{s}
+
+	// elements are decoded through the Append of the set
+	if err := c.UnmarshalJSON([]byte(`[1, 2]`)); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := c.UnmarshalText([]byte(`2,3`)); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if len(added) != 3 || !s.Equal(NewSet(1, 2, 3)) {
+		t.Errorf("Expected 3 elements added through Append, got: %v", added)
+	}
+
+	// malformed inputs add nothing
+	if err := c.UnmarshalJSON([]byte(`[4, "x"]`)); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+	if err := c.UnmarshalText([]byte(`4,x`)); err == nil {
+		t.Error("Expected an error for malformed text")
+	}
+	if s.Cardinality() != 3 {
+		t.Errorf("Expected malformed inputs to add nothing, got: %v", s)
+	}
+
+	bin, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	bt, b, err := c.MarshalBSONValue()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	decoded := NewThreadUnsafeSet[int]()
+	dc := setCodec[int]{decoded}
+	if err := dc.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := dc.UnmarshalBSONValue(bt, b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !decoded.Equal(NewThreadUnsafeSet(1, 2, 3)) {
+		t.Errorf("Expected %v, got: %v", s, decoded)
+	}
+	if err := dc.UnmarshalBSONValue(bson.TypeString, b); err == nil {
+		t.Error("Expected an error for a BSON value that is not an array")
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// EqualBy reports whether a and b are equal once their elements are
+// projected through key, for instance to compare sets of users by ID while
+// ignoring their other fields. Elements with the same key are collapsed, so
+// sets of different cardinalities can be equal by key.
+func EqualBy[T comparable, K comparable](a, b Set[T], key func(T) K) bool {
+	keys := make(map[K]struct{}, a.Cardinality())
+	a.Each(func(v T) bool {
+		keys[key(v)] = struct{}{}
+		return false
+	})
+
+	seen := make(map[K]struct{}, len(keys))
+	equal := true
+	b.Each(func(v T) bool {
+		k := key(v)
+		if _, found := keys[k]; !found {
+			equal = false
+			return true
+		}
+		seen[k] = struct{}{}
+		return false
+	})
+	return equal && len(seen) == len(keys)
+}
+
+// IsSubsetFunc reports whether every element of s matches, according to eq,
+// at least one element of other. It asserts containment across related
+// element types, such as expected and actual domain objects, without
+// converting either set. As eq is arbitrary, every element of s may be
+// compared with every element of other; prefer EqualBy or IsSubset when the
+// elements can be mapped to comparable keys.
+func IsSubsetFunc[T comparable, U comparable](s Set[T], other Set[U], eq func(T, U) bool) bool {
+	candidates := other.ToSlice()
+	subset := true
+	s.Each(func(v T) bool {
+		for _, u := range candidates {
+			if eq(v, u) {
+				return false
+			}
+		}
+		subset = false
+		return true
+	})
+	return subset
+}
+
+// EqualFunc reports whether every element of a matches, according to eq,
+// at least one element of b and the other way around, for instance to
+// compare strings case-insensitively. eq is always called with an element
+// of a first. Like with EqualBy, sets of different cardinalities can be
+// equal, and every element of each set may be compared with every element
+// of the other; prefer EqualBy when the elements can be mapped to
+// comparable keys, such as normalized strings.
+func EqualFunc[T comparable](a, b Set[T], eq func(a, b T) bool) bool {
+	return IsSubsetFunc(a, b, eq) && IsSubsetFunc(b, a, func(v, u T) bool { return eq(u, v) })
+}
+
+// IsDisjoint reports whether a and b have no element in common. It looks up
+// the elements of the smaller set in the larger one and stops at the first
+// common element, without building the intersection.
+func IsDisjoint[T comparable](a, b Set[T]) bool {
+	switch a.(type) {
+	case *threadUnsafeSet[T]:
+		if _, ok := b.(*threadUnsafeSet[T]); ok {
+			return !a.ContainsAnyElement(b)
+		}
+	case *threadSafeSet[T]:
+		if _, ok := b.(*threadSafeSet[T]); ok {
+			return !a.ContainsAnyElement(b)
+		}
+	}
+
+	small, large := a, b
+	if b.Cardinality() < a.Cardinality() {
+		small, large = b, a
+	}
+	// the elements are copied so that no lock of small is held while large
+	// is queried, as both may be the same set
+	for _, v := range small.ToSlice() {
+		if large.ContainsOne(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"strings"
+	"testing"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func Test_EqualBy(t *testing.T) {
+	byID := func(u user) int { return u.ID }
+
+	a := NewSet(user{1, "alice"}, user{2, "bob"})
+	b := NewSet(user{2, "Bob"}, user{1, "Alice"})
+	if !EqualBy(a, b, byID) {
+		t.Error("the sets should be equal by ID")
+	}
+	if a.Equal(b) {
+		t.Error("the sets should not be equal")
+	}
+
+	if EqualBy(a, NewSet(user{1, "alice"}), byID) {
+		t.Error("a missing key should make the sets different")
+	}
+	if EqualBy(NewSet(user{1, "alice"}), a, byID) {
+		t.Error("an extra key should make the sets different")
+	}
+	if EqualBy(a, NewSet(user{1, "alice"}, user{3, "carol"}), byID) {
+		t.Error("a different key should make the sets different")
+	}
+
+	// duplicate keys are collapsed
+	c := NewThreadUnsafeSet(user{1, "alice"}, user{1, "Alice"}, user{2, "bob"})
+	if !EqualBy(a, c, byID) || !EqualBy(c, a, byID) {
+		t.Error("the sets should be equal by ID despite duplicate keys")
+	}
+	if !EqualBy(NewSet[user](), NewThreadUnsafeSet[user](), byID) {
+		t.Error("empty sets should be equal")
+	}
+}
+
+func Test_IsSubsetFunc(t *testing.T) {
+	sameID := func(id int, u user) bool { return id == u.ID }
+
+	users := NewSet(user{1, "alice"}, user{2, "bob"}, user{3, "carol"})
+	if !IsSubsetFunc(NewSet(1, 3), users, sameID) {
+		t.Error("1 and 3 should match users")
+	}
+	if IsSubsetFunc(NewThreadUnsafeSet(1, 4), users, sameID) {
+		t.Error("4 should not match any user")
+	}
+	if !IsSubsetFunc(NewSet[int](), NewSet[user](), sameID) {
+		t.Error("the empty set should be a subset of any set")
+	}
+	if IsSubsetFunc(NewSet(1), NewSet[user](), sameID) {
+		t.Error("no set but the empty one should be a subset of the empty set")
+	}
+}
+
+func Test_EqualFunc(t *testing.T) {
+	eq := func(a, b string) bool { return strings.EqualFold(a, b) }
+
+	if !EqualFunc(NewSet("Go", "RUST"), NewSet("go", "rust"), eq) {
+		t.Error("the sets should be equal case-insensitively")
+	}
+	if !EqualFunc(NewSet("a", "A"), NewThreadUnsafeSet("a"), eq) {
+		t.Error("elements matching the same element should be collapsed")
+	}
+	if EqualFunc(NewSet("a"), NewSet("a", "b"), eq) || EqualFunc(NewSet("a", "b"), NewSet("a"), eq) {
+		t.Error("b should not match any element")
+	}
+	if !EqualFunc(NewSet[string](), NewSet[string](), eq) {
+		t.Error("empty sets should be equal")
+	}
+
+	var calls [][2]int
+	EqualFunc(NewSet(1), NewSet(2), func(a, b int) bool {
+		calls = append(calls, [2]int{a, b})
+		return true
+	})
+	for _, c := range calls {
+		if c != [2]int{1, 2} {
+			t.Errorf("eq should be called with an element of a first, got %v", c)
+		}
+	}
+}
+
+func Test_IsDisjoint(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3)
+		for _, tt := range []struct {
+			b    Set[int]
+			want bool
+		}{
+			{ctor(4, 5), true},
+			{ctor(3, 4, 5, 6, 7), false},
+			{ctor(), true},
+			{a, false},
+			{foreignSet[int]{ctor(0, 2)}, false},
+			{NewSortedSet(4, 5, 6), true},
+		} {
+			if got := IsDisjoint(a, tt.b); got != tt.want {
+				t.Errorf("IsDisjoint(%v, %v): expected %v, got: %v", a, tt.b, tt.want, got)
+			}
+			if got := IsDisjoint(tt.b, a); got != tt.want {
+				t.Errorf("IsDisjoint(%v, %v): expected %v, got: %v", tt.b, a, tt.want, got)
+			}
+		}
+		if !IsDisjoint(ctor(), ctor()) {
+			t.Error("empty sets should be disjoint")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
@@ -0,0 +1,100 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"reflect"
+	"sync"
+)
+
+// maxScratchSize bounds the size of the scratch maps kept for reuse, so
+// that one huge call doesn't pin its memory forever.
+const maxScratchSize = 1 << 16
+
+// scratchPools holds a *sync.Pool of scratch maps per key type.
+var scratchPools sync.Map
+
+func scratchPool[K comparable]() *sync.Pool {
+	typ := reflect.TypeOf((*K)(nil)).Elem()
+	if p, ok := scratchPools.Load(typ); ok {
+		return p.(*sync.Pool)
+	}
+	p, _ := scratchPools.LoadOrStore(typ, &sync.Pool{
+		New: func() any {
+			return make(map[K]struct{})
+		},
+	})
+	return p.(*sync.Pool)
+}
+
+// getScratch returns an empty map from the pool, to be released with
+// putScratch.
+func getScratch[K comparable]() map[K]struct{} {
+	return scratchPool[K]().Get().(map[K]struct{})
+}
+
+func putScratch[K comparable](m map[K]struct{}) {
+	if len(m) > maxScratchSize {
+		return
+	}
+	for k := range m {
+		delete(m, k)
+	}
+	scratchPool[K]().Put(m)
+}
+
+// Dedup removes the duplicate elements of in, in place, keeping the first
+// occurrence of every element in its original order, and returns the
+// shortened slice. The elements past the end of the returned slice are
+// zeroed. Scratch memory is pooled, so Dedup doesn't allocate in steady
+// state.
+func Dedup[T comparable](in []T) []T {
+	return DedupFunc(in, func(v T) T { return v })
+}
+
+// DedupFunc is like Dedup but considers elements with the same key as
+// duplicates, for instance to unique records by ID.
+func DedupFunc[T any, K comparable](in []T, key func(T) K) []T {
+	seen := getScratch[K]()
+	defer putScratch(seen)
+
+	n := 0
+	for _, v := range in {
+		k := key(v)
+		if _, found := seen[k]; found {
+			continue
+		}
+		seen[k] = struct{}{}
+		in[n] = v
+		n++
+	}
+
+	var zero T
+	for i := n; i < len(in); i++ {
+		in[i] = zero
+	}
+	return in[:n]
+}
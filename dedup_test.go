@@ -0,0 +1,77 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Dedup(t *testing.T) {
+	in := []string{"b", "a", "b", "c", "a", "d"}
+	out := Dedup(in)
+
+	if expected := []string{"b", "a", "c", "d"}; !reflect.DeepEqual(out, expected) {
+		t.Errorf("expected %v, got %v", expected, out)
+	}
+	if &out[0] != &in[0] {
+		t.Error("Dedup should work in place")
+	}
+	if in[4] != "" || in[5] != "" {
+		t.Errorf("the tail should be zeroed, got %q", in[4:])
+	}
+
+	if out := Dedup([]int(nil)); len(out) != 0 {
+		t.Errorf("expected an empty slice, got %v", out)
+	}
+}
+
+func Test_DedupFunc(t *testing.T) {
+	in := []string{"Go", "rust", "GO", "Rust", "zig"}
+	out := DedupFunc(in, strings.ToLower)
+
+	if expected := []string{"Go", "rust", "zig"}; !reflect.DeepEqual(out, expected) {
+		t.Errorf("expected %v, got %v", expected, out)
+	}
+}
+
+func Test_DedupAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items at random under the race detector")
+	}
+
+	in := nrand(100)
+	buf := make([]int, 0, 2*len(in))
+	Dedup(append(append(buf, in...), in...))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Dedup(append(append(buf[:0], in...), in...))
+	})
+	if allocs > 0 {
+		t.Errorf("Dedup should reuse its scratch memory, got %v allocations", allocs)
+	}
+}
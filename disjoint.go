@@ -0,0 +1,191 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+)
+
+// DisjointSet partitions elements into disjoint sets that can be merged,
+// the union-find structure behind connected components and equivalence
+// classes: Union merges the sets of two elements and Find returns the
+// representative of the set of an element, both in nearly constant
+// amortized time. A DisjointSet is safe for concurrent use.
+type DisjointSet[T comparable] struct {
+	mu     sync.Mutex
+	parent map[T]T
+	// size holds the number of elements of the sets, by representative.
+	size map[T]int
+}
+
+// NewDisjointSet creates and returns a new DisjointSet with every given
+// element in a set of its own.
+func NewDisjointSet[T comparable](vs ...T) *DisjointSet[T] {
+	d := &DisjointSet[T]{
+		parent: make(map[T]T, len(vs)),
+		size:   make(map[T]int, len(vs)),
+	}
+	for _, v := range vs {
+		d.add(v)
+	}
+	return d
+}
+
+// Add adds an element in a set of its own, and returns whether it was
+// added. Elements that are already present keep their set.
+func (d *DisjointSet[T]) Add(v T) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.add(v)
+}
+
+// add must be called with the lock held.
+func (d *DisjointSet[T]) add(v T) bool {
+	if _, found := d.parent[v]; found {
+		return false
+	}
+	d.parent[v] = v
+	d.size[v] = 1
+	return true
+}
+
+// find returns the representative of v, which must be present, halving the
+// path to it along the way. It must be called with the lock held.
+func (d *DisjointSet[T]) find(v T) T {
+	for {
+		p := d.parent[v]
+		if p == v {
+			return v
+		}
+		gp := d.parent[p]
+		d.parent[v] = gp
+		v = gp
+	}
+}
+
+// Union merges the sets of a and b, adding them first if they are not
+// present. Returns whether they were in different sets.
+func (d *DisjointSet[T]) Union(a, b T) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.add(a)
+	d.add(b)
+	ra, rb := d.find(a), d.find(b)
+	if ra == rb {
+		return false
+	}
+	// the smaller set is attached to the larger one, which keeps the
+	// paths short
+	if d.size[ra] < d.size[rb] {
+		ra, rb = rb, ra
+	}
+	d.parent[rb] = ra
+	d.size[ra] += d.size[rb]
+	delete(d.size, rb)
+	return true
+}
+
+// Find returns the representative of the set of v, which is the same for
+// all the elements of a set until it is merged with another one, and
+// whether v is present.
+func (d *DisjointSet[T]) Find(v T) (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, found := d.parent[v]; !found {
+		var zero T
+		return zero, false
+	}
+	return d.find(v), true
+}
+
+// Connected returns whether a and b are present and in the same set.
+func (d *DisjointSet[T]) Connected(a, b T) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, foundA := d.parent[a]
+	_, foundB := d.parent[b]
+	return foundA && foundB && d.find(a) == d.find(b)
+}
+
+// Cardinality returns the number of elements, in all sets.
+func (d *DisjointSet[T]) Cardinality() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.parent)
+}
+
+// Count returns the number of disjoint sets.
+func (d *DisjointSet[T]) Count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.size)
+}
+
+// SetOf returns the elements of the set of v as a new thread-safe Set, or
+// nil if v is not present.
+func (d *DisjointSet[T]) SetOf(v T) Set[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, found := d.parent[v]; !found {
+		return nil
+	}
+	root := d.find(v)
+	set := newThreadSafeSetWithSize[T](d.size[root])
+	for elem := range d.parent {
+		if d.find(elem) == root {
+			set.uss.add(elem)
+		}
+	}
+	return set
+}
+
+// Sets returns the disjoint sets as new thread-safe Sets, in no particular
+// order.
+func (d *DisjointSet[T]) Sets() []Set[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	byRoot := make(map[T]*threadSafeSet[T], len(d.size))
+	for root, size := range d.size {
+		byRoot[root] = newThreadSafeSetWithSize[T](size)
+	}
+	for elem := range d.parent {
+		byRoot[d.find(elem)].uss.add(elem)
+	}
+
+	sets := make([]Set[T], 0, len(byRoot))
+	for _, set := range byRoot {
+		sets = append(sets, set)
+	}
+	return sets
+}
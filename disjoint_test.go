@@ -0,0 +1,96 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_DisjointSet(t *testing.T) {
+	d := NewDisjointSet("a", "b", "c", "d", "e")
+
+	if !d.Union("a", "b") || !d.Union("c", "d") || !d.Union("b", "d") {
+		t.Error("Union of different sets should report true")
+	}
+	if d.Union("a", "c") {
+		t.Error("Union of the same set should report false")
+	}
+	if !d.Union("f", "g") {
+		t.Error("Union of missing elements should add them")
+	}
+
+	if d.Cardinality() != 7 || d.Count() != 3 {
+		t.Errorf("expected 7 elements in 3 sets, got %d in %d", d.Cardinality(), d.Count())
+	}
+	ra, _ := d.Find("a")
+	rd, _ := d.Find("d")
+	if ra != rd || !d.Connected("a", "d") || d.Connected("a", "e") {
+		t.Error("expected a and d to be in the same set, apart from e")
+	}
+	if _, found := d.Find("z"); found || d.Connected("z", "z") {
+		t.Error("missing elements should not be found")
+	}
+	if d.Add("a") || !d.Add("h") {
+		t.Error("Add should report whether the element was added")
+	}
+
+	if s := d.SetOf("c"); !s.Equal(NewSet("a", "b", "c", "d")) {
+		t.Errorf("expected the set of c to be {a, b, c, d}, got %v", s)
+	}
+	if d.SetOf("z") != nil {
+		t.Error("expected no set for a missing element")
+	}
+
+	want := map[string]Set[string]{
+		"a": NewSet("a", "b", "c", "d"),
+		"e": NewSet("e"),
+		"f": NewSet("f", "g"),
+		"h": NewSet("h"),
+	}
+	sets := d.Sets()
+	if len(sets) != len(want) {
+		t.Fatalf("expected %d sets, got %v", len(want), sets)
+	}
+	for _, s := range sets {
+		var found bool
+		for _, w := range want {
+			found = found || s.Equal(w)
+		}
+		if !found {
+			t.Errorf("unexpected set %v", s)
+		}
+	}
+}
+
+func Test_DisjointSetLarge(t *testing.T) {
+	d := NewDisjointSet[int]()
+	for i := 1; i < N; i++ {
+		d.Union(i-1, i)
+	}
+	if d.Count() != 1 || !d.Connected(0, N-1) {
+		t.Errorf("expected a single set, got %d", d.Count())
+	}
+}
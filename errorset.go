@@ -0,0 +1,70 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"strconv"
+)
+
+// NewErrorSet creates and returns a new empty set collecting unique errors,
+// for instance the errors returned by the branches of a fan-out operation.
+//
+// A plain Set[error] compares errors as interfaces, so two errors with the
+// same message created by separate calls to errors.New or fmt.Errorf are
+// different elements, while errors of non-comparable dynamic types make it
+// panic. An error set instead identifies errors by their message, or, for
+// the errors matching one of the given sentinels with errors.Is, by that
+// sentinel:
+//
+//	errs := mapset.NewErrorSet(os.ErrNotExist)
+//	errs.Add(fmt.Errorf("open a: %w", os.ErrNotExist))
+//	errs.Add(fmt.Errorf("open b: %w", os.ErrNotExist)) // same as the first one
+//	errs.Add(errors.New("timeout"))
+//	errs.Add(errors.New("timeout")) // same message as the previous one
+//
+// The first error added for a key is kept. Nil errors are all the same
+// element.
+func NewErrorSet(sentinels ...error) *KeyedSet[error, string] {
+	sentinels = append([]error(nil), sentinels...)
+	return NewKeyedSet(func(err error) string {
+		return errorKey(err, sentinels)
+	})
+}
+
+// errorKey returns the key of err in an error set. The keys of sentinels
+// start with a NUL byte, which error messages normally don't.
+func errorKey(err error, sentinels []error) string {
+	if err == nil {
+		return "\x00nil"
+	}
+	for i, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			return "\x00" + strconv.Itoa(i)
+		}
+	}
+	return err.Error()
+}
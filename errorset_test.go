@@ -0,0 +1,91 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// sliceError is an error of a non-comparable type.
+type sliceError []string
+
+func (e sliceError) Error() string {
+	return fmt.Sprint([]string(e))
+}
+
+func Test_NewErrorSet(t *testing.T) {
+	errs := NewErrorSet(os.ErrNotExist, io.EOF)
+
+	first := fmt.Errorf("open a: %w", os.ErrNotExist)
+	errs.Append(
+		first,
+		fmt.Errorf("open b: %w", os.ErrNotExist),
+		errors.New("timeout"),
+		errors.New("timeout"),
+		sliceError{"a"},
+		sliceError{"a"},
+		nil,
+		nil,
+		io.EOF,
+	)
+
+	if errs.Cardinality() != 5 {
+		t.Errorf("expected 5 unique errors, got %v", errs)
+	}
+	// errors matching a sentinel are keyed by it, so any of them is found
+	if !errs.Contains(fmt.Errorf("stat c: %w", os.ErrNotExist), io.EOF, errors.New("timeout"), nil) {
+		t.Error("unexpected membership")
+	}
+	if errs.Contains(errors.New("other")) {
+		t.Error("an error with another message should not be in the set")
+	}
+
+	var kept error
+	errs.Each(func(err error) bool {
+		if errors.Is(err, os.ErrNotExist) {
+			kept = err
+		}
+		return false
+	})
+	if kept != first {
+		t.Errorf("expected the first error matching the sentinel to be kept, got %v", kept)
+	}
+}
+
+func ExampleNewErrorSet() {
+	errs := NewErrorSet(os.ErrNotExist)
+	for _, name := range []string{"a", "b"} {
+		errs.Add(fmt.Errorf("open %s: %w", name, os.ErrNotExist))
+		errs.Add(errors.New("timeout"))
+	}
+
+	fmt.Println(errs.Cardinality())
+	// Output: 2
+}
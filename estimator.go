@@ -0,0 +1,218 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// DefaultEstimatorPrecision is the precision of the estimators created by
+// NewCardinalityEstimator with a zero precision: 2^14 registers, 16KiB,
+// for a standard error of about 0.8%.
+const DefaultEstimatorPrecision = 14
+
+// ErrInvalidEstimator is returned when decoding data that is not an
+// estimator encoded by CardinalityEstimator.MarshalBinary.
+var ErrInvalidEstimator = errors.New("mapset: invalid cardinality estimator")
+
+const estimatorVersion = 1
+
+// CardinalityEstimator estimates the number of distinct elements added to
+// it with HyperLogLog, in constant memory, so that distinct elements can be
+// counted in unbounded streams where holding the full set isn't feasible.
+// With precision p it uses 2^p bytes and has a standard error of about
+// 1.04/sqrt(2^p). A CardinalityEstimator is safe for concurrent use.
+//
+// Elements are hashed by their JSON encoding, so estimators fed in
+// different processes can be merged, provided the elements encode to JSON
+// in a deterministic way.
+type CardinalityEstimator[T comparable] struct {
+	mu        sync.Mutex
+	p         uint8
+	registers []uint8
+}
+
+// NewCardinalityEstimator creates and returns an empty estimator with
+// 2^precision registers, or DefaultEstimatorPrecision if precision is 0.
+// It panics if precision is not between 4 and 18.
+func NewCardinalityEstimator[T comparable](precision int) *CardinalityEstimator[T] {
+	if precision == 0 {
+		precision = DefaultEstimatorPrecision
+	}
+	if precision < 4 || precision > 18 {
+		panic(fmt.Sprintf("mapset: estimator precision %d out of range [4, 18]", precision))
+	}
+	return &CardinalityEstimator[T]{p: uint8(precision), registers: make([]uint8, 1<<precision)}
+}
+
+// Add records an element.
+func (e *CardinalityEstimator[T]) Add(v T) {
+	h := mix64(elementHash(v))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.add(h)
+}
+
+// AddSet records all the elements of s.
+func (e *CardinalityEstimator[T]) AddSet(s Set[T]) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s.Each(func(v T) bool {
+		e.add(mix64(elementHash(v)))
+		return false
+	})
+}
+
+// add must be called with the lock held. The first p bits of the hash
+// select a register, which keeps the highest rank, the position of the
+// first set bit, seen in the remaining bits.
+func (e *CardinalityEstimator[T]) add(h uint64) {
+	idx := h >> (64 - e.p)
+	rank := uint8(bits.LeadingZeros64(h<<e.p|1<<(e.p-1))) + 1
+	if rank > e.registers[idx] {
+		e.registers[idx] = rank
+	}
+}
+
+// Estimate returns the estimated number of distinct elements recorded.
+func (e *CardinalityEstimator[T]) Estimate() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	m := float64(len(e.registers))
+	sum, zeros := 0.0, 0
+	for _, r := range e.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	est := estimatorAlpha(len(e.registers)) * m * m / sum
+	// small cardinalities are better estimated by linear counting
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
+
+func estimatorAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge records the elements recorded by other, so that the estimate is
+// the one of the union of both streams. Both estimators must have the same
+// precision.
+func (e *CardinalityEstimator[T]) Merge(other *CardinalityEstimator[T]) error {
+	if e == other {
+		return nil
+	}
+	other.mu.Lock()
+	registers := append([]uint8(nil), other.registers...)
+	p := other.p
+	other.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if p != e.p {
+		return fmt.Errorf("mapset: cannot merge estimators of precision %d and %d", e.p, p)
+	}
+	for i, r := range registers {
+		if r > e.registers[i] {
+			e.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Reset forgets all the recorded elements.
+func (e *CardinalityEstimator[T]) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.registers {
+		e.registers[i] = 0
+	}
+}
+
+// Precision returns the precision of the estimator.
+func (e *CardinalityEstimator[T]) Precision() int {
+	return int(e.p)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so that estimators can
+// be persisted or sent to another process and merged there.
+func (e *CardinalityEstimator[T]) MarshalBinary() ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b := make([]byte, 0, 2+len(e.registers))
+	b = append(b, estimatorVersion, e.p)
+	return append(b, e.registers...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the
+// state of the estimator, including its precision, with the decoded one.
+func (e *CardinalityEstimator[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != estimatorVersion || data[1] < 4 || data[1] > 18 || len(data) != 2+1<<data[1] {
+		return ErrInvalidEstimator
+	}
+	for _, r := range data[2:] {
+		if r > 64-data[1]+1 {
+			return ErrInvalidEstimator
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.p = data[1]
+	e.registers = append([]uint8(nil), data[2:]...)
+	return nil
+}
+
+// mix64 is the finalizer of MurmurHash3, it spreads the bits of FNV hashes
+// of short inputs over the whole word.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
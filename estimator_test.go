@@ -0,0 +1,102 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_CardinalityEstimator(t *testing.T) {
+	e := NewCardinalityEstimator[int](0)
+	if e.Estimate() != 0 {
+		t.Errorf("expected an empty estimator to estimate 0, got %d", e.Estimate())
+	}
+
+	for i := 0; i < 10; i++ {
+		e.Add(i)
+		e.Add(i)
+	}
+	if got := e.Estimate(); got != 10 {
+		t.Errorf("expected small cardinalities to be exact, got %d", got)
+	}
+
+	const n = 100000
+	for i := 0; i < n; i++ {
+		e.Add(i)
+	}
+	// four standard errors of 0.8%
+	if got := e.Estimate(); math.Abs(float64(got)-n)/n > 0.033 {
+		t.Errorf("expected an estimate close to %d, got %d", n, got)
+	}
+
+	e.Reset()
+	if e.Estimate() != 0 {
+		t.Errorf("expected Reset to forget all elements, got %d", e.Estimate())
+	}
+}
+
+func Test_CardinalityEstimatorMerge(t *testing.T) {
+	a, b := NewCardinalityEstimator[string](10), NewCardinalityEstimator[string](10)
+	a.AddSet(NewSet("a", "b", "c"))
+	b.AddSet(NewThreadUnsafeSet("c", "d"))
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if got := a.Estimate(); got != 4 {
+		t.Errorf("expected the union to hold 4 elements, got %d", got)
+	}
+	if err := a.Merge(NewCardinalityEstimator[string](12)); err == nil {
+		t.Error("expected an error merging estimators of different precisions")
+	}
+}
+
+func Test_CardinalityEstimatorBinary(t *testing.T) {
+	e := NewCardinalityEstimator[int](8)
+	for _, v := range nrand(N) {
+		e.Add(v)
+	}
+	data, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	var decoded CardinalityEstimator[int]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if decoded.Precision() != 8 || decoded.Estimate() != e.Estimate() {
+		t.Errorf("expected the decoded estimator to match, got precision %d and estimate %d", decoded.Precision(), decoded.Estimate())
+	}
+
+	for _, bad := range [][]byte{nil, {estimatorVersion, 3}, data[:len(data)-1], append([]byte{estimatorVersion, 4}, make([]byte, 15)...)} {
+		if err := decoded.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidEstimator) {
+			t.Errorf("Expected %v, got: %v", ErrInvalidEstimator, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// ExpiringSet is a thread-safe set whose members expire after a time to
+// live. Every member has its own deadline, so for instance bans of varying
+// durations can be kept in the same set.
+//
+// Expired members are never reported; they are removed lazily, in
+// deadline order, by the operations that modify the set or count its
+// members, and can be removed eagerly with RemoveExpired.
+type ExpiringSet[T comparable] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	members map[T]*expiringEntry[T]
+	// deadlines holds the members that expire, soonest first.
+	deadlines expiringHeap[T]
+	now       func() time.Time
+}
+
+type expiringEntry[T comparable] struct {
+	value    T
+	deadline time.Time
+	// index of the entry in deadlines, -1 if it never expires
+	index int
+}
+
+// NewExpiringSet creates and returns a new empty ExpiringSet whose members
+// expire ttl after being added with Add. A non-positive ttl means that
+// members added with Add never expire.
+func NewExpiringSet[T comparable](ttl time.Duration) *ExpiringSet[T] {
+	return &ExpiringSet[T]{
+		ttl:     ttl,
+		members: make(map[T]*expiringEntry[T]),
+		now:     time.Now,
+	}
+}
+
+// Add adds an element to the set with the default time to live of the
+// set, replacing the deadline of the element if it is already present.
+// Returns whether the element was added.
+func (s *ExpiringSet[T]) Add(v T) bool {
+	return s.AddWithTTL(v, s.ttl)
+}
+
+// AddWithTTL adds an element to the set that expires after d, replacing
+// the deadline of the element if it is already present. A non-positive d
+// means that the element never expires. Returns whether the element was
+// added.
+func (s *ExpiringSet[T]) AddWithTTL(v T, d time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+
+	e, found := s.members[v]
+	if !found {
+		e = &expiringEntry[T]{value: v, index: -1}
+		s.members[v] = e
+	}
+
+	if d <= 0 {
+		e.deadline = time.Time{}
+		if e.index >= 0 {
+			heap.Remove(&s.deadlines, e.index)
+		}
+		return !found
+	}
+
+	e.deadline = now.Add(d)
+	if e.index >= 0 {
+		heap.Fix(&s.deadlines, e.index)
+	} else {
+		heap.Push(&s.deadlines, e)
+	}
+	return !found
+}
+
+// Contains returns whether the given element is in the set and hasn't
+// expired.
+func (s *ExpiringSet[T]) Contains(v T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, found := s.members[v]
+	return found && s.alive(e, s.now())
+}
+
+// ExpiresAt returns when the given element expires, and whether it is in
+// the set. The returned time is zero for elements that never expire.
+func (s *ExpiringSet[T]) ExpiresAt(v T) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, found := s.members[v]
+	if !found || !s.alive(e, s.now()) {
+		return time.Time{}, false
+	}
+	return e.deadline, true
+}
+
+// Remove removes a single element from the set. Returns whether the element
+// was present and hadn't expired.
+func (s *ExpiringSet[T]) Remove(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(s.now())
+	e, found := s.members[v]
+	if found {
+		s.remove(e)
+	}
+	return found
+}
+
+// RemoveExpired removes the expired members of the set and returns how
+// many were removed.
+func (s *ExpiringSet[T]) RemoveExpired() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.expire(s.now())
+}
+
+// Cardinality returns the number of elements in the set that haven't
+// expired.
+func (s *ExpiringSet[T]) Cardinality() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(s.now())
+	return len(s.members)
+}
+
+// ToSet returns the elements of the set that haven't expired as a new
+// thread-safe Set.
+func (s *ExpiringSet[T]) ToSet() Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(s.now())
+	set := newThreadSafeSetWithSize[T](len(s.members))
+	for v := range s.members {
+		set.uss.add(v)
+	}
+	return set
+}
+
+// alive must be called with the lock held.
+func (s *ExpiringSet[T]) alive(e *expiringEntry[T], now time.Time) bool {
+	return e.index < 0 || now.Before(e.deadline)
+}
+
+// expire removes the members whose deadline passed, it must be called with
+// the write lock held.
+func (s *ExpiringSet[T]) expire(now time.Time) int {
+	n := 0
+	for len(s.deadlines) > 0 && !now.Before(s.deadlines[0].deadline) {
+		s.remove(s.deadlines[0])
+		n++
+	}
+	return n
+}
+
+// remove must be called with the write lock held.
+func (s *ExpiringSet[T]) remove(e *expiringEntry[T]) {
+	if e.index >= 0 {
+		heap.Remove(&s.deadlines, e.index)
+	}
+	delete(s.members, e.value)
+}
+
+// expiringHeap implements heap.Interface, ordering entries by deadline.
+type expiringHeap[T comparable] []*expiringEntry[T]
+
+func (h expiringHeap[T]) Len() int { return len(h) }
+
+func (h expiringHeap[T]) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h expiringHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiringHeap[T]) Push(x any) {
+	e := x.(*expiringEntry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiringHeap[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,126 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_ExpiringSet(t *testing.T) {
+	s := NewExpiringSet[string](time.Minute)
+	now, advance := manualClock(time.Unix(0, 0))
+	s.now = now
+
+	s.Add("spammer")
+	s.AddWithTTL("troll", time.Hour)
+	s.AddWithTTL("admin", 0)
+
+	if deadline, ok := s.ExpiresAt("troll"); !ok || !deadline.Equal(time.Unix(0, 0).Add(time.Hour)) {
+		t.Errorf("unexpected deadline for troll: %v", deadline)
+	}
+	if deadline, ok := s.ExpiresAt("admin"); !ok || !deadline.IsZero() {
+		t.Errorf("admin should never expire, got deadline %v", deadline)
+	}
+
+	advance(time.Minute)
+	if s.Contains("spammer") {
+		t.Error("spammer should have expired")
+	}
+	if !s.Contains("troll") || !s.Contains("admin") {
+		t.Error("troll and admin should not have expired")
+	}
+	if n := s.Cardinality(); n != 2 {
+		t.Errorf("expected 2 elements, got %d", n)
+	}
+
+	// re-adding replaces the deadline
+	if s.AddWithTTL("troll", time.Second) {
+		t.Error("re-adding a present element should report false")
+	}
+	advance(time.Second)
+	if n := s.RemoveExpired(); n != 1 {
+		t.Errorf("expected troll to be removed, got %d removals", n)
+	}
+	if !s.ToSet().Equal(NewSet("admin")) {
+		t.Errorf("expected only admin to remain, got %v", s.ToSet())
+	}
+
+	// an expiring element can be made permanent and the other way round
+	s.AddWithTTL("admin", time.Second)
+	s.AddWithTTL("spammer", time.Second)
+	s.AddWithTTL("spammer", 0)
+	advance(time.Hour)
+	if !s.ToSet().Equal(NewSet("spammer")) {
+		t.Errorf("expected only spammer to remain, got %v", s.ToSet())
+	}
+	if !s.Remove("spammer") || s.Remove("spammer") {
+		t.Error("Remove should report whether the element was present")
+	}
+}
+
+func Test_ExpiringSetOrder(t *testing.T) {
+	s := NewExpiringSet[int](0)
+	now, advance := manualClock(time.Unix(0, 0))
+	s.now = now
+
+	for i := 0; i < N; i++ {
+		s.AddWithTTL(i, time.Duration(N-i)*time.Second)
+	}
+	for i := N - 1; i >= 0; i-- {
+		advance(time.Second)
+		if n := s.RemoveExpired(); n != 1 {
+			t.Fatalf("expected one removal per second, got %d", n)
+		}
+		if s.Contains(i) {
+			t.Fatalf("%d should have expired", i)
+		}
+		if i > 0 && !s.Contains(i-1) {
+			t.Fatalf("%d should not have expired yet", i-1)
+		}
+	}
+}
+
+func Test_ExpiringSetConcurrent(t *testing.T) {
+	s := NewExpiringSet[int](time.Hour)
+
+	var wg sync.WaitGroup
+	for _, v := range nrand(N) {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			s.AddWithTTL(v, time.Duration(v%3)*time.Hour)
+			s.Contains(v)
+			s.Cardinality()
+		}(v)
+	}
+	wg.Wait()
+
+	if n := s.RemoveExpired(); n != 0 {
+		t.Errorf("no element should have expired, got %d removals", n)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NewSetFromField creates and returns a new set with the values of the
+// exported field named fieldName of every item, which must be a struct or
+// a pointer to a struct. Operations on the resulting set are thread-safe.
+//
+//	ids := mapset.NewSetFromField[int](users, "ID")
+//
+// Nil pointer items, like items whose field is promoted through a nil
+// embedded pointer, contribute the zero value of K. NewSetFromField panics
+// if the field doesn't exist, is unexported or its type isn't assignable to
+// K. Prefer NewSetFromFunc when the accessor can
+// be written out, it is checked at compile time and doesn't use reflection.
+func NewSetFromField[K comparable, T any](items []T, fieldName string) Set[K] {
+	return NewSetFromFunc(items, fieldAccessor[T, K](fieldName))
+}
+
+// NewThreadUnsafeSetFromField creates and returns a new set with the values
+// of the exported field named fieldName of every item, see NewSetFromField.
+// Operations on the resulting set are not thread-safe.
+func NewThreadUnsafeSetFromField[K comparable, T any](items []T, fieldName string) Set[K] {
+	return NewThreadUnsafeSetFromFunc(items, fieldAccessor[T, K](fieldName))
+}
+
+// fieldAccessor returns a function reading the field named fieldName of a T
+// as a K. Type checking happens once, up front.
+func fieldAccessor[T any, K comparable](fieldName string) func(T) K {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mapset: %v is not a struct type", typ))
+	}
+
+	field, found := typ.FieldByName(fieldName)
+	if !found {
+		panic(fmt.Sprintf("mapset: %v has no field %s", typ, fieldName))
+	}
+	if field.PkgPath != "" {
+		panic(fmt.Sprintf("mapset: field %s of %v is unexported", fieldName, typ))
+	}
+	keyType := reflect.TypeOf((*K)(nil)).Elem()
+	if !field.Type.AssignableTo(keyType) {
+		panic(fmt.Sprintf("mapset: field %s of %v has type %v, not assignable to %v", fieldName, typ, field.Type, keyType))
+	}
+
+	return func(item T) K {
+		var key K
+		v := reflect.ValueOf(item)
+		if isPtr {
+			if v.IsNil() {
+				return key
+			}
+			v = v.Elem()
+		}
+
+		f, err := v.FieldByIndexErr(field.Index)
+		if err != nil {
+			// promoted through a nil embedded pointer
+			return key
+		}
+		reflect.ValueOf(&key).Elem().Set(f)
+		return key
+	}
+}
+
+// NewSetIgnoring creates and returns a new empty keyed set of structs, or
+// pointers to structs, where two elements are the same when all of their
+// fields but the ignored ones are equal. It deduplicates events or records
+// that differ only by timestamps, trace IDs and the like, without writing a
+// key function for every type:
+//
+//	events := mapset.NewSetIgnoring[Event]("Timestamp", "TraceID")
+//
+// Pointer fields are compared by address, like ==. Other fields, including
+// unexported and non-comparable ones, are compared by their %#v
+// representation, so nested structs are compared as a whole. NewSetIgnoring panics if T isn't a struct or a
+// pointer to a struct, or if one of the ignored fields doesn't exist.
+func NewSetIgnoring[T any](fields ...string) *KeyedSet[T, string] {
+	return NewKeyedSet(ignoringKey[T](fields))
+}
+
+// ignoringKey returns a function formatting the fields of a T, but the
+// ignored ones, as a key. Type checking happens once, up front.
+func ignoringKey[T any](ignored []string) func(T) string {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mapset: %v is not a struct type", typ))
+	}
+
+	skip := make(map[int]bool, len(ignored))
+	for _, name := range ignored {
+		field, found := typ.FieldByName(name)
+		if !found || len(field.Index) != 1 {
+			panic(fmt.Sprintf("mapset: %v has no field %s", typ, name))
+		}
+		skip[field.Index[0]] = true
+	}
+
+	return func(item T) string {
+		v := reflect.ValueOf(item)
+		if isPtr {
+			if v.IsNil() {
+				return "nil"
+			}
+			v = v.Elem()
+		}
+
+		var key strings.Builder
+		for i := 0; i < v.NumField(); i++ {
+			if skip[i] {
+				continue
+			}
+			f := v.Field(i)
+			if f.Kind() == reflect.Ptr {
+				// %#v would print what a top-level pointer points to
+				fmt.Fprintf(&key, "(%v)(%#x);", f.Type(), f.Pointer())
+				continue
+			}
+			// quoting makes the representations of the fields unambiguous
+			fmt.Fprintf(&key, "%#v;", f)
+		}
+		return key.String()
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+type row struct {
+	ID     int
+	Name   string
+	secret string
+}
+
+func Test_NewSetFromFunc(t *testing.T) {
+	rows := []row{{1, "a", ""}, {2, "b", ""}, {1, "c", ""}}
+	id := func(r row) int { return r.ID }
+
+	if !NewSetFromFunc(rows, id).Equal(NewSet(1, 2)) {
+		t.Error("expected the set of IDs {1, 2}")
+	}
+	if !NewThreadUnsafeSetFromFunc(rows, id).Equal(NewThreadUnsafeSet(1, 2)) {
+		t.Error("expected the set of IDs {1, 2}")
+	}
+}
+
+func Test_NewSetFromField(t *testing.T) {
+	rows := []row{{1, "a", ""}, {2, "b", ""}, {1, "c", ""}}
+
+	if !NewSetFromField[int](rows, "ID").Equal(NewSet(1, 2)) {
+		t.Error("expected the set of IDs {1, 2}")
+	}
+	if !NewThreadUnsafeSetFromField[string](rows, "Name").Equal(NewThreadUnsafeSet("a", "b", "c")) {
+		t.Error("expected the set of names {a, b, c}")
+	}
+
+	ptrs := []*row{&rows[0], &rows[1]}
+	if !NewSetFromField[int](ptrs, "ID").Equal(NewSet(1, 2)) {
+		t.Error("expected the set of IDs {1, 2} from pointers to structs")
+	}
+
+	// nil pointers contribute the zero value, as in NewSetIgnoring
+	if s := NewSetFromField[int]([]*row{nil, &rows[1]}, "ID"); !s.Equal(NewSet(0, 2)) {
+		t.Errorf("expected the set of IDs {0, 2} with a nil pointer, got %v", s)
+	}
+	type wrapped struct {
+		*row
+	}
+	if s := NewSetFromField[string]([]wrapped{{nil}, {&rows[0]}}, "Name"); !s.Equal(NewSet("", "a")) {
+		t.Errorf("expected the set of names {\"\", a} with a nil embedded pointer, got %v", s)
+	}
+}
+
+func Test_NewSetFromFieldPanics(t *testing.T) {
+	rows := []row{{1, "a", ""}}
+	tests := map[string]func(){
+		"missing field": func() { NewSetFromField[int](rows, "Missing") },
+		"unexported":    func() { NewSetFromField[string](rows, "secret") },
+		"wrong type":    func() { NewSetFromField[string](rows, "ID") },
+		"not a struct":  func() { NewSetFromField[int]([]int{1}, "ID") },
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+type event struct {
+	Kind    string
+	Attrs   map[string]string
+	TraceID string
+	at      int64
+}
+
+func Test_NewSetIgnoring(t *testing.T) {
+	s := NewSetIgnoring[event]("TraceID", "at")
+
+	s.Append(
+		event{"login", map[string]string{"user": "a"}, "t1", 1},
+		event{"login", map[string]string{"user": "a"}, "t2", 2},
+		event{"login", map[string]string{"user": "b"}, "t3", 3},
+		event{"logout", map[string]string{"user": "a"}, "t4", 4},
+	)
+	if s.Cardinality() != 3 {
+		t.Errorf("expected 3 events, got %v", s)
+	}
+	if !s.Contains(event{Kind: "logout", Attrs: map[string]string{"user": "a"}}) {
+		t.Error("the ignored fields should not matter")
+	}
+
+	// unexported fields count unless ignored
+	withTime := NewSetIgnoring[*event]("TraceID")
+	withTime.Append(&event{Kind: "a", at: 1}, &event{Kind: "a", at: 2}, &event{Kind: "a", at: 2, TraceID: "x"}, nil, nil)
+	if withTime.Cardinality() != 3 {
+		t.Errorf("expected 3 events, got %v", withTime)
+	}
+
+	// pointer fields are compared by address, not by what they point to
+	type wrapper struct {
+		E *event
+		N int
+	}
+	e1, e2 := &event{Kind: "a"}, &event{Kind: "a"}
+	byAddr := NewSetIgnoring[wrapper]("N")
+	byAddr.Append(wrapper{e1, 1}, wrapper{e1, 2}, wrapper{e2, 3}, wrapper{nil, 4})
+	if byAddr.Cardinality() != 3 {
+		t.Errorf("expected 3 wrappers, got %v", byAddr.Cardinality())
+	}
+}
+
+func Test_NewSetIgnoringPanics(t *testing.T) {
+	tests := map[string]func(){
+		"missing field": func() { NewSetIgnoring[event]("Missing") },
+		"not a struct":  func() { NewSetIgnoring[int]() },
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SetFlag is a flag.Value collecting the values of a repeated or
+// comma-separated flag into a set, so that "-include a -include b,c" holds
+// a, b and c:
+//
+//	include := mapset.NewSetFlag(mapset.NewSet[string](), nil)
+//	flag.Var(include, "include", "comma-separated list of names to include")
+//
+// The parsed elements are added to the set given to NewSetFlag, which keeps
+// any elements it already holds as defaults.
+type SetFlag[T comparable] struct {
+	set   Set[T]
+	parse func(string) (T, error)
+}
+
+var _ flag.Getter = (*SetFlag[string])(nil)
+
+// NewSetFlag returns a SetFlag adding the values of the flag to s, parsing
+// them with parse. A nil parse parses the values like DecodeText: strings
+// are taken as they are, elements implementing encoding.TextUnmarshaler are
+// parsed with it and other elements are decoded as JSON.
+func NewSetFlag[T comparable](s Set[T], parse func(string) (T, error)) *SetFlag[T] {
+	if parse == nil {
+		parse = parseElement[T]
+	}
+	return &SetFlag[T]{set: s, parse: parse}
+}
+
+// Set parses value as a comma-separated list of elements and adds them to
+// the set. Spaces around the elements and empty elements are ignored. No
+// element is added if any of them fails to parse.
+func (f *SetFlag[T]) Set(value string) error {
+	var elems []T
+	for _, item := range strings.Split(value, DefaultTextSeparator) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		elem, err := f.parse(item)
+		if err != nil {
+			return fmt.Errorf("mapset: cannot parse %q: %w", item, err)
+		}
+		elems = append(elems, elem)
+	}
+	f.set.Append(elems...)
+	return nil
+}
+
+// String returns the elements of the set in ascending order, separated by
+// commas. It is safe to call on the zero value, as the flag package does
+// when printing defaults.
+func (f *SetFlag[T]) String() string {
+	if f == nil || f.set == nil {
+		return ""
+	}
+	items := make([]string, 0, f.set.Cardinality())
+	f.set.Each(func(elem T) bool {
+		item, err := elementText(elem)
+		if err != nil {
+			item = fmt.Sprintf("%v", elem)
+		}
+		items = append(items, item)
+		return false
+	})
+	sort.Strings(items)
+	return strings.Join(items, DefaultTextSeparator)
+}
+
+// Get returns the set holding the values of the flag, implementing
+// flag.Getter.
+func (f *SetFlag[T]) Get() interface{} {
+	return f.set
+}
+
+// Values returns the set holding the values of the flag.
+func (f *SetFlag[T]) Values() Set[T] {
+	return f.set
+}
@@ -0,0 +1,90 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func Test_SetFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	include := NewSetFlag(NewSet("x"), nil)
+	fs.Var(include, "include", "names to include")
+
+	if err := fs.Parse([]string{"-include", "a", "-include", "b, c,", "-include=a"}); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !include.Values().Equal(NewSet("x", "a", "b", "c")) {
+		t.Errorf("Expected flag values to be x, a, b and c, got %v", include.Values())
+	}
+	if got := include.String(); got != "a,b,c,x" {
+		t.Errorf("Expected String to be a,b,c,x, got %q", got)
+	}
+	if fs.Lookup("include").Value.(flag.Getter).Get() != include.Values() {
+		t.Error("Expected Get to return the set of the flag")
+	}
+}
+
+func Test_SetFlagParse(t *testing.T) {
+	ports := NewSetFlag(NewThreadUnsafeSet[int](), func(s string) (int, error) {
+		return strconv.Atoi(s)
+	})
+	if err := ports.Set("80,443"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := ports.Set("8080,http"); err == nil {
+		t.Error("Expected an error for an invalid port")
+	} else if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expected the error to wrap strconv.ErrSyntax, got %v", err)
+	}
+	if !ports.Values().Equal(NewThreadUnsafeSet(80, 443)) {
+		t.Errorf("Expected no values to be added by a failed Set, got %v", ports.Values())
+	}
+
+	levels := NewSetFlag(NewSet[level](), nil)
+	if err := levels.Set("!,!!!"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !levels.Values().Equal(NewSet(level(1), level(3))) {
+		t.Errorf("Expected levels to be parsed as text, got %v", levels.Values())
+	}
+}
+
+func Test_SetFlagDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(NewSetFlag(NewSet(2, 1), nil), "n", "numbers")
+
+	if got := fs.Lookup("n").DefValue; got != "1,2" {
+		t.Errorf("Expected the default value to be 1,2, got %q", got)
+	}
+	// PrintDefaults calls String on the zero value of the flag
+	fs.PrintDefaults()
+}
@@ -0,0 +1,105 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"strings"
+	"unicode"
+)
+
+// UnionFold returns a new set with all elements in both string sets, where
+// elements that are equal under Unicode case-folding (see strings.EqualFold)
+// are considered the same element.
+//
+// The original casing is preserved: a representative is taken from a when
+// the element is present there, otherwise from b. When a single set holds
+// several spellings of the same element, the lexicographically smallest one
+// is kept. The returned set uses the same implementation as a.
+func UnionFold(a, b Set[string]) Set[string] {
+	reps := foldRepresentatives(a)
+	for key, rep := range foldRepresentatives(b) {
+		if _, found := reps[key]; !found {
+			reps[key] = rep
+		}
+	}
+
+	union := newSetLike(a, len(reps))
+	for _, rep := range reps {
+		union.Add(rep)
+	}
+	return union
+}
+
+// IntersectFold returns a new set containing only the elements of a that
+// have a case-insensitive match in b (see strings.EqualFold).
+//
+// The original casing of a is preserved. When a holds several spellings of
+// the same element, the lexicographically smallest one is kept. The returned
+// set uses the same implementation as a.
+func IntersectFold(a, b Set[string]) Set[string] {
+	aReps := foldRepresentatives(a)
+	bReps := foldRepresentatives(b)
+
+	intersection := newSetLike(a, len(aReps))
+	for key, rep := range aReps {
+		if _, found := bReps[key]; found {
+			intersection.Add(rep)
+		}
+	}
+	return intersection
+}
+
+// foldRepresentatives maps the case-folded key of every element of s to its
+// lexicographically smallest spelling.
+func foldRepresentatives(s Set[string]) map[string]string {
+	reps := make(map[string]string, s.Cardinality())
+	s.Each(func(elem string) bool {
+		key := foldKey(elem)
+		if rep, found := reps[key]; !found || elem < rep {
+			reps[key] = elem
+		}
+		return false
+	})
+	return reps
+}
+
+// foldKey returns a canonical form of s such that foldKey(a) == foldKey(b)
+// if and only if strings.EqualFold(a, b).
+func foldKey(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		// use the smallest rune of the simple folding orbit as canonical rune
+		canonical := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < canonical {
+				canonical = f
+			}
+		}
+		b.WriteRune(canonical)
+	}
+	return b.String()
+}
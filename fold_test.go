@@ -0,0 +1,91 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_UnionFold(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		a := ctor("Go", "Rust", "zig")
+		b := ctor("GO", "Zig", "Python", "python")
+
+		union := UnionFold(a, b)
+		expected := ctor("Go", "Rust", "zig", "Python")
+		if !union.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, union)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_IntersectFold(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		a := ctor("Go", "Rust", "zig", "Straße")
+		b := ctor("GO", "ZIG", "STRASSE", "straSSe", "strasSE")
+
+		intersection := IntersectFold(a, b)
+		expected := ctor("Go", "zig")
+		if !intersection.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, intersection)
+		}
+
+		if !IntersectFold(a, ctor()).IsEmpty() {
+			t.Error("intersection with the empty set should be empty")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_FoldKey(t *testing.T) {
+	pairs := [][2]string{
+		{"go", "GO"},
+		{"Σίσυφος", "ΣΊΣΥΦΟΣ"},
+		{"k", "K"}, // Kelvin sign
+	}
+	for _, p := range pairs {
+		if foldKey(p[0]) != foldKey(p[1]) {
+			t.Errorf("%q and %q should have the same fold key", p[0], p[1])
+		}
+	}
+
+	if foldKey("go") == foldKey("gopher") {
+		t.Error("different words should not share a fold key")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// formatSet implements fmt.Formatter for the sets of this package. %v and
+// %s print the same as String, %+v adds the element type, and %#v prints a
+// Go expression building the set with ctor, the name of its constructor,
+// called with args followed by the elements. Other verbs, flags, widths
+// and precisions apply to each element, so that %x prints the elements in
+// hexadecimal and %q quotes them.
+func formatSet[T comparable](f fmt.State, verb rune, ctor string, elems []T, args ...string) {
+	if verb == 's' {
+		verb = 'v'
+	}
+	directive := formatDirective(f, verb)
+
+	items := make([]string, 0, len(args)+len(elems))
+	if verb == 'v' && f.Flag('#') {
+		items = append(items, args...)
+	}
+	for _, elem := range elems {
+		items = append(items, fmt.Sprintf(directive, elem))
+	}
+	joined := strings.Join(items, ", ")
+
+	elemType := reflect.TypeOf((*T)(nil)).Elem().String()
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(f, "mapset.%s[%s](%s)", ctor, elemType, joined)
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "Set[%s]{%s}", elemType, joined)
+	default:
+		io.WriteString(f, "Set{"+joined+"}")
+	}
+}
+
+// formatDirective rebuilds the directive that is being formatted from its
+// state, like fmt.FormatString which isn't available before Go 1.20.
+func formatDirective(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
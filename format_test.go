@@ -0,0 +1,62 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_Format(t *testing.T) {
+	for _, tc := range []struct {
+		format string
+		set    interface{}
+		want   string
+	}{
+		{"%v", NewSet(7), "Set{7}"},
+		{"%s", NewThreadUnsafeSet(7), "Set{7}"},
+		{"%+v", NewSet(7), "Set[int]{7}"},
+		{"%#v", NewSet(7), "mapset.NewSet[int](7)"},
+		{"%#v", NewThreadUnsafeSet("a"), `mapset.NewThreadUnsafeSet[string]("a")`},
+		{"%#v", NewShardedSet(2, 7), "mapset.NewShardedSet[int](2, 7)"},
+		{"%#v", NewShardedSet[int](2), "mapset.NewShardedSet[int](2)"},
+		{"%#v", NewSet[int](), "mapset.NewSet[int]()"},
+		{"%x", NewSet(255), "Set{ff}"},
+		{"%q", NewSet("a"), `Set{"a"}`},
+		{"%03d", NewThreadUnsafeSet(7), "Set{007}"},
+		{"%+v", NewSet(user{1, "alice"}), "Set[mapset.user]{{ID:1 Name:alice}}"},
+	} {
+		if got := fmt.Sprintf(tc.format, tc.set); got != tc.want {
+			t.Errorf("%s: Expected %s, got: %s", tc.format, tc.want, got)
+		}
+	}
+
+	// iterating twice may yield another order, so the lengths are compared
+	s := NewSet(1, 2, 3)
+	if got := fmt.Sprintf("%v", s); len(got) != len(s.String()) {
+		t.Errorf("%%v should print like String, expected %s, got: %s", s.String(), got)
+	}
+}
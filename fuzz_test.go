@@ -0,0 +1,158 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/deckarep/golang-set/v2/mapsettest"
+)
+
+func addSeeds(f *testing.F, seeds [][]byte) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+}
+
+// checkRoundTrip encodes s with marshal, decodes the result with unmarshal
+// and fails if the decoded set is different.
+func checkRoundTrip[T comparable](t *testing.T, s mapset.Set[T], marshal func(mapset.Set[T]) ([]byte, error), unmarshal func([]byte, mapset.Set[T]) error) {
+	t.Helper()
+
+	b, err := marshal(s)
+	if err != nil {
+		// an element that cannot be encoded, such as a NaN in JSON
+		return
+	}
+	decoded := mapset.NewSet[T]()
+	if err := unmarshal(b, decoded); err != nil {
+		t.Fatalf("cannot decode %q encoded from %v: %v", b, s, err)
+	}
+	if !decoded.Equal(mapset.NewSet(s.ToSlice()...)) {
+		t.Fatalf("round trip changed %v into %v", s, decoded)
+	}
+}
+
+// The binary and text encodings of sets are implemented by the sets of the
+// package, but are not part of the mapset.Set interface.
+
+func marshalBinary[T comparable](s mapset.Set[T]) ([]byte, error) {
+	return s.(encoding.BinaryMarshaler).MarshalBinary()
+}
+
+func unmarshalBinary[T comparable](b []byte, s mapset.Set[T]) error {
+	return s.(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+}
+
+func marshalText[T comparable](s mapset.Set[T]) ([]byte, error) {
+	return s.(encoding.TextMarshaler).MarshalText()
+}
+
+func unmarshalText[T comparable](b []byte, s mapset.Set[T]) error {
+	return s.(encoding.TextUnmarshaler).UnmarshalText(b)
+}
+
+func FuzzUnmarshalJSON(f *testing.F) {
+	addSeeds(f, mapsettest.JSONSeeds())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		strs := mapset.NewSet[string]()
+		if err := json.Unmarshal(data, strs); err == nil {
+			checkRoundTrip(t, strs, mapset.Set[string].MarshalJSON, func(b []byte, s mapset.Set[string]) error {
+				return json.Unmarshal(b, s)
+			})
+		}
+
+		floats := mapset.NewThreadUnsafeSet[float64]()
+		if err := json.Unmarshal(data, floats); err == nil {
+			checkRoundTrip(t, floats, mapset.Set[float64].MarshalJSON, func(b []byte, s mapset.Set[float64]) error {
+				return json.Unmarshal(b, s)
+			})
+		}
+
+		// the streaming decoder must agree with json.Unmarshal
+		streamed := mapset.NewSet[string]()
+		if err := mapset.DecodeJSON(bytes.NewReader(data), streamed); err == nil && !streamed.IsEmpty() {
+			if !streamed.IsSubset(strs) && json.Valid(data) {
+				t.Fatalf("DecodeJSON decoded %v, json.Unmarshal %v", streamed, strs)
+			}
+		}
+	})
+}
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	addSeeds(f, mapsettest.BinarySeeds())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		strs := mapset.NewSet[string]()
+		if err := unmarshalBinary(data, strs); err == nil {
+			checkRoundTrip(t, strs, marshalBinary[string], unmarshalBinary[string])
+		}
+
+		ints := mapset.NewThreadUnsafeSet[int]()
+		if err := unmarshalBinary(data, ints); err == nil {
+			checkRoundTrip(t, ints, marshalBinary[int], unmarshalBinary[int])
+		}
+	})
+}
+
+func FuzzUnmarshalText(f *testing.F) {
+	addSeeds(f, mapsettest.TextSeeds())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		strs := mapset.NewSet[string]()
+		if err := unmarshalText(data, strs); err != nil {
+			t.Fatalf("any text should decode into strings: %v", err)
+		}
+		checkRoundTrip(t, strs, marshalText[string], unmarshalText[string])
+
+		ints := mapset.NewSet[int]()
+		if err := unmarshalText(data, ints); err == nil {
+			checkRoundTrip(t, ints, marshalText[int], unmarshalText[int])
+		}
+
+		if _, err := mapset.ReadLines(bytes.NewReader(data)); err != nil {
+			t.Fatalf("any text should be read as lines: %v", err)
+		}
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	addSeeds(f, mapsettest.JSONSeeds())
+	addSeeds(f, mapsettest.BinarySeeds())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		format, err := mapset.DetectFormat(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatalf("DetectFormat should not fail on in-memory data: %v", err)
+		}
+		err = mapset.Decode(bytes.NewReader(data), mapset.NewSet[string]())
+		if format == mapset.FormatUnknown && err == nil {
+			t.Fatal("Decode should fail on data of unknown format")
+		}
+	})
+}
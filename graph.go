@@ -0,0 +1,200 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+)
+
+// ErrCycle is returned by TopoSort for graphs that have a cycle.
+var ErrCycle = errors.New("mapset: graph has a cycle")
+
+// The graph helpers below treat a map[T]Set[T] as a directed graph in
+// adjacency set form: g[u] holds the nodes u has an edge to. Nodes that only
+// appear as neighbors are nodes of the graph too, and a nil set means no
+// edge.
+
+// graphNodes returns all the nodes of g.
+func graphNodes[T comparable](g map[T]Set[T]) []T {
+	seen := make(map[T]struct{}, len(g))
+	nodes := make([]T, 0, len(g))
+	visit := func(v T) {
+		if _, found := seen[v]; !found {
+			seen[v] = struct{}{}
+			nodes = append(nodes, v)
+		}
+	}
+	for u, neighbors := range g {
+		visit(u)
+		if neighbors != nil {
+			neighbors.Each(func(v T) bool {
+				visit(v)
+				return false
+			})
+		}
+	}
+	return nodes
+}
+
+// neighbors returns the nodes u has an edge to.
+func neighbors[T comparable](g map[T]Set[T], u T) []T {
+	if s := g[u]; s != nil {
+		return s.ToSlice()
+	}
+	return nil
+}
+
+// Reachable returns the set of nodes reachable from the given node by
+// following the edges of g, including from itself. The returned set is
+// thread-safe.
+func Reachable[T comparable](g map[T]Set[T], from T) Set[T] {
+	reached := newThreadSafeSet[T]()
+	reached.uss.add(from)
+
+	stack := []T{from}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range neighbors(g, u) {
+			if !reached.uss.contains(v) {
+				reached.uss.add(v)
+				stack = append(stack, v)
+			}
+		}
+	}
+	return reached
+}
+
+// StronglyConnected returns the strongly connected components of g: the
+// maximal sets of nodes that can all reach each other. Every node belongs to
+// exactly one component, and components are returned in reverse
+// topological order, a component never having an edge to the components
+// that follow it. The returned sets are thread-safe.
+func StronglyConnected[T comparable](g map[T]Set[T]) []Set[T] {
+	// Tarjan's algorithm, with an explicit stack of frames
+	type frame struct {
+		node      T
+		neighbors []T
+		next      int
+	}
+
+	index := make(map[T]int)
+	lowlink := make(map[T]int)
+	onStack := make(map[T]bool)
+	var stack []T
+	var components []Set[T]
+
+	for _, root := range graphNodes(g) {
+		if _, visited := index[root]; visited {
+			continue
+		}
+
+		frames := []*frame{{node: root, neighbors: neighbors(g, root)}}
+		index[root], lowlink[root] = len(index), len(index)
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(frames) > 0 {
+			f := frames[len(frames)-1]
+			if f.next < len(f.neighbors) {
+				v := f.neighbors[f.next]
+				f.next++
+				if _, visited := index[v]; !visited {
+					index[v], lowlink[v] = len(index), len(index)
+					stack = append(stack, v)
+					onStack[v] = true
+					frames = append(frames, &frame{node: v, neighbors: neighbors(g, v)})
+				} else if onStack[v] && index[v] < lowlink[f.node] {
+					lowlink[f.node] = index[v]
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+			u := f.node
+			if len(frames) > 0 {
+				if parent := frames[len(frames)-1].node; lowlink[u] < lowlink[parent] {
+					lowlink[parent] = lowlink[u]
+				}
+			}
+			if lowlink[u] != index[u] {
+				continue
+			}
+
+			component := newThreadSafeSet[T]()
+			for {
+				v := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[v] = false
+				component.uss.add(v)
+				if v == u {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// TopoSort returns the nodes of g in topological order, every node coming
+// before the nodes it has an edge to; for instance tasks come before the
+// tasks depending on them when edges point to dependents. It returns
+// ErrCycle if g has a cycle. The order of the nodes that don't depend on
+// each other is unspecified.
+func TopoSort[T comparable](g map[T]Set[T]) ([]T, error) {
+	nodes := graphNodes(g)
+	indegree := make(map[T]int, len(nodes))
+	for _, u := range nodes {
+		for _, v := range neighbors(g, u) {
+			indegree[v]++
+		}
+	}
+
+	// Kahn's algorithm
+	var ready []T
+	for _, u := range nodes {
+		if indegree[u] == 0 {
+			ready = append(ready, u)
+		}
+	}
+	sorted := make([]T, 0, len(nodes))
+	for len(ready) > 0 {
+		u := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		sorted = append(sorted, u)
+		for _, v := range neighbors(g, u) {
+			if indegree[v]--; indegree[v] == 0 {
+				ready = append(ready, v)
+			}
+		}
+	}
+
+	if len(sorted) != len(nodes) {
+		return nil, ErrCycle
+	}
+	return sorted, nil
+}
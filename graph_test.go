@@ -0,0 +1,117 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_Reachable(t *testing.T) {
+	g := map[string]Set[string]{
+		"a": NewSet("b", "c"),
+		"b": NewSet("d"),
+		"c": NewThreadUnsafeSet("d"),
+		"e": NewSet("a"),
+		"f": nil,
+	}
+
+	if r := Reachable(g, "a"); !r.Equal(NewSet("a", "b", "c", "d")) {
+		t.Errorf("unexpected reachable nodes: %v", r)
+	}
+	if r := Reachable(g, "d"); !r.Equal(NewSet("d")) {
+		t.Errorf("a node without edges only reaches itself, got %v", r)
+	}
+	if r := Reachable(g, "f"); !r.Equal(NewSet("f")) {
+		t.Errorf("a node with nil edges only reaches itself, got %v", r)
+	}
+}
+
+func Test_StronglyConnected(t *testing.T) {
+	g := map[int]Set[int]{
+		1: NewSet(2),
+		2: NewSet(3),
+		3: NewSet(1, 4),
+		4: NewSet(5),
+		5: NewSet(4, 6),
+	}
+
+	components := StronglyConnected(g)
+	expected := []Set[int]{NewSet(6), NewSet(4, 5), NewSet(1, 2, 3)}
+	if len(components) != len(expected) {
+		t.Fatalf("expected %d components, got %v", len(expected), components)
+	}
+	for i := range expected {
+		if !components[i].Equal(expected[i]) {
+			t.Errorf("expected component %d to be %v, got %v", i, expected[i], components[i])
+		}
+	}
+}
+
+func Test_StronglyConnectedDeep(t *testing.T) {
+	// a long chain must not exhaust the stack
+	g := make(map[int]Set[int])
+	for i := 0; i < 100000; i++ {
+		g[i] = NewThreadUnsafeSet(i + 1)
+	}
+	if components := StronglyConnected(g); len(components) != 100001 {
+		t.Errorf("expected one component per node, got %d", len(components))
+	}
+}
+
+func Test_TopoSort(t *testing.T) {
+	g := map[string]Set[string]{
+		"shirt":    NewSet("tie", "belt"),
+		"tie":      NewSet("jacket"),
+		"trousers": NewSet("shoes", "belt"),
+		"belt":     NewSet("jacket"),
+		"socks":    NewSet("shoes"),
+	}
+
+	sorted, err := TopoSort(g)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if len(sorted) != 7 {
+		t.Fatalf("expected 7 nodes, got %v", sorted)
+	}
+	position := make(map[string]int)
+	for i, node := range sorted {
+		position[node] = i
+	}
+	for u, neighbors := range g {
+		neighbors.Each(func(v string) bool {
+			if position[u] >= position[v] {
+				t.Errorf("%s should come before %s in %v", u, v, sorted)
+			}
+			return false
+		})
+	}
+
+	g["jacket"] = NewSet("shirt")
+	if _, err := TopoSort(g); err != ErrCycle {
+		t.Errorf("Expected ErrCycle, got: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"sort"
+)
+
+// CardinalityHistogram counts the sets of m by cardinality, to analyze the
+// skew of per-key sets such as hot tenants or giant groups. buckets holds
+// the inclusive upper bounds of the buckets, in any order; every set is
+// counted in the smallest bucket its cardinality fits in, and the count is
+// stored under that bound. Sets larger than every bound are counted under
+// math.MaxInt. Nil sets count as empty.
+//
+//	// sets with up to 10, 100 and 1000 elements, and the larger ones
+//	hist := mapset.CardinalityHistogram(groups, []int{10, 100, 1000})
+//
+// Every bucket, including the overflow one, is present in the result even
+// when no set falls into it.
+func CardinalityHistogram[K comparable, T comparable](m map[K]Set[T], buckets []int) map[int]int {
+	bounds := append([]int(nil), buckets...)
+	sort.Ints(bounds)
+
+	hist := make(map[int]int, len(bounds)+1)
+	for _, b := range bounds {
+		hist[b] = 0
+	}
+	hist[math.MaxInt] = 0
+
+	for _, s := range m {
+		n := 0
+		if s != nil {
+			n = s.Cardinality()
+		}
+		i := sort.SearchInts(bounds, n)
+		if i == len(bounds) {
+			hist[math.MaxInt]++
+		} else {
+			hist[bounds[i]]++
+		}
+	}
+	return hist
+}
@@ -0,0 +1,54 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_CardinalityHistogram(t *testing.T) {
+	groups := map[string]Set[int]{
+		"empty": NewSet[int](),
+		"nil":   nil,
+		"one":   NewSet(1),
+		"two":   NewThreadUnsafeSet(1, 2),
+		"five":  NewSet(1, 2, 3, 4, 5),
+		"many":  makeSetInt(nrand(N)),
+	}
+
+	hist := CardinalityHistogram(groups, []int{10, 0, 2})
+	expected := map[int]int{0: 2, 2: 2, 10: 1, math.MaxInt: 1}
+	if !reflect.DeepEqual(hist, expected) {
+		t.Errorf("expected %v, got %v", expected, hist)
+	}
+
+	hist = CardinalityHistogram(map[int]Set[int]{}, nil)
+	if !reflect.DeepEqual(hist, map[int]int{math.MaxInt: 0}) {
+		t.Errorf("expected only an empty overflow bucket, got %v", hist)
+	}
+}
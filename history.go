@@ -0,0 +1,101 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"time"
+)
+
+// Mutation is a change recorded by a HistorySet: the elements that were
+// added to or removed from the set by a single operation.
+type Mutation[T comparable] struct {
+	Op    Op
+	Elems []T
+	Time  time.Time
+}
+
+// HistorySet is a set that records its most recent mutations, so that when
+// a set reaches an unexpected state in production the operations that led
+// there can be inspected. Only operations that actually changed the set are
+// recorded; a bulk operation such as Append is a single mutation.
+type HistorySet[T comparable] struct {
+	Set[T]
+
+	mu      sync.Mutex
+	history []Mutation[T] // ring buffer of the last mutations
+	next    int           // index of the slot to overwrite next
+	full    bool
+	now     func() time.Time
+}
+
+// NewHistorySet returns a set that forwards all operations to s and records
+// its last n mutations. If n is less than 1, a single mutation is kept.
+//
+// Mutations must go through the returned set to be recorded; s itself
+// should no longer be used directly.
+func NewHistorySet[T comparable](s Set[T], n int) *HistorySet[T] {
+	if n < 1 {
+		n = 1
+	}
+	h := &HistorySet[T]{
+		history: make([]Mutation[T], n),
+		now:     time.Now,
+	}
+	h.Set = newObservedSet(s, h.record)
+	return h
+}
+
+func (h *HistorySet[T]) unwrap() Set[T] {
+	return h.Set
+}
+
+// History returns the recorded mutations, oldest first.
+func (h *HistorySet[T]) History() []Mutation[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]Mutation[T](nil), h.history[:h.next]...)
+	}
+	history := make([]Mutation[T], 0, len(h.history))
+	history = append(history, h.history[h.next:]...)
+	return append(history, h.history[:h.next]...)
+}
+
+func (h *HistorySet[T]) record(op Op, vs []T) {
+	m := Mutation[T]{Op: op, Elems: vs, Time: h.now()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.history[h.next] = m
+	h.next++
+	if h.next == len(h.history) {
+		h.next = 0
+		h.full = true
+	}
+}
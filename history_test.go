@@ -0,0 +1,80 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_HistorySet(t *testing.T) {
+	now, advance := manualClock(time.Unix(0, 0))
+	h := NewHistorySet[int](NewSet[int](), 3)
+	h.now = now
+
+	if len(h.History()) != 0 {
+		t.Errorf("expected empty history, got %v", h.History())
+	}
+
+	h.Add(1)
+	advance(time.Second)
+	h.Append(1, 2, 3)
+	h.Add(3) // no-op mutations are not recorded
+	h.Remove(7)
+
+	history := h.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 mutations, got %v", history)
+	}
+	if history[0].Op != OpAdd || len(history[0].Elems) != 1 || !history[0].Time.Equal(time.Unix(0, 0)) {
+		t.Errorf("unexpected first mutation: %+v", history[0])
+	}
+	if history[1].Op != OpAdd || len(history[1].Elems) != 2 || !history[1].Time.Equal(time.Unix(1, 0)) {
+		t.Errorf("unexpected second mutation: %+v", history[1])
+	}
+
+	// the oldest mutations are dropped once the buffer is full
+	h.Remove(1)
+	h.RemoveAll(2, 3)
+	history = h.History()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 mutations, got %v", history)
+	}
+	if history[0].Op != OpAdd || history[1].Op != OpRemove || history[2].Op != OpRemove || len(history[2].Elems) != 2 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+	if !h.IsEmpty() {
+		t.Errorf("expected empty set, got %v", h)
+	}
+}
+
+func Test_HistorySetAsOperand(t *testing.T) {
+	h := NewHistorySet[int](NewSet(1, 2), 1)
+	h.Append(3)
+	if u := NewSet(4).Union(h); !u.Equal(NewSet(1, 2, 3, 4)) {
+		t.Errorf("unexpected union: %v", u)
+	}
+}
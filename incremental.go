@@ -0,0 +1,185 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+)
+
+// Source is a set whose mutations can be followed, the input of the
+// derived sets maintained by IncrementalUnion and IncrementalIntersect.
+// It is implemented by ObservableSet and IncrementalSet, so that derived
+// sets can be chained.
+type Source[T comparable] interface {
+	SetView[T]
+
+	// Subscribe registers fn to be called with every mutation of the set,
+	// and returns a function that unregisters it.
+	Subscribe(fn func(Mutation[T])) (cancel func())
+}
+
+// IncrementalSet is a read-only set derived from other sets, kept up to
+// date by applying the mutations of its inputs as they happen instead of
+// recomputing it from scratch. Every mutation of an input costs a
+// membership check of the changed elements in the other inputs, whatever
+// their size. An IncrementalSet is safe for concurrent use, provided its
+// inputs are, and publishes its own mutations to its subscribers.
+type IncrementalSet[T comparable] struct {
+	out    *ObservableSet[T]
+	inputs []Source[T]
+	// member reports whether an element belongs to the derived set given
+	// the current contents of the inputs.
+	member func(v T) bool
+
+	// mu serializes the application of mutations, so that membership is
+	// always checked against inputs at least as recent as the mutation.
+	mu      sync.Mutex
+	cancels []func()
+}
+
+// IncrementalUnion returns a set holding the union of the inputs, kept up
+// to date as the inputs change. Call Close to stop following the inputs.
+func IncrementalUnion[T comparable](inputs ...Source[T]) *IncrementalSet[T] {
+	s := &IncrementalSet[T]{inputs: inputs}
+	s.member = func(v T) bool {
+		for _, in := range s.inputs {
+			if in.ContainsOne(v) {
+				return true
+			}
+		}
+		return false
+	}
+	s.start()
+	return s
+}
+
+// IncrementalIntersect returns a set holding the intersection of the
+// inputs, kept up to date as the inputs change. Call Close to stop
+// following the inputs.
+func IncrementalIntersect[T comparable](inputs ...Source[T]) *IncrementalSet[T] {
+	s := &IncrementalSet[T]{inputs: inputs}
+	s.member = func(v T) bool {
+		for _, in := range s.inputs {
+			if !in.ContainsOne(v) {
+				return false
+			}
+		}
+		return len(s.inputs) > 0
+	}
+	s.start()
+	return s
+}
+
+// start subscribes to the inputs before computing the initial contents, so
+// that no mutation is missed: the ones racing with the computation are
+// applied after it, and applying a mutation twice is harmless.
+func (s *IncrementalSet[T]) start() {
+	s.out = NewObservableSet[T](newThreadSafeSet[T]())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, in := range s.inputs {
+		s.cancels = append(s.cancels, in.Subscribe(s.apply))
+	}
+	var initial []T
+	for _, in := range s.inputs {
+		// the elements are copied first, since member calls back into the
+		// inputs
+		for _, v := range in.ToSlice() {
+			if s.member(v) {
+				initial = append(initial, v)
+			}
+		}
+	}
+	s.out.Append(initial...)
+}
+
+// apply recomputes the membership of the elements changed by m.
+func (s *IncrementalSet[T]) apply(m Mutation[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var added, removed []T
+	for _, v := range m.Elems {
+		if s.member(v) {
+			added = append(added, v)
+		} else {
+			removed = append(removed, v)
+		}
+	}
+	if len(added) > 0 {
+		s.out.Append(added...)
+	}
+	if len(removed) > 0 {
+		s.out.RemoveAll(removed...)
+	}
+}
+
+// Close stops following the inputs; the set keeps its current contents.
+func (s *IncrementalSet[T]) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	s.cancels = nil
+}
+
+// Subscribe registers fn to be called with every mutation of the derived
+// set, and returns a function that unregisters it.
+func (s *IncrementalSet[T]) Subscribe(fn func(Mutation[T])) (cancel func()) {
+	return s.out.Subscribe(fn)
+}
+
+func (s *IncrementalSet[T]) Cardinality() int {
+	return s.out.Cardinality()
+}
+
+func (s *IncrementalSet[T]) Contains(val ...T) bool {
+	return s.out.Contains(val...)
+}
+
+func (s *IncrementalSet[T]) ContainsOne(val T) bool {
+	return s.out.ContainsOne(val)
+}
+
+func (s *IncrementalSet[T]) Each(cb func(T) bool) {
+	s.out.Each(cb)
+}
+
+func (s *IncrementalSet[T]) IsEmpty() bool {
+	return s.out.IsEmpty()
+}
+
+func (s *IncrementalSet[T]) String() string {
+	return s.out.String()
+}
+
+func (s *IncrementalSet[T]) ToSlice() []T {
+	return s.out.ToSlice()
+}
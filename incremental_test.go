@@ -0,0 +1,118 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func Test_IncrementalUnion(t *testing.T) {
+	a, b := NewObservableSet(NewSet(1, 2)), NewObservableSet(NewSet(2, 3))
+	u := IncrementalUnion[int](a, b)
+	defer u.Close()
+
+	if !NewSet(u.ToSlice()...).Equal(NewSet(1, 2, 3)) {
+		t.Errorf("expected the initial union to be {1, 2, 3}, got %v", u)
+	}
+
+	a.Remove(2) // still in b
+	b.Add(4)
+	b.Remove(3)
+	if !NewSet(u.ToSlice()...).Equal(NewSet(1, 2, 4)) {
+		t.Errorf("expected the union to be {1, 2, 4}, got %v", u)
+	}
+
+	u.Close()
+	a.Add(5)
+	if u.ContainsOne(5) {
+		t.Error("expected Close to stop following the inputs")
+	}
+}
+
+func Test_IncrementalIntersect(t *testing.T) {
+	a := NewObservableSet(NewSet(1, 2, 3))
+	b := NewObservableSet(NewThreadUnsafeSet(2, 3))
+	c := NewObservableSet(NewSet(3, 4))
+
+	// derived sets can be chained
+	ab := IncrementalIntersect[int](a, b)
+	abc := IncrementalUnion[int](ab, c)
+	defer ab.Close()
+	defer abc.Close()
+
+	var changes int
+	abc.Subscribe(func(Mutation[int]) { changes++ })
+
+	b.Add(1)
+	a.Remove(3)
+	if !NewSet(ab.ToSlice()...).Equal(NewSet(1, 2)) {
+		t.Errorf("expected the intersection to be {1, 2}, got %v", ab)
+	}
+	if !NewSet(abc.ToSlice()...).Equal(NewSet(1, 2, 3, 4)) {
+		t.Errorf("expected the union to be {1, 2, 3, 4}, got %v", abc)
+	}
+	c.Remove(3)
+	if abc.ContainsOne(3) || changes != 2 {
+		t.Errorf("expected 3 to be removed after 2 changes, got %v after %d", abc, changes)
+	}
+
+	if empty := IncrementalIntersect[int](); !empty.IsEmpty() {
+		t.Errorf("expected the intersection of no sets to be empty, got %v", empty)
+	}
+}
+
+func Test_IncrementalConcurrent(t *testing.T) {
+	a, b := NewObservableSet(NewSet[int]()), NewObservableSet(NewSet[int]())
+	u := IncrementalUnion[int](a, b)
+	i := IncrementalIntersect[int](a, b)
+	defer u.Close()
+	defer i.Close()
+
+	var wg sync.WaitGroup
+	for g, s := range []*ObservableSet[int]{a, b, a, b} {
+		wg.Add(1)
+		go func(s *ObservableSet[int], r *rand.Rand) {
+			defer wg.Done()
+			for n := 0; n < N; n++ {
+				if v := r.Intn(20); r.Intn(2) == 0 {
+					s.Add(v)
+				} else {
+					s.Remove(v)
+				}
+			}
+		}(s, rand.New(rand.NewSource(int64(g))))
+	}
+	wg.Wait()
+
+	if want := a.Union(b); !NewSet(u.ToSlice()...).Equal(want) {
+		t.Errorf("expected the union to be %v, got %v", want, u)
+	}
+	if want := a.Intersect(b); !NewSet(i.ToSlice()...).Equal(want) {
+		t.Errorf("expected the intersection to be %v, got %v", want, i)
+	}
+}
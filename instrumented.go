@@ -0,0 +1,145 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"expvar"
+	"time"
+)
+
+// MetricsSink receives the metrics of a set instrumented with
+// NewInstrumentedSet, to export them to expvar, Prometheus or another
+// monitoring system. Its methods are called synchronously, possibly from
+// several goroutines at once, so they should be quick and thread-safe.
+type MetricsSink interface {
+	// Cardinality reports the cardinality of the set after a mutation
+	// that changed it.
+	Cardinality(n int)
+	// Added and Removed report how many elements a mutation actually
+	// added or removed. Sinks derive the rates of change from them, for
+	// instance as Prometheus counters.
+	Added(n int)
+	Removed(n int)
+	// LockWait reports how long an operation waited for the lock of the
+	// set, for writing or for reading.
+	LockWait(d time.Duration, write bool)
+}
+
+// NewInstrumentedSet returns a set that forwards all operations to s and
+// reports the cardinality of s and the elements actually added and removed
+// to sink. For the thread-safe sets of this package, including decorated
+// ones such as those of NewSetWithOptions, it also reports how
+// long every operation waits for their lock, including the operations
+// applied to s directly; it must then be called before s is shared with
+// other goroutines.
+//
+// Mutations must go through the returned set to be counted; s itself
+// should no longer be mutated directly.
+func NewInstrumentedSet[T comparable](s Set[T], sink MetricsSink) Set[T] {
+	cardinality := s.Cardinality
+	if t, ok := unwrapSet(s).(*threadSafeSet[T]); ok {
+		var inner rwLocker = &t.RWMutex
+		if t.locker != nil {
+			inner = t.locker
+		}
+		t.locker = &timedLocker{rwLocker: inner, sink: sink}
+
+		// reporting the cardinality must not count as a lock wait
+		cardinality = func() int {
+			inner.RLock()
+			defer inner.RUnlock()
+			return len(*t.uss)
+		}
+	}
+
+	return newObservedSet(s, func(op Op, vs []T) {
+		switch op {
+		case OpAdd:
+			sink.Added(len(vs))
+		case OpRemove:
+			sink.Removed(len(vs))
+		}
+		sink.Cardinality(cardinality())
+	})
+}
+
+// timedLocker reports how long it takes to acquire the lock it wraps.
+type timedLocker struct {
+	rwLocker
+	sink MetricsSink
+}
+
+func (l *timedLocker) Lock() {
+	start := time.Now()
+	l.rwLocker.Lock()
+	l.sink.LockWait(time.Since(start), true)
+}
+
+func (l *timedLocker) RLock() {
+	start := time.Now()
+	l.rwLocker.RLock()
+	l.sink.LockWait(time.Since(start), false)
+}
+
+// expvarSink publishes the metrics of a set as an expvar.Map.
+type expvarSink struct {
+	cardinality, adds, removes expvar.Int
+	// waits and waitNanos count the lock acquisitions and their total wait
+	waits, waitNanos expvar.Int
+}
+
+// NewExpvarSink returns a MetricsSink publishing the metrics of a set as
+// an expvar map named name, with the current cardinality, the total
+// numbers of elements added and removed, and the number of lock
+// acquisitions with their total wait in nanoseconds. Like expvar.Publish,
+// it panics if name is already in use.
+func NewExpvarSink(name string) MetricsSink {
+	s := &expvarSink{}
+	m := expvar.NewMap(name)
+	m.Set("cardinality", &s.cardinality)
+	m.Set("adds", &s.adds)
+	m.Set("removes", &s.removes)
+	m.Set("lock_waits", &s.waits)
+	m.Set("lock_wait_ns", &s.waitNanos)
+	return s
+}
+
+func (s *expvarSink) Cardinality(n int) {
+	s.cardinality.Set(int64(n))
+}
+
+func (s *expvarSink) Added(n int) {
+	s.adds.Add(int64(n))
+}
+
+func (s *expvarSink) Removed(n int) {
+	s.removes.Add(int64(n))
+}
+
+func (s *expvarSink) LockWait(d time.Duration, _ bool) {
+	s.waits.Add(1)
+	s.waitNanos.Add(int64(d))
+}
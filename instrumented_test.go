@@ -0,0 +1,128 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"expvar"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type metricsRecorder struct {
+	mu               sync.Mutex
+	cardinality      int
+	added, removed   int
+	readWaits, waits int
+}
+
+func (s *metricsRecorder) Cardinality(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cardinality = n
+}
+
+func (s *metricsRecorder) Added(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.added += n
+}
+
+func (s *metricsRecorder) Removed(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removed += n
+}
+
+func (s *metricsRecorder) LockWait(d time.Duration, write bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if write {
+		s.waits++
+	} else {
+		s.readWaits++
+	}
+}
+
+func Test_InstrumentedSet(t *testing.T) {
+	test := func(t *testing.T, base Set[int], locked bool) {
+		sink := &metricsRecorder{}
+		s := NewInstrumentedSet(base, sink)
+
+		s.Append(1, 2, 3, 1)
+		s.Remove(2)
+		s.Remove(4)
+		s.ContainsOne(1)
+		if sink.added != 3 || sink.removed != 1 || sink.cardinality != 2 {
+			t.Errorf("Expected 3 added, 1 removed and a cardinality of 2, got: %+v", sink)
+		}
+		s.Clear()
+		if sink.removed != 3 || sink.cardinality != 0 {
+			t.Errorf("Expected 3 removed and a cardinality of 0, got: %+v", sink)
+		}
+		if got := sink.waits > 0 && sink.readWaits > 0; got != locked {
+			t.Errorf("Expected lock waits to be reported: %v, got: %+v", locked, sink)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int](), true)
+	})
+	t.Run("Policy", func(t *testing.T) {
+		test(t, NewSetWithOptions(WithLockPolicy[int](LockWriterPreferring)), true)
+	})
+	t.Run("Options", func(t *testing.T) {
+		test(t, NewSetWithOptions(WithMaxCardinality[int](10, RejectWhenFull[int]())), true)
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int](), false)
+	})
+}
+
+func Test_InstrumentedSetLockWaits(t *testing.T) {
+	sink := &metricsRecorder{}
+	s := NewInstrumentedSet(NewSet[int](), sink)
+
+	s.Add(1)
+	s.Remove(1)
+	if sink.waits != 2 || sink.readWaits != 0 {
+		t.Errorf("Expected only the 2 write locks to be reported, got: %+v", sink)
+	}
+}
+
+func Test_ExpvarSink(t *testing.T) {
+	s := NewInstrumentedSet(NewSet[string](), NewExpvarSink("mapset_test_set"))
+	s.Append("a", "b")
+	s.Remove("a")
+
+	got := expvar.Get("mapset_test_set").String()
+	for _, want := range []string{`"adds": 2`, `"removes": 1`, `"cardinality": 1`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s in %s", want, got)
+		}
+	}
+}
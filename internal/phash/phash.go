@@ -0,0 +1,204 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package phash builds minimal-collision perfect hash tables for static sets
+// of strings using the hash and displace algorithm: keys are first grouped
+// into buckets by a shared hash, then every bucket gets its own seed chosen
+// so that all of its keys land into free slots of the table.
+//
+// A lookup costs two hashes and a single string comparison, and never
+// allocates.
+package phash
+
+import (
+	"errors"
+	"sort"
+)
+
+// maxSeed bounds the search for a bucket seed before the table is grown.
+const maxSeed = 1 << 16
+
+// maxGrowth bounds how many times the table is doubled when no seed is
+// found for a bucket.
+const maxGrowth = 3
+
+// ErrNoSeed is returned by Build when no seed could be found for a bucket,
+// which in practice never happens for reasonable key sets.
+var ErrNoSeed = errors.New("phash: no perfect hash seed found")
+
+// Table is a perfect hash table for a static set of strings.
+type Table struct {
+	// Seeds holds the seed of every bucket, len(Seeds) is a power of two.
+	Seeds []uint32
+	// Slots holds the keys, len(Slots) is a power of two. Unused slots are
+	// empty strings, see HasEmpty.
+	Slots []string
+	// HasEmpty reports whether the empty string is one of the keys.
+	HasEmpty bool
+	// Len is the number of distinct keys.
+	Len int
+}
+
+// Hash is the seeded string hash used by Table: FNV-1a followed by the
+// splitmix64 finalizer for better avalanche on short keys.
+func Hash(seed uint64, s string) uint64 {
+	h := uint64(14695981039346656037) ^ seed
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// Build returns a perfect hash table for keys. Duplicate keys are ignored.
+func Build(keys []string) (*Table, error) {
+	unique := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		unique[k] = struct{}{}
+	}
+
+	n, hasEmpty := len(unique), false
+	if _, found := unique[""]; found {
+		hasEmpty = true
+		delete(unique, "")
+	}
+
+	// keep the load factor at or below 0.8, since filling the last free
+	// slots of a full table takes a very long seed search
+	size := nextPowerOfTwo(len(unique) + (len(unique)+3)/4)
+	for grow := 0; grow <= maxGrowth; grow++ {
+		if t, ok := place(unique, size<<grow); ok {
+			t.Len, t.HasEmpty = n, hasEmpty
+			return t, nil
+		}
+	}
+	return nil, ErrNoSeed
+}
+
+// place tries to build a table of the given number of slots for unique,
+// which must not contain the empty string.
+func place(unique map[string]struct{}, size int) (*Table, bool) {
+	t := &Table{}
+	t.Slots = make([]string, size)
+	t.Seeds = make([]uint32, nextPowerOfTwo(len(unique)/4))
+
+	buckets := make([][]string, len(t.Seeds))
+	bucketMask := uint64(len(t.Seeds) - 1)
+	for k := range unique {
+		b := Hash(0, k) & bucketMask
+		buckets[b] = append(buckets[b], k)
+	}
+
+	// place the biggest buckets first, while the table is still mostly empty
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(buckets[order[i]]) > len(buckets[order[j]])
+	})
+
+	used := make([]bool, len(t.Slots))
+	slotMask := uint64(len(t.Slots) - 1)
+	slots := make([]uint64, 0, 16)
+	for _, b := range order {
+		bucket := buckets[b]
+		if len(bucket) == 0 {
+			break
+		}
+
+		found := false
+		for seed := uint32(1); seed < maxSeed && !found; seed++ {
+			slots = slots[:0]
+			found = true
+			for _, k := range bucket {
+				i := Hash(uint64(seed), k) & slotMask
+				if used[i] || contains(slots, i) {
+					found = false
+					break
+				}
+				slots = append(slots, i)
+			}
+			if found {
+				t.Seeds[b] = seed
+				for j, i := range slots {
+					used[i] = true
+					t.Slots[i] = bucket[j]
+				}
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return t, true
+}
+
+// Contains reports whether s is one of the keys of the table.
+func (t *Table) Contains(s string) bool {
+	if s == "" {
+		return t.HasEmpty
+	}
+	b := Hash(0, s) & uint64(len(t.Seeds)-1)
+	i := Hash(uint64(t.Seeds[b]), s) & uint64(len(t.Slots)-1)
+	return t.Slots[i] == s
+}
+
+// Keys returns the keys of the table in slot order.
+func (t *Table) Keys() []string {
+	keys := make([]string, 0, t.Len)
+	if t.HasEmpty {
+		keys = append(keys, "")
+	}
+	for _, k := range t.Slots {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func contains(slots []uint64, i uint64) bool {
+	for _, s := range slots {
+		if s == i {
+			return true
+		}
+	}
+	return false
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,96 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package phash
+
+import (
+	"strconv"
+	"testing"
+)
+
+func Test_Build(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 10, 100, 1000, 1024, 50000, 65536} {
+		keys := make([]string, n)
+		for i := range keys {
+			keys[i] = "key-" + strconv.Itoa(i)
+		}
+
+		table, err := Build(keys)
+		if err != nil {
+			t.Fatalf("n=%d: Error should be nil: %v", n, err)
+		}
+		if table.Len != n {
+			t.Errorf("n=%d: expected Len %d, got %d", n, n, table.Len)
+		}
+		if 5*n > 4*len(table.Slots) {
+			t.Errorf("n=%d: expected a load factor of at most 0.8, got %d slots", n, len(table.Slots))
+		}
+		for _, k := range keys {
+			if !table.Contains(k) {
+				t.Fatalf("n=%d: table should contain %q", n, k)
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			if k := "other-" + strconv.Itoa(i); table.Contains(k) {
+				t.Fatalf("n=%d: table should not contain %q", n, k)
+			}
+		}
+		if table.Contains("") {
+			t.Errorf("n=%d: table should not contain the empty string", n)
+		}
+		if len(table.Keys()) != n {
+			t.Errorf("n=%d: expected %d keys, got %d", n, n, len(table.Keys()))
+		}
+	}
+}
+
+func Test_BuildEmptyAndDuplicateKeys(t *testing.T) {
+	table, err := Build([]string{"a", "", "a", "b"})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if table.Len != 3 {
+		t.Errorf("expected 3 distinct keys, got %d", table.Len)
+	}
+	for _, k := range []string{"", "a", "b"} {
+		if !table.Contains(k) {
+			t.Errorf("table should contain %q", k)
+		}
+	}
+}
+
+func Benchmark_Contains(b *testing.B) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+	table, _ := Build(keys)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.Contains(keys[i%len(keys)])
+	}
+}
@@ -47,6 +47,21 @@ func (i *Iterator[T]) Stop() {
 	}
 }
 
+// Next returns the next element, or false once all elements have been
+// received or the Iterator was stopped. Unlike ranging over the channel
+// returned by Iter, breaking out of a loop over Next doesn't leak a
+// goroutine as long as Stop is called:
+//
+//	it := s.Iterator()
+//	defer it.Stop()
+//	for v, ok := it.Next(); ok; v, ok = it.Next() {
+//		...
+//	}
+func (i *Iterator[T]) Next() (T, bool) {
+	v, ok := <-i.C
+	return v, ok
+}
+
 // newIterator returns a new Iterator instance together with its item and stop channels.
 func newIterator[T comparable]() (*Iterator[T], chan<- T, <-chan struct{}) {
 	itemChan := make(chan T)
@@ -58,3 +58,28 @@ func Test_ExampleIterator(t *testing.T) {
 		t.Fatalf("expected iterator to have found `John` record but got nil or something else")
 	}
 }
+
+func Test_IteratorNext(t *testing.T) {
+	s := NewSet(1, 2, 3)
+
+	it := s.Iterator()
+	seen := NewSet[int]()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		seen.Add(v)
+	}
+	if !seen.Equal(s) {
+		t.Errorf("expected every element to be returned, got %v", seen)
+	}
+	if _, ok := it.Next(); ok {
+		t.Error("Next should keep reporting false once exhausted")
+	}
+
+	it = NewThreadUnsafeSet(1, 2, 3).Iterator()
+	if _, ok := it.Next(); !ok {
+		t.Fatal("expected an element")
+	}
+	it.Stop()
+	if _, ok := it.Next(); ok {
+		t.Error("Next should report false once stopped")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// DefaultIterationChunkSize is a chunk size suitable for WithChunkedIteration
+// and EachChunk, large enough to amortize the locking and small enough to
+// only hold the lock of a set for a short time.
+const DefaultIterationChunkSize = 1 << 16
+
+// WithChunkedIteration makes the iterations of a thread-safe set copy its
+// elements size at a time instead of holding the read lock for the whole
+// iteration: the lock is released while each chunk is handed to the caller,
+// so writers are not starved during long exports of very large sets. It
+// applies to Each, EachErr, Iter, Iterator, ToSlice and the streaming
+// encoders such as EncodeJSON. Callbacks run without the lock held and may
+// modify the set.
+//
+// Chunked iterations are weakly consistent: elements added or removed
+// during the iteration may or may not be visited, and an element that is
+// removed and added back may be visited twice. Other elements are visited
+// exactly once. It has no effect on thread-unsafe sets, or if size is not
+// positive. Like the other options, it is not inherited by the sets
+// returned by operations such as Union, Intersect or Clone.
+func WithChunkedIteration[T comparable](size int) Option[T] {
+	return func(o *options[T]) {
+		o.iterChunk = size
+	}
+}
+
+// EachChunk calls cb with consecutive chunks of at most size elements of s,
+// until all elements have been visited or cb returns true. The chunk slice
+// is reused between calls and must not be retained. A non-positive size
+// selects DefaultIterationChunkSize.
+//
+// For thread-safe sets the read lock is only held while a chunk is copied,
+// as with WithChunkedIteration, and the iteration is weakly consistent.
+// Other sets are iterated with Each.
+func EachChunk[T comparable](s Set[T], size int, cb func(chunk []T) bool) {
+	if size <= 0 {
+		size = DefaultIterationChunkSize
+	}
+	if t, ok := unwrapSet(s).(*threadSafeSet[T]); ok {
+		t.eachChunk(size, cb)
+		return
+	}
+
+	chunk := make([]T, 0, size)
+	stopped := false
+	s.Each(func(elem T) bool {
+		chunk = append(chunk, elem)
+		if len(chunk) < size {
+			return false
+		}
+		stopped = cb(chunk)
+		chunk = chunk[:0]
+		return stopped
+	})
+	if !stopped && len(chunk) > 0 {
+		cb(chunk)
+	}
+}
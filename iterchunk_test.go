@@ -0,0 +1,131 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_ChunkedIteration(t *testing.T) {
+	s := NewSetWithOptions(WithChunkedIteration[int](7))
+	s.Append(makeRange(100)...)
+
+	seen := NewThreadUnsafeSet[int]()
+	s.Each(func(v int) bool {
+		// callbacks run without the lock held, so writers aren't blocked
+		if v == 50 {
+			s.Remove(v)
+		}
+		seen.Add(v)
+		return false
+	})
+	if seen.Cardinality() != 100 {
+		t.Errorf("expected Each to visit 100 elements, got %d", seen.Cardinality())
+	}
+	if s.ContainsOne(50) {
+		t.Error("expected 50 to be removed during the iteration")
+	}
+
+	if got := s.ToSlice(); len(got) != 99 || !NewSet(got...).Equal(s) {
+		t.Errorf("expected ToSlice to return the 99 elements, got %d", len(got))
+	}
+
+	n := 0
+	for range s.Iter() {
+		n++
+	}
+	if n != 99 {
+		t.Errorf("expected Iter to visit 99 elements, got %d", n)
+	}
+
+	it := s.Iterator()
+	n = 0
+	for range it.C {
+		if n++; n == 10 {
+			it.Stop()
+		}
+	}
+	if n < 10 || n > 11 {
+		t.Errorf("expected Iterator to stop after 10 elements, got %d", n)
+	}
+
+	errStop := errors.New("stop")
+	n = 0
+	err := EachErr(s, func(int) error {
+		if n++; n == 20 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || n != 20 {
+		t.Errorf("expected EachErr to stop with errStop after 20 elements, got %v after %d", err, n)
+	}
+}
+
+func Test_EachChunk(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(makeRange(100)...)
+
+		seen := NewThreadUnsafeSet[int]()
+		chunks := 0
+		EachChunk(s, 30, func(chunk []int) bool {
+			if len(chunk) > 30 {
+				t.Errorf("expected chunks of at most 30 elements, got %d", len(chunk))
+			}
+			seen.Append(chunk...)
+			chunks++
+			return false
+		})
+		if chunks != 4 || !seen.Equal(NewThreadUnsafeSet(makeRange(100)...)) {
+			t.Errorf("expected 4 chunks with all elements, got %d chunks with %d elements", chunks, seen.Cardinality())
+		}
+
+		chunks = 0
+		EachChunk(s, 30, func([]int) bool {
+			chunks++
+			return true
+		})
+		if chunks != 1 {
+			t.Errorf("expected EachChunk to stop after 1 chunk, got %d", chunks)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func makeRange(n int) []int {
+	vs := make([]int, n)
+	for i := range vs {
+		vs[i] = i
+	}
+	return vs
+}
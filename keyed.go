@@ -0,0 +1,306 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeyedSet is a set of values of any type, including non-comparable ones
+// such as slices, maps or structs holding them. Two values are the same
+// element of the set when the key function extracts the same key from
+// them, typically an ID or a hash. Operations on a KeyedSet are
+// thread-safe.
+type KeyedSet[T any, K comparable] struct {
+	mu    sync.RWMutex
+	key   func(T) K
+	elems map[K]T
+}
+
+// NewKeyedSet creates and returns a new empty set whose elements are
+// identified by the keys returned by keyFn. keyFn must return the same key
+// for a value for as long as it is in the set.
+func NewKeyedSet[T any, K comparable](keyFn func(T) K) *KeyedSet[T, K] {
+	return newKeyedSet[T, K](keyFn, 0)
+}
+
+func newKeyedSet[T any, K comparable](keyFn func(T) K, cardinality int) *KeyedSet[T, K] {
+	return &KeyedSet[T, K]{key: keyFn, elems: make(map[K]T, cardinality)}
+}
+
+// Add adds v to the set and returns whether it was added. If an element
+// with the same key is already present, it is kept and v is discarded.
+func (s *KeyedSet[T, K]) Add(v T) bool {
+	k := s.key(v)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.elems[k]; found {
+		return false
+	}
+	s.elems[k] = v
+	return true
+}
+
+// Append adds multiple elements to the set and returns the number of
+// elements added, see Add.
+func (s *KeyedSet[T, K]) Append(vs ...T) int {
+	keys := s.keys(vs)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for i, k := range keys {
+		if _, found := s.elems[k]; !found {
+			s.elems[k] = vs[i]
+			n++
+		}
+	}
+	return n
+}
+
+// keys extracts the keys of vs, without holding the lock since the key
+// function may be expensive.
+func (s *KeyedSet[T, K]) keys(vs []T) []K {
+	keys := make([]K, len(vs))
+	for i, v := range vs {
+		keys[i] = s.key(v)
+	}
+	return keys
+}
+
+// Cardinality returns the number of elements in the set.
+func (s *KeyedSet[T, K]) Cardinality() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.elems)
+}
+
+// Clear removes all elements from the set.
+func (s *KeyedSet[T, K]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.elems = make(map[K]T)
+}
+
+// Clone returns a copy of the set using the same key function.
+func (s *KeyedSet[T, K]) Clone() *KeyedSet[T, K] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clone := newKeyedSet[T, K](s.key, len(s.elems))
+	for k, v := range s.elems {
+		clone.elems[k] = v
+	}
+	return clone
+}
+
+// Contains returns whether all of the given values are in the set, that is
+// whether the set holds elements with the same keys.
+func (s *KeyedSet[T, K]) Contains(vs ...T) bool {
+	return s.ContainsKey(s.keys(vs)...)
+}
+
+// ContainsKey returns whether elements with all of the given keys are in
+// the set.
+func (s *KeyedSet[T, K]) ContainsKey(keys ...K) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range keys {
+		if _, found := s.elems[k]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns the element with the given key, if any.
+func (s *KeyedSet[T, K]) Get(k K) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, found := s.elems[k]
+	return v, found
+}
+
+// Each iterates over the elements of the set and executes cb against each
+// of them. If cb returns true, the iteration stops. cb must not modify the
+// set.
+func (s *KeyedSet[T, K]) Each(cb func(T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range s.elems {
+		if cb(v) {
+			break
+		}
+	}
+}
+
+// IsEmpty returns whether the set has no elements.
+func (s *KeyedSet[T, K]) IsEmpty() bool {
+	return s.Cardinality() == 0
+}
+
+// Keys returns a set of the keys of the elements.
+func (s *KeyedSet[T, K]) Keys() Set[K] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := newThreadSafeSetWithSize[K](len(s.elems))
+	for k := range s.elems {
+		keys.uss.add(k)
+	}
+	return keys
+}
+
+// Remove removes the element with the same key as v from the set.
+func (s *KeyedSet[T, K]) Remove(v T) {
+	s.RemoveKey(s.key(v))
+}
+
+// RemoveKey removes the elements with the given keys from the set.
+func (s *KeyedSet[T, K]) RemoveKey(keys ...K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, k := range keys {
+		delete(s.elems, k)
+	}
+}
+
+// ToSlice returns the elements of the set as a slice.
+func (s *KeyedSet[T, K]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	elems := make([]T, 0, len(s.elems))
+	for _, v := range s.elems {
+		elems = append(elems, v)
+	}
+	return elems
+}
+
+// Equal returns whether both sets hold elements with the same keys.
+func (s *KeyedSet[T, K]) Equal(other *KeyedSet[T, K]) bool {
+	if s == other {
+		return true
+	}
+	keys := other.Keys()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.elems) != keys.Cardinality() {
+		return false
+	}
+	for k := range s.elems {
+		if !keys.ContainsOne(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set with the elements of both sets. Elements of s are
+// preferred over the elements of other with the same key. The new set uses
+// the key function of s.
+func (s *KeyedSet[T, K]) Union(other *KeyedSet[T, K]) *KeyedSet[T, K] {
+	union := other.Clone()
+	union.key = s.key
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for k, v := range s.elems {
+		union.elems[k] = v
+	}
+	return union
+}
+
+// Intersect returns a new set with the elements of s whose keys are also in
+// other.
+func (s *KeyedSet[T, K]) Intersect(other *KeyedSet[T, K]) *KeyedSet[T, K] {
+	keys := other.Keys()
+	return s.filterKeys(keys.ContainsOne)
+}
+
+// Difference returns a new set with the elements of s whose keys are not in
+// other.
+func (s *KeyedSet[T, K]) Difference(other *KeyedSet[T, K]) *KeyedSet[T, K] {
+	keys := other.Keys()
+	return s.filterKeys(func(k K) bool { return !keys.ContainsOne(k) })
+}
+
+func (s *KeyedSet[T, K]) filterKeys(keep func(K) bool) *KeyedSet[T, K] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filtered := newKeyedSet[T, K](s.key, 0)
+	for k, v := range s.elems {
+		if keep(k) {
+			filtered.elems[k] = v
+		}
+	}
+	return filtered
+}
+
+// String provides a convenient string representation of the current state
+// of the set.
+func (s *KeyedSet[T, K]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]string, 0, len(s.elems))
+	for _, v := range s.elems {
+		items = append(items, fmt.Sprintf("%v", v))
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// MarshalJSON creates a JSON array from the set, it marshals all elements
+func (s *KeyedSet[T, K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set, see Add. The
+// set must have been created with NewKeyedSet.
+func (s *KeyedSet[T, K]) UnmarshalJSON(b []byte) error {
+	var vs []T
+	if err := json.Unmarshal(b, &vs); err != nil {
+		return err
+	}
+	s.Append(vs...)
+	return nil
+}
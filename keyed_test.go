@@ -0,0 +1,141 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+type document struct {
+	ID   string
+	Tags []string
+}
+
+func documentID(d document) string {
+	return d.ID
+}
+
+func Test_KeyedSet(t *testing.T) {
+	s := NewKeyedSet(documentID)
+
+	if !s.Add(document{"a", []string{"x"}}) || s.Add(document{"a", []string{"y"}}) {
+		t.Error("Add should report whether an element with the key was added")
+	}
+	if n := s.Append(document{ID: "b"}, document{ID: "c"}, document{ID: "b"}); n != 2 {
+		t.Errorf("expected 2 elements to be appended, got %d", n)
+	}
+	if s.Cardinality() != 3 || s.IsEmpty() {
+		t.Errorf("expected 3 elements, got %d", s.Cardinality())
+	}
+
+	// the first element added with a key is kept
+	if d, ok := s.Get("a"); !ok || len(d.Tags) != 1 || d.Tags[0] != "x" {
+		t.Errorf("unexpected element for key a: %v", d)
+	}
+	if !s.Contains(document{ID: "a"}, document{ID: "c"}) || s.Contains(document{ID: "d"}) {
+		t.Error("unexpected membership")
+	}
+	if !s.ContainsKey("b") || s.ContainsKey("a", "d") {
+		t.Error("unexpected membership by key")
+	}
+	if !s.Keys().Equal(NewSet("a", "b", "c")) {
+		t.Errorf("unexpected keys: %v", s.Keys())
+	}
+
+	clone := s.Clone()
+	s.Remove(document{ID: "a"})
+	s.RemoveKey("b")
+	if s.Cardinality() != 1 || !s.ContainsKey("c") || clone.Cardinality() != 3 {
+		t.Errorf("unexpected sets after removal: %v and %v", s, clone)
+	}
+
+	n := 0
+	clone.Each(func(document) bool {
+		n++
+		return true
+	})
+	if n != 1 {
+		t.Errorf("Each should stop when the callback returns true, got %d calls", n)
+	}
+
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Errorf("expected empty set, got %v", s)
+	}
+}
+
+func Test_KeyedSetOperations(t *testing.T) {
+	a := NewKeyedSet(documentID)
+	a.Append(document{ID: "a"}, document{ID: "b", Tags: []string{"a"}})
+	b := NewKeyedSet(documentID)
+	b.Append(document{ID: "b"}, document{ID: "c"})
+
+	keys := func(s *KeyedSet[document, string]) []string {
+		ks := s.Keys().ToSlice()
+		sort.Strings(ks)
+		return ks
+	}
+
+	union := a.Union(b)
+	if ks := keys(union); len(ks) != 3 {
+		t.Errorf("unexpected union: %v", ks)
+	}
+	if d, _ := union.Get("b"); len(d.Tags) != 1 {
+		t.Error("the union should prefer the elements of the receiver")
+	}
+	if ks := keys(a.Intersect(b)); len(ks) != 1 || ks[0] != "b" {
+		t.Errorf("unexpected intersection: %v", ks)
+	}
+	if ks := keys(a.Difference(b)); len(ks) != 1 || ks[0] != "a" {
+		t.Errorf("unexpected difference: %v", ks)
+	}
+	if a.Equal(b) || !a.Equal(a.Clone()) || !a.Equal(a) {
+		t.Error("unexpected equality")
+	}
+}
+
+func Test_KeyedSetJSON(t *testing.T) {
+	s := NewKeyedSet(documentID)
+	s.Add(document{ID: "a", Tags: []string{"x"}})
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if string(b) != `[{"ID":"a","Tags":["x"]}]` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+
+	decoded := NewKeyedSet(documentID)
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !decoded.Equal(s) || decoded.String() != "Set{{a [x]}}" {
+		t.Errorf("unexpected decoded set: %v", decoded)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidLine is returned by WriteLines when an element cannot be written
+// as a single line, because it is empty or contains a line break.
+var ErrInvalidLine = errors.New("mapset: element cannot be written as a line")
+
+// WriteLines writes the elements of s to w as newline-delimited text, one
+// element per line in ascending order, so that the output is stable and
+// diffs nicely. The format is the usual one of block lists and allow lists
+// and can be read back with ReadLines. If any element cannot be written as a
+// line, ErrInvalidLine is returned and nothing is written to w.
+func WriteLines(w io.Writer, s Set[string]) error {
+	return writeLines(w, s.ToSlice())
+}
+
+// WriteStringerLines writes the String form of every element of s to w as
+// newline-delimited text, see WriteLines. It can be read back with
+// ReadLinesFunc.
+func WriteStringerLines[T interface {
+	comparable
+	fmt.Stringer
+}](w io.Writer, s Set[T]) error {
+	lines := make([]string, 0, s.Cardinality())
+	s.Each(func(elem T) bool {
+		lines = append(lines, elem.String())
+		return false
+	})
+	return writeLines(w, lines)
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	sort.Strings(lines)
+	for _, line := range lines {
+		if line == "" || strings.ContainsAny(line, "\r\n") {
+			return fmt.Errorf("%w: %q", ErrInvalidLine, line)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		if _, err := bw.WriteString(line); err != nil {
+			return err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadLines reads newline-delimited text from r and returns a set with one
+// element per line. Both "\n" and "\r\n" line endings are accepted and blank
+// lines are skipped. Operations on the resulting set are thread-safe.
+func ReadLines(r io.Reader) (Set[string], error) {
+	return ReadLinesFunc(r, func(line string) (string, error) {
+		return line, nil
+	})
+}
+
+// ReadLinesFunc reads newline-delimited text from r and returns a set with
+// the elements obtained by calling parse on every line, see ReadLines.
+// Reading stops at the first error returned by parse. Operations on the
+// resulting set are thread-safe.
+func ReadLinesFunc[T comparable](r io.Reader, parse func(line string) (T, error)) (Set[T], error) {
+	s := newThreadSafeSet[T]()
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line != "" {
+			v, perr := parse(line)
+			if perr != nil {
+				return nil, perr
+			}
+			s.uss.add(v)
+		}
+
+		if err == io.EOF {
+			return s, nil
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type port int
+
+func (p port) String() string {
+	return strconv.Itoa(int(p))
+}
+
+func Test_WriteReadLines(t *testing.T) {
+	s := NewSet("example.org", "bad.example", "ads.example")
+
+	var buf bytes.Buffer
+	if err := WriteLines(&buf, s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	expected := "ads.example\nbad.example\nexample.org\n"
+	if buf.String() != expected {
+		t.Errorf("expected sorted lines %q, got %q", expected, buf.String())
+	}
+
+	actual, err := ReadLines(&buf)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(actual) {
+		t.Errorf("Expected no difference, got: %v", s.SymmetricDifference(actual))
+	}
+}
+
+func Test_ReadLines(t *testing.T) {
+	actual, err := ReadLines(strings.NewReader("a\r\n\nb\n\r\nc"))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !actual.Equal(NewSet("a", "b", "c")) {
+		t.Errorf("unexpected set contents: %v", actual)
+	}
+}
+
+func Test_WriteLinesInvalid(t *testing.T) {
+	for _, elem := range []string{"", "two\nlines"} {
+		var buf bytes.Buffer
+		if err := WriteLines(&buf, NewSet(elem)); !errors.Is(err, ErrInvalidLine) {
+			t.Errorf("expected ErrInvalidLine for %q, got %v", elem, err)
+		}
+	}
+
+	s := NewSet[string]()
+	for i := 0; i < 10000; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+	s.Add("~bad\n")
+	var buf bytes.Buffer
+	if err := WriteLines(&buf, s); !errors.Is(err, ErrInvalidLine) {
+		t.Fatalf("expected ErrInvalidLine, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written, got %d bytes", buf.Len())
+	}
+}
+
+func Test_WriteStringerLines(t *testing.T) {
+	s := NewThreadUnsafeSet[port](443, 80, 8080)
+
+	var buf bytes.Buffer
+	if err := WriteStringerLines(&buf, s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	actual, err := ReadLinesFunc(&buf, func(line string) (port, error) {
+		p, err := strconv.Atoi(line)
+		return port(p), err
+	})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !actual.Equal(NewSet[port](80, 443, 8080)) {
+		t.Errorf("unexpected set contents: %v", actual)
+	}
+
+	if _, err := ReadLinesFunc(strings.NewReader("80\nhttp\n"), func(line string) (int, error) {
+		return strconv.Atoi(line)
+	}); err == nil {
+		t.Error("expected the parse error to be returned")
+	}
+}
@@ -0,0 +1,221 @@
+//go:build go1.24
+// +build go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// loadChunkLines is the number of lines handed to a parsing worker at once.
+const loadChunkLines = 4096
+
+type decompressor struct {
+	magic []byte
+	open  func(io.Reader) (io.Reader, error)
+}
+
+var (
+	decompressorsMu sync.RWMutex
+	decompressors   = []decompressor{{
+		magic: []byte{0x1f, 0x8b},
+		open: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+	}}
+)
+
+// RegisterDecompressor makes LoadFromFile decompress files starting with the
+// given magic bytes through open. Gzip is supported out of the box; other
+// codecs such as zstd are registered by the program, which keeps their
+// dependencies out of this package:
+//
+//	mapset.RegisterDecompressor([]byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.Reader, error) {
+//		return zstd.NewReader(r)
+//	})
+func RegisterDecompressor(magic []byte, open func(io.Reader) (io.Reader, error)) {
+	decompressorsMu.Lock()
+	defer decompressorsMu.Unlock()
+
+	decompressors = append(decompressors, decompressor{magic: append([]byte(nil), magic...), open: open})
+}
+
+// decompress returns a reader of the decompressed contents of br, or br
+// itself when it does not start with a registered magic.
+func decompress(br *bufio.Reader) (io.Reader, error) {
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+
+	for _, d := range decompressors {
+		magic, _ := br.Peek(len(d.magic))
+		if bytes.Equal(magic, d.magic) {
+			return d.open(br)
+		}
+	}
+	return br, nil
+}
+
+// LoadFromFile reads a set of strings from the file at path, see
+// LoadFromFileFunc.
+func LoadFromFile(path string) (*ShardedSet[string], error) {
+	return LoadFromFileFunc(path, func(line string) (string, error) {
+		return line, nil
+	})
+}
+
+// LoadFromFileFunc reads the file at path into a new sharded set, so that
+// services can warm load very large sets at startup. The file may be
+// compressed with gzip, which is the only codec built into this package, or
+// with a codec added with RegisterDecompressor such as zstd. It may hold a
+// binary snapshot written by WriteSnapshot, a JSON array, or
+// newline-delimited text as written by WriteLines, in which case every line
+// is turned into an element by parse.
+//
+// Only text is loaded in parallel: lines are read and decompressed by a
+// single goroutine, and chunks of lines are parsed and inserted by one
+// worker per CPU, so parse must be safe for concurrent use. Snapshots and
+// JSON arrays are decoded sequentially. Loading stops at the first error
+// returned by parse.
+func LoadFromFileFunc[T comparable](path string, parse func(line string) (T, error)) (*ShardedSet[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := decompress(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	br := bufio.NewReaderSize(r, 1<<16)
+	format, err := DetectFormat(br)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewShardedSet[T](0)
+	switch format {
+	case FormatSnapshot, FormatJSON:
+		if err := Decode[T](br, s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	if err := loadLines(br, s, parse); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadLines reads newline-delimited text from br and adds the parsed lines
+// to s, parsing chunks of lines concurrently.
+func loadLines[T comparable](br *bufio.Reader, s *ShardedSet[T], parse func(line string) (T, error)) error {
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		failed  = make(chan struct{})
+		loadErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			loadErr = err
+			close(failed)
+		})
+	}
+
+	chunks := make(chan []string)
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				select {
+				case <-failed:
+					continue
+				default:
+				}
+				for _, line := range chunk {
+					v, err := parse(line)
+					if err != nil {
+						fail(err)
+						break
+					}
+					s.Add(v)
+				}
+			}
+		}()
+	}
+
+	send := func(chunk []string) bool {
+		select {
+		case chunks <- chunk:
+			return true
+		case <-failed:
+			return false
+		}
+	}
+
+	chunk := make([]string, 0, loadChunkLines)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			fail(err)
+			break
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line != "" {
+			chunk = append(chunk, line)
+		}
+		if len(chunk) == loadChunkLines || (err == io.EOF && len(chunk) > 0) {
+			if !send(chunk) {
+				break
+			}
+			chunk = make([]string, 0, loadChunkLines)
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	return loadErr
+}
@@ -0,0 +1,152 @@
+//go:build go1.24
+// +build go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func writeFile(t *testing.T, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "set")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	return path
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func Test_LoadFromFile(t *testing.T) {
+	expected := NewSet[string]()
+	var lines bytes.Buffer
+	for i := 0; i < 3*loadChunkLines+7; i++ {
+		expected.Add(strconv.Itoa(i))
+		fmt.Fprintf(&lines, "%d\r\n", i)
+	}
+	lines.WriteString("\n0\n")
+
+	var snapshot bytes.Buffer
+	if err := WriteSnapshot(&snapshot, expected); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"Lines":          lines.Bytes(),
+		"GzipLines":      gzipped(t, lines.Bytes()),
+		"Snapshot":       snapshot.Bytes(),
+		"GzipSnapshot":   gzipped(t, snapshot.Bytes()),
+		"GzipJSON":       gzipped(t, []byte(`["0", "1"]`)),
+		"EmptyGzip":      gzipped(t, nil),
+		"EmptyFile":      nil,
+		"NoFinalNewline": []byte("0\n1"),
+		"BracketLines":   []byte("[a]\n[b]\n"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, err := LoadFromFile(writeFile(t, data))
+			if err != nil {
+				t.Fatalf("Error should be nil: %v", err)
+			}
+
+			want := expected
+			switch name {
+			case "GzipJSON", "NoFinalNewline":
+				want = NewSet("0", "1")
+			case "EmptyGzip", "EmptyFile":
+				want = NewSet[string]()
+			case "BracketLines":
+				want = NewSet("[a]", "[b]")
+			}
+			if !s.Equal(want) {
+				t.Errorf("expected %d elements, got %d", want.Cardinality(), s.Cardinality())
+			}
+		})
+	}
+}
+
+func Test_LoadFromFileFunc(t *testing.T) {
+	path := writeFile(t, gzipped(t, []byte("1\n2\n3\n")))
+
+	s, err := LoadFromFileFunc(path, strconv.Atoi)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(NewSet(1, 2, 3)) {
+		t.Errorf("expected 1, 2 and 3, got %v", s)
+	}
+
+	path = writeFile(t, []byte("1\nx\n3\n"))
+	if _, err := LoadFromFileFunc(path, strconv.Atoi); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+
+	if _, err := LoadFromFile(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing file error, got %v", err)
+	}
+}
+
+func Test_RegisterDecompressor(t *testing.T) {
+	// a toy codec that prefixes the contents with a magic
+	magic := []byte("TOY!")
+	RegisterDecompressor(magic, func(r io.Reader) (io.Reader, error) {
+		if _, err := io.ReadFull(r, make([]byte, len(magic))); err != nil {
+			return nil, err
+		}
+		return r, nil
+	})
+
+	s, err := LoadFromFile(writeFile(t, append(magic, "a\nb\n"...)))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(NewSet("a", "b")) {
+		t.Errorf("expected a and b, got %v", s)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import "sync"
+
+// LockPolicy selects how a thread-safe set created with NewSetWithOptions
+// arbitrates between readers and writers, see WithLockPolicy.
+type LockPolicy int
+
+const (
+	// LockReadWrite guards the set with a sync.RWMutex: readers share the
+	// lock, and a waiting writer holds back new readers. It is the default
+	// and suits most workloads.
+	LockReadWrite LockPolicy = iota
+
+	// LockReaderPreferring lets readers share the lock even while writers
+	// are waiting, so reads are never delayed by queued writes. Writers can
+	// be starved by a continuous flow of reads, which makes it a fit for
+	// read-mostly sets updated now and then.
+	LockReaderPreferring
+
+	// LockWriterPreferring lets readers share the lock, but makes new
+	// readers wait as long as a writer holds the lock or waits for it, so
+	// writes are never delayed by a continuous flow of reads. Readers can
+	// be starved by a continuous flow of writes, which makes it a fit for
+	// bulk ingestion, where the set is mostly written and reads can wait.
+	LockWriterPreferring
+)
+
+func (p LockPolicy) String() string {
+	switch p {
+	case LockReadWrite:
+		return "read-write"
+	case LockReaderPreferring:
+		return "reader-preferring"
+	case LockWriterPreferring:
+		return "writer-preferring"
+	}
+	return "unknown"
+}
+
+// WithLockPolicy selects the locking strategy of a thread-safe set, see
+// LockPolicy. It has no effect on thread-unsafe sets. Like the other
+// options, it is not inherited by the sets returned by operations such as
+// Union, Intersect or Clone, which use the default LockReadWrite.
+func WithLockPolicy[T comparable](p LockPolicy) Option[T] {
+	return func(o *options[T]) {
+		o.lockPolicy = p
+	}
+}
+
+// rwLocker is the locking interface used by threadSafeSet.
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// newLocker returns the lock implementing p, or nil for the default
+// sync.RWMutex embedded in threadSafeSet.
+func newLocker(p LockPolicy) rwLocker {
+	switch p {
+	case LockReaderPreferring:
+		return &readerPreferringLock{}
+	case LockWriterPreferring:
+		return newWriterPreferringLock()
+	}
+	return nil
+}
+
+// readerPreferringLock is a readers-writer lock where the first reader in
+// takes the write lock for all of them and the last reader out releases it,
+// so readers keep entering as long as one of them holds it.
+//
+// Only the first reader competes with writers for w, with the fairness of
+// sync.Mutex: it may queue behind writers, and the readers arriving while it
+// waits queue behind it on mu. Once the readers hold w, writers wait until
+// the last reader leaves.
+type readerPreferringLock struct {
+	w       sync.Mutex // held by a writer or by the readers as a group
+	mu      sync.Mutex // guards readers
+	readers int
+}
+
+func (l *readerPreferringLock) Lock() {
+	l.w.Lock()
+}
+
+func (l *readerPreferringLock) Unlock() {
+	l.w.Unlock()
+}
+
+func (l *readerPreferringLock) RLock() {
+	l.mu.Lock()
+	l.readers++
+	if l.readers == 1 {
+		l.w.Lock()
+	}
+	l.mu.Unlock()
+}
+
+func (l *readerPreferringLock) RUnlock() {
+	l.mu.Lock()
+	l.readers--
+	if l.readers == 0 {
+		// the last reader may not be the one that locked, which
+		// sync.Mutex allows
+		l.w.Unlock()
+	}
+	l.mu.Unlock()
+}
+
+// writerPreferringLock is a readers-writer lock where readers wait while a
+// writer holds the lock or is waiting for it, and writers only wait for the
+// readers already in.
+type writerPreferringLock struct {
+	mu      sync.Mutex
+	changed *sync.Cond // signaled when the lock is released
+	readers int
+	writing bool
+	waiting int // writers waiting for the lock
+}
+
+func newWriterPreferringLock() *writerPreferringLock {
+	l := &writerPreferringLock{}
+	l.changed = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *writerPreferringLock) Lock() {
+	l.mu.Lock()
+	l.waiting++
+	for l.writing || l.readers > 0 {
+		l.changed.Wait()
+	}
+	l.waiting--
+	l.writing = true
+	l.mu.Unlock()
+}
+
+func (l *writerPreferringLock) Unlock() {
+	l.mu.Lock()
+	l.writing = false
+	l.changed.Broadcast()
+	l.mu.Unlock()
+}
+
+func (l *writerPreferringLock) RLock() {
+	l.mu.Lock()
+	for l.writing || l.waiting > 0 {
+		l.changed.Wait()
+	}
+	l.readers++
+	l.mu.Unlock()
+}
+
+func (l *writerPreferringLock) RUnlock() {
+	l.mu.Lock()
+	l.readers--
+	if l.readers == 0 {
+		l.changed.Broadcast()
+	}
+	l.mu.Unlock()
+}
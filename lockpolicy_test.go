@@ -0,0 +1,137 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_WithLockPolicy(t *testing.T) {
+	for _, p := range []LockPolicy{LockReadWrite, LockReaderPreferring, LockWriterPreferring} {
+		t.Run(p.String(), func(t *testing.T) {
+			s := NewSetWithOptions(WithLockPolicy[int](p))
+
+			var wg sync.WaitGroup
+			for g := 0; g < 4; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < N; i++ {
+						s.Add(g*N + i)
+						s.ContainsOne(i)
+						s.Cardinality()
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			if s.Cardinality() != 4*N {
+				t.Errorf("expected %d elements, got %d", 4*N, s.Cardinality())
+			}
+			if u := s.Union(NewSet(-1)); u.Cardinality() != 4*N+1 {
+				t.Errorf("expected %d elements in the union, got %d", 4*N+1, u.Cardinality())
+			}
+		})
+	}
+}
+
+func Test_ReaderPreferringLock(t *testing.T) {
+	var l readerPreferringLock
+	l.RLock()
+
+	written := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(written)
+		l.Unlock()
+	}()
+	// give the writer time to queue up
+	time.Sleep(10 * time.Millisecond)
+
+	// a new reader enters although a writer is waiting
+	read := make(chan struct{})
+	go func() {
+		l.RLock()
+		close(read)
+	}()
+	select {
+	case <-read:
+	case <-time.After(time.Second):
+		t.Fatal("the reader should not wait for the queued writer")
+	}
+
+	select {
+	case <-written:
+		t.Fatal("the writer should wait for the readers")
+	default:
+	}
+	l.RUnlock()
+	l.RUnlock()
+	<-written
+}
+
+func Test_WriterPreferringLock(t *testing.T) {
+	l := newWriterPreferringLock()
+	l.RLock()
+
+	written := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(written)
+		l.Unlock()
+	}()
+	// give the writer time to queue up
+	time.Sleep(10 * time.Millisecond)
+
+	// a new reader waits for the queued writer
+	read := make(chan struct{})
+	go func() {
+		l.RLock()
+		close(read)
+		l.RUnlock()
+	}()
+	select {
+	case <-read:
+		t.Fatal("the reader should wait for the queued writer")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	l.RUnlock()
+	<-written
+	select {
+	case <-read:
+	case <-time.After(time.Second):
+		t.Fatal("the reader should enter once the writer is done")
+	}
+}
+
+func Test_LockPolicyString(t *testing.T) {
+	if s := LockPolicy(42).String(); s != "unknown" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
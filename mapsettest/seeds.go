@@ -0,0 +1,130 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package mapsettest provides seed corpora for fuzz targets of code that
+// decodes sets, so that new serialization formats and the programs that
+// decode user-supplied sets are fuzzed from the same interesting inputs as
+// this module: valid encodings of typical sets as well as hostile ones such
+// as truncated data, absurd lengths and deep nesting.
+//
+//	func FuzzConfig(f *testing.F) {
+//		for _, seed := range mapsettest.JSONSeeds() {
+//			f.Add(seed)
+//		}
+//		f.Fuzz(func(t *testing.T, data []byte) {
+//			_ = json.Unmarshal(data, mapset.NewSet[string]())
+//		})
+//	}
+package mapsettest
+
+import (
+	"bytes"
+	"encoding"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// JSONSeeds returns JSON inputs for fuzzing the decoding of sets.
+func JSONSeeds() [][]byte {
+	seeds := []string{
+		`[]`,
+		`["a","b","c"]`,
+		`[1,2,3]`,
+		`[1,1,1]`,
+		`[-0,0,1e308,-1e308,1.5]`,
+		`["\u0000","\ud800","\\\"",""]`,
+		`[null]`,
+		`[true,false]`,
+		`[[1],[2]]`,
+		`{"a":1}`,
+		`null`,
+		`[1,`,
+		`["a"`,
+		`[1]]`,
+		" \t\n[ 1 , 2 ]\n",
+		strings.Repeat("[", 10000),
+		`[1e999999]`,
+		`[99999999999999999999999999999]`,
+	}
+	return toBytes(seeds)
+}
+
+// BinarySeeds returns inputs for fuzzing the decoding of sets from
+// snapshots, as read by mapset.ReadSnapshot and UnmarshalBinary.
+func BinarySeeds() [][]byte {
+	var seeds [][]byte
+	for _, s := range []mapset.Set[string]{mapset.NewSet[string](), mapset.NewSet("a", "b", "c")} {
+		b, _ := s.(encoding.BinaryMarshaler).MarshalBinary()
+		seeds = append(seeds, b)
+	}
+	ints, _ := mapset.NewSet(1, -1, 1<<40).(encoding.BinaryMarshaler).MarshalBinary()
+	seeds = append(seeds, ints)
+	var indexed bytes.Buffer
+	_ = mapset.WriteIndexedSnapshot(&indexed, mapset.NewSet("a", "b", "c"))
+	seeds = append(seeds, indexed.Bytes())
+
+	valid := seeds[1]
+	seeds = append(seeds,
+		nil,
+		[]byte("MSET"),
+		valid[:len(valid)/2],
+		append(append([]byte(nil), valid...), 0xff),
+		// a future version and a header announcing a huge count
+		[]byte("MSET\x7f\x02\x01\x00"),
+		[]byte("MSET\x01\x0b\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01"),
+		// a header longer than the data
+		[]byte("MSET\x01\xff\xff\xff\xff\x0f"),
+		bytes.Repeat([]byte{0xff}, 64),
+	)
+	return seeds
+}
+
+// TextSeeds returns inputs for fuzzing the decoding of sets from text, as
+// read by mapset.DecodeText, UnmarshalText and mapset.ReadLines.
+func TextSeeds() [][]byte {
+	seeds := []string{
+		"",
+		"a",
+		"a,b,c",
+		" a , b ,, c ,",
+		"1,2,3",
+		"-1,1e3,0x10",
+		"a\nb\r\nc\n\n",
+		",,,,",
+		"\x00,\xff, ",
+		strings.Repeat("a,", 1000),
+		strings.Repeat("x", 1<<16),
+	}
+	return toBytes(seeds)
+}
+
+func toBytes(seeds []string) [][]byte {
+	out := make([][]byte, len(seeds))
+	for i, s := range seeds {
+		out[i] = []byte(s)
+	}
+	return out
+}
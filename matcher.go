@@ -0,0 +1,157 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"github.com/deckarep/golang-set/v2/internal/phash"
+)
+
+// CompileMatcher returns a function reporting whether a string is one of
+// the elements of s, compiled into a perfect hash table from the current
+// contents of s. A lookup costs two hashes and a single string comparison,
+// takes no lock and never allocates, which suits hot request-filtering
+// paths. The matcher is immutable: later changes of s are not reflected.
+func CompileMatcher(s Set[string]) func(string) bool {
+	elems := s.ToSlice()
+	table, err := phash.Build(elems)
+	if err != nil {
+		// no perfect hash was found, fall back to a map that is never
+		// written again and thus needs no lock either
+		m := make(map[string]struct{}, len(elems))
+		for _, elem := range elems {
+			m[elem] = struct{}{}
+		}
+		return func(v string) bool {
+			_, found := m[v]
+			return found
+		}
+	}
+	return table.Contains
+}
+
+// CompileSubstringMatcher returns a function reporting whether a string
+// contains any of the elements of s, compiled into an Aho-Corasick
+// automaton from the current contents of s, so that the cost of a match is
+// linear in the length of the string whatever the number of elements. The
+// matcher never allocates and is safe for concurrent use. It is immutable:
+// later changes of s are not reflected. An empty element matches every
+// string.
+func CompileSubstringMatcher(s Set[string]) func(string) bool {
+	m := newSubstringMatcher(s.ToSlice())
+	return m.match
+}
+
+// substringMatcher is an Aho-Corasick automaton compiled into a complete
+// DFA. Bytes are mapped to classes first, one per byte that appears in a
+// pattern and the class 0 for all other bytes, which keeps the transition
+// table small.
+type substringMatcher struct {
+	classes [256]uint16
+	width   int
+	// next holds the transitions, next[state*width+class] is the state
+	// reached from state on a byte of class.
+	next []int32
+	// final reports the states where a pattern ends.
+	final []bool
+}
+
+func newSubstringMatcher(patterns []string) *substringMatcher {
+	m := &substringMatcher{width: 1}
+	for _, p := range patterns {
+		for i := 0; i < len(p); i++ {
+			if m.classes[p[i]] == 0 {
+				m.classes[p[i]] = uint16(m.width)
+				m.width++
+			}
+		}
+	}
+
+	// build the trie, with -1 for the missing transitions
+	m.addState()
+	for _, p := range patterns {
+		state := int32(0)
+		for i := 0; i < len(p); i++ {
+			t := int(state)*m.width + int(m.classes[p[i]])
+			if m.next[t] < 0 {
+				m.next[t] = m.addState()
+			}
+			state = m.next[t]
+		}
+		m.final[state] = true
+	}
+
+	// turn it into a DFA breadth first, so that the failure state of every
+	// state, the longest proper suffix of its path that is in the trie, is
+	// complete when the state is visited
+	fail := make([]int32, len(m.final))
+	queue := make([]int32, 0, len(m.final))
+	for c := 0; c < m.width; c++ {
+		if child := m.next[c]; child < 0 {
+			m.next[c] = 0
+		} else {
+			queue = append(queue, child)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		m.final[state] = m.final[state] || m.final[fail[state]]
+
+		for c := 0; c < m.width; c++ {
+			t := int(state)*m.width + c
+			failNext := m.next[int(fail[state])*m.width+c]
+			if child := m.next[t]; child < 0 {
+				m.next[t] = failNext
+			} else {
+				fail[child] = failNext
+				queue = append(queue, child)
+			}
+		}
+	}
+	return m
+}
+
+func (m *substringMatcher) addState() int32 {
+	for c := 0; c < m.width; c++ {
+		m.next = append(m.next, -1)
+	}
+	m.final = append(m.final, false)
+	return int32(len(m.final) - 1)
+}
+
+func (m *substringMatcher) match(s string) bool {
+	if m.final[0] {
+		return true
+	}
+	state := 0
+	for i := 0; i < len(s); i++ {
+		state = int(m.next[state*m.width+int(m.classes[s[i]])])
+		if m.final[state] {
+			return true
+		}
+	}
+	return false
+}
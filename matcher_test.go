@@ -0,0 +1,95 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func Test_CompileMatcher(t *testing.T) {
+	s := NewSet("GET", "HEAD", "")
+	match := CompileMatcher(s)
+	s.Add("POST") // not reflected
+
+	for v, want := range map[string]bool{"GET": true, "HEAD": true, "": true, "POST": false, "GE": false, "get": false} {
+		if got := match(v); got != want {
+			t.Errorf("expected match(%q) to be %v, got %v", v, want, got)
+		}
+	}
+
+	if CompileMatcher(NewSet[string]())("a") {
+		t.Error("expected a matcher of the empty set to match nothing")
+	}
+}
+
+func Test_CompileSubstringMatcher(t *testing.T) {
+	match := CompileSubstringMatcher(NewSet("he", "she", "his", "hers"))
+	for v, want := range map[string]bool{"ushers": true, "ahis": true, "hxe": false, "h": false, "": false, "xxshe": true} {
+		if got := match(v); got != want {
+			t.Errorf("expected match(%q) to be %v, got %v", v, want, got)
+		}
+	}
+
+	if !CompileSubstringMatcher(NewSet(""))("anything") {
+		t.Error("expected the empty element to match every string")
+	}
+	if CompileSubstringMatcher(NewSet[string]())("anything") {
+		t.Error("expected a matcher of the empty set to match nothing")
+	}
+}
+
+func Test_CompileSubstringMatcherRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	word := func(n int) string {
+		b := make([]byte, 1+r.Intn(n))
+		for i := range b {
+			b[i] = "abc\xff"[r.Intn(4)]
+		}
+		return string(b)
+	}
+
+	for round := 0; round < 50; round++ {
+		patterns := NewThreadUnsafeSet[string]()
+		for i := 0; i < 1+r.Intn(8); i++ {
+			patterns.Add(word(4))
+		}
+		match := CompileSubstringMatcher(patterns)
+
+		for i := 0; i < 100; i++ {
+			v := word(12)
+			want := false
+			patterns.Each(func(p string) bool {
+				want = strings.Contains(v, p)
+				return want
+			})
+			if got := match(v); got != want {
+				t.Fatalf("expected match(%q) to be %v for %v, got %v", v, want, patterns, got)
+			}
+		}
+	}
+}
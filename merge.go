@@ -0,0 +1,88 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+)
+
+// MergeSorted returns an iterator over the union of the given sets in
+// ascending order, merging them with a heap instead of sorting the union.
+// Every element is yielded once, even when it is present in several sets.
+// Each set is sorted when the iteration starts, unless it is a SortedSet.
+func MergeSorted[T cmp.Ordered](sets ...Set[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := make(mergeHeap[T], 0, len(sets))
+		for _, s := range sets {
+			if sorted := Sorted(s); len(sorted) > 0 {
+				h = append(h, sorted)
+			}
+		}
+		heap.Init(&h)
+
+		var last T
+		for i := 0; h.Len() > 0; i++ {
+			v := h[0][0]
+			if i == 0 || cmp.Compare(v, last) != 0 {
+				if !yield(v) {
+					return
+				}
+				last = v
+			}
+
+			h[0] = h[0][1:]
+			if len(h[0]) == 0 {
+				heap.Pop(&h)
+			} else {
+				heap.Fix(&h, 0)
+			}
+		}
+	}
+}
+
+// mergeHeap is a min-heap of sorted, non-empty slices ordered by their head.
+type mergeHeap[T cmp.Ordered] [][]T
+
+func (h mergeHeap[T]) Len() int           { return len(h) }
+func (h mergeHeap[T]) Less(i, j int) bool { return cmp.Less(h[i][0], h[j][0]) }
+func (h mergeHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap[T]) Push(x any) {
+	*h = append(*h, x.([]T))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,81 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func Test_MergeSorted(t *testing.T) {
+	a := NewSet(5, 1, 9)
+	b := NewThreadUnsafeSet(2, 9, 4)
+	c := NewSet[int]()
+	d := NewSet(3, 1, 10, 7)
+
+	actual := slices.Collect(MergeSorted(a, b, c, d))
+	expected := []int{1, 2, 3, 4, 5, 7, 9, 10}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	var first []int
+	for v := range MergeSorted(a, b, c, d) {
+		if len(first) == 3 {
+			break
+		}
+		first = append(first, v)
+	}
+	if !slices.Equal(first, []int{1, 2, 3}) {
+		t.Errorf("iteration should stop on the way, got %v", first)
+	}
+
+	if merged := slices.Collect(MergeSorted[string]()); len(merged) != 0 {
+		t.Errorf("merging no sets should yield nothing, got %v", merged)
+	}
+}
+
+func Test_MergeSortedNaN(t *testing.T) {
+	nan := math.NaN()
+	actual := slices.Collect(MergeSorted(NewSet(2.0, nan), NewSet(1.0)))
+	if len(actual) != 3 || !math.IsNaN(actual[0]) || actual[1] != 1 || actual[2] != 2 {
+		t.Errorf("NaNs should be ordered before other values, got %v", actual)
+	}
+}
+
+func Test_MergeSortedSortedSets(t *testing.T) {
+	var merged []string
+	for v := range MergeSorted[string](NewSortedSet("c", "a"), NewSet("b", "d"), NewSortedSet("d")) {
+		merged = append(merged, v)
+	}
+	if !slices.Equal(merged, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected merged elements: %v", merged)
+	}
+}
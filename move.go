@@ -0,0 +1,127 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// Move removes v from src and adds it to dst, and reports whether v was in
+// src. When both are thread-safe sets created by NewSet, possibly with
+// options or observed by a HistorySet or an ObservableSet, the move holds
+// the locks of both sets, taken in a fixed order so that concurrent moves
+// in opposite directions don't deadlock. No operation on either set runs
+// between the removal and the addition, which suits state-machine
+// transitions such as moving tasks from a pending set to an active one. A
+// goroutine checking the sets one after the other may still find v in
+// both or in neither, when the move happens between its two checks.
+//
+// The options of dst validate v before the locks are taken, and observers
+// are notified after they are released. Otherwise, for instance with
+// thread-unsafe sets or when dst has a maximum cardinality, v is removed
+// from src and then added to dst. If dst rejects v, it is put back into
+// src and Move returns false.
+func Move[T comparable](src, dst Set[T], v T) bool {
+	if src == dst {
+		return src.ContainsOne(v)
+	}
+
+	sbase, _, sobs, sok := moveChain(src)
+	dbase, dguards, dobs, dok := moveChain(dst)
+	s, sts := sbase.(*threadSafeSet[T])
+	d, dts := dbase.(*threadSafeSet[T])
+	if !sok || !dok || !sts || !dts {
+		if len(RemovedWhich(src, v)) == 0 {
+			return false
+		}
+		if !dst.Add(v) && !dst.ContainsOne(v) {
+			src.Add(v)
+			return false
+		}
+		return true
+	}
+	if s == d {
+		return s.ContainsOne(v)
+	}
+	for _, g := range dguards {
+		if g.check(v) != nil {
+			return false
+		}
+	}
+
+	first, second := s, d
+	if !lockOrder(s, d) {
+		first, second = second, first
+	}
+	first.Lock()
+	second.Lock()
+	moved := s.uss.contains(v)
+	added := moved && !d.uss.contains(v)
+	if moved {
+		s.uss.Remove(v)
+		d.uss.add(v)
+	}
+	second.Unlock()
+	first.Unlock()
+
+	if moved {
+		for _, o := range sobs {
+			o.notify(OpRemove, []T{v})
+		}
+	}
+	if added {
+		for _, o := range dobs {
+			o.notify(OpAdd, []T{v})
+		}
+	}
+	return moved
+}
+
+// moveChain strips the decorators of s and returns the underlying set along
+// with the options and observers that take part in a move, or false if one
+// of the decorators can't, such as a maximum cardinality whose FullPolicy
+// may mutate the set.
+func moveChain[T comparable](s Set[T]) (base Set[T], guards []*guardedSet[T], observers []*observedSet[T], ok bool) {
+	for {
+		switch d := s.(type) {
+		case *guardedSet[T]:
+			if d.opts.maxCardinality > 0 {
+				return nil, nil, nil, false
+			}
+			guards = append(guards, d)
+		case *observedSet[T]:
+			observers = append(observers, d)
+		case *WALSet[T]:
+			// mutations of a WALSet must be logged in the order they apply
+			return nil, nil, nil, false
+		case *VersionedSet[T]:
+			// and the ones of a VersionedSet recorded in that order
+			return nil, nil, nil, false
+		}
+
+		w, isWrapper := s.(wrapper[T])
+		if !isWrapper {
+			return s, guards, observers, true
+		}
+		s = w.unwrap()
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_Move(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		pending, active := ctor(1, 2), ctor(3)
+
+		if !Move(pending, active, 1) {
+			t.Error("1 should have been moved")
+		}
+		if Move(pending, active, 4) {
+			t.Error("4 is not pending and should not be moved")
+		}
+		if !pending.Equal(ctor(2)) || !active.Equal(ctor(1, 3)) {
+			t.Errorf("unexpected sets %v and %v", pending, active)
+		}
+
+		// moving to a set that already holds the element
+		pending.Add(3)
+		if !Move(pending, active, 3) || pending.ContainsOne(3) || active.Cardinality() != 2 {
+			t.Errorf("unexpected sets %v and %v", pending, active)
+		}
+
+		if !Move(active, active, 1) || Move(active, active, 2) || !active.ContainsOne(1) {
+			t.Error("moving within a set should only report membership")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_MoveRejected(t *testing.T) {
+	src := NewSet(0, 1)
+	dst := NewSetWithOptions(RejectZero[int]())
+
+	if Move(src, dst, 0) {
+		t.Error("a rejected element should not be moved")
+	}
+	if !src.Contains(0, 1) || !dst.IsEmpty() {
+		t.Errorf("a rejected element should stay in the source, got %v and %v", src, dst)
+	}
+	if !Move(src, dst, 1) || !dst.ContainsOne(1) {
+		t.Errorf("1 should have been moved, got %v and %v", src, dst)
+	}
+}
+
+func Test_MoveDecorated(t *testing.T) {
+	src := NewHistorySet[int](NewSet(0, 1, 2), 0)
+	dst := NewHistorySet[int](NewSetWithOptions(RejectZero[int]()), 0)
+
+	if !Move[int](src, dst, 1) || src.ContainsOne(1) || !dst.ContainsOne(1) {
+		t.Errorf("1 should have been moved, got %v and %v", src, dst)
+	}
+	if Move[int](src, dst, 0) || !src.ContainsOne(0) {
+		t.Errorf("a rejected element should stay in the source, got %v and %v", src, dst)
+	}
+	if h := src.History(); len(h) != 1 || h[0].Op != OpRemove || h[0].Elems[0] != 1 {
+		t.Errorf("the removal should be recorded, got %v", h)
+	}
+	if h := dst.History(); len(h) != 1 || h[0].Op != OpAdd || h[0].Elems[0] != 1 {
+		t.Errorf("the addition should be recorded, got %v", h)
+	}
+}
+
+func Test_MoveConcurrent(t *testing.T) {
+	a, b := NewSet[int](), NewSet[int]()
+	for i := 0; i < N; i++ {
+		a.Add(i)
+	}
+
+	// elements bounce between the sets while a reader checks that every
+	// element is always in exactly one of them
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				if g%2 == 0 {
+					Move(a, b, i)
+				} else {
+					Move(b, a, i)
+				}
+			}
+		}(g)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-done:
+			if a.Cardinality()+b.Cardinality() != N {
+				t.Errorf("expected %d elements in total, got %d and %d", N, a.Cardinality(), b.Cardinality())
+			}
+			return
+		default:
+		}
+		// Union locks both sets, so it sees a consistent state
+		if u := a.Union(b); u.Cardinality() != N {
+			t.Fatalf("expected %d elements in the union, got %d", N, u.Cardinality())
+		}
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package msgpackset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// decoder reads MessagePack values from the front of data.
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data) < n {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidData)
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+func (d *decoder) byte() (byte, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// uint reads a big endian unsigned integer of n bytes.
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (d *decoder) arrayHeader() (int, error) {
+	c, err := d.byte()
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	switch {
+	case c&0xf0 == 0x90:
+		n = uint64(c & 0x0f)
+	case c == 0xdc:
+		n, err = d.uint(2)
+	case c == 0xdd:
+		n, err = d.uint(4)
+	default:
+		return 0, fmt.Errorf("%w: expected an array, got type byte %#x", ErrInvalidData, c)
+	}
+	return int(n), err
+}
+
+// value decodes the next value into v, converting numbers to the kind of v
+// when they fit.
+func (d *decoder) value(v reflect.Value) error {
+	c, err := d.byte()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return setUint(v, uint64(c))
+	case c >= 0xe0:
+		return setInt(v, int64(int8(c)))
+	case c&0xe0 == 0xa0:
+		return d.str(v, int(c&0x1f))
+	}
+
+	switch c {
+	case 0xc2, 0xc3:
+		if v.Kind() != reflect.Bool {
+			return mismatch(v, "a boolean")
+		}
+		v.SetBool(c == 0xc3)
+		return nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return err
+		}
+		return setUint(v, u)
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		u, err := d.uint(n)
+		if err != nil {
+			return err
+		}
+		// sign extend from n bytes
+		shift := 64 - 8*n
+		return setInt(v, int64(u<<shift)>>shift)
+	case 0xca:
+		u, err := d.uint(4)
+		if err != nil {
+			return err
+		}
+		return setFloat(v, float64(math.Float32frombits(uint32(u))))
+	case 0xcb:
+		u, err := d.uint(8)
+		if err != nil {
+			return err
+		}
+		return setFloat(v, math.Float64frombits(u))
+	case 0xd9, 0xc4:
+		return d.strN(v, 1)
+	case 0xda, 0xc5:
+		return d.strN(v, 2)
+	case 0xdb, 0xc6:
+		return d.strN(v, 4)
+	}
+	return fmt.Errorf("%w: unsupported type byte %#x", ErrInvalidData, c)
+}
+
+// strN decodes a string or binary value whose length takes n bytes.
+func (d *decoder) strN(v reflect.Value, n int) error {
+	length, err := d.uint(n)
+	if err != nil {
+		return err
+	}
+	if length > uint64(len(d.data)) {
+		return fmt.Errorf("%w: unexpected end of data", ErrInvalidData)
+	}
+	return d.str(v, int(length))
+}
+
+func (d *decoder) str(v reflect.Value, n int) error {
+	if v.Kind() != reflect.String {
+		return mismatch(v, "a string")
+	}
+	b, err := d.next(n)
+	if err != nil {
+		return err
+	}
+	v.SetString(string(b))
+	return nil
+}
+
+func setUint(v reflect.Value, u uint64) error {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.OverflowUint(u) {
+			return overflow(v, u)
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if u > math.MaxInt64 || v.OverflowInt(int64(u)) {
+			return overflow(v, u)
+		}
+		v.SetInt(int64(u))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(u))
+		return nil
+	}
+	return mismatch(v, "an integer")
+}
+
+func setInt(v reflect.Value, i int64) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(i) {
+			return overflow(v, i)
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(i))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return overflow(v, i)
+	}
+	return mismatch(v, "an integer")
+}
+
+func setFloat(v reflect.Value, f float64) error {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f)
+		return nil
+	}
+	return mismatch(v, "a float")
+}
+
+func mismatch(v reflect.Value, got string) error {
+	return fmt.Errorf("%w: cannot decode %s into %v", ErrInvalidData, got, v.Type())
+}
+
+func overflow(v reflect.Value, n interface{}) error {
+	return fmt.Errorf("%w: %v overflows %v", ErrInvalidData, n, v.Type())
+}
@@ -0,0 +1,42 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package msgpackset encodes sets as MessagePack arrays, so that they
+// survive round-trips through MessagePack based RPC layers as plain arrays
+// that any MessagePack implementation can read. It has no dependencies:
+// elements of boolean, integer, floating-point and string kinds are encoded
+// by the package itself.
+//
+// Marshal and Unmarshal work on any set. The Set wrapper implements the
+// MarshalMsgpack and UnmarshalMsgpack methods recognized by common
+// MessagePack libraries such as github.com/vmihailenco/msgpack, so that
+// sets embedded in messages are encoded transparently:
+//
+//	type Message struct {
+//		Tags msgpackset.Set[string]
+//	}
+//
+//	msg := Message{Tags: msgpackset.Wrap(mapset.NewSet("a", "b"))}
+package msgpackset
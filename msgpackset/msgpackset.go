@@ -0,0 +1,226 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package msgpackset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// ErrUnsupportedType is returned when the elements of a set are not of a
+// boolean, integer, floating-point or string kind.
+var ErrUnsupportedType = errors.New("msgpackset: unsupported element type")
+
+// ErrInvalidData is returned when decoding data that is not a valid
+// MessagePack array of elements of the set's type.
+var ErrInvalidData = errors.New("msgpackset: invalid data")
+
+// Marshal encodes the elements of s as a MessagePack array, in no
+// particular order.
+func Marshal[T comparable](s mapset.Set[T]) ([]byte, error) {
+	if !supported(elemType[T]()) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, elemType[T]())
+	}
+
+	elems := s.ToSlice()
+	b := appendArrayHeader(make([]byte, 0, 1+len(elems)*9), len(elems))
+	for i := range elems {
+		b = appendValue(b, reflect.ValueOf(&elems[i]).Elem())
+	}
+	return b, nil
+}
+
+// Unmarshal decodes a MessagePack array from data and adds its elements to
+// s. Integers and floats are converted to the element type when they fit
+// into it, and strings may be encoded as binary data.
+func Unmarshal[T comparable](data []byte, s mapset.Set[T]) error {
+	typ := elemType[T]()
+	if !supported(typ) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedType, typ)
+	}
+
+	d := decoder{data: data}
+	n, err := d.arrayHeader()
+	if err != nil {
+		return err
+	}
+	// every element takes at least a byte, which bounds the length of
+	// data announced by hostile headers
+	if n > len(d.data) {
+		return fmt.Errorf("%w: %d elements announced in %d bytes", ErrInvalidData, n, len(d.data))
+	}
+
+	elems := make([]T, n)
+	for i := range elems {
+		if err := d.value(reflect.ValueOf(&elems[i]).Elem()); err != nil {
+			return err
+		}
+	}
+	if len(d.data) > 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidData, len(d.data))
+	}
+	s.Append(elems...)
+	return nil
+}
+
+// Set wraps a set to implement the MarshalMsgpack and UnmarshalMsgpack
+// methods of MessagePack libraries.
+type Set[T comparable] struct {
+	mapset.Set[T]
+}
+
+// Wrap returns s wrapped for MessagePack libraries.
+func Wrap[T comparable](s mapset.Set[T]) Set[T] {
+	return Set[T]{Set: s}
+}
+
+// MarshalMsgpack encodes the set as a MessagePack array.
+func (s Set[T]) MarshalMsgpack() ([]byte, error) {
+	if s.Set == nil {
+		return []byte{0xc0}, nil
+	}
+	return Marshal(s.Set)
+}
+
+// UnmarshalMsgpack adds the elements of a MessagePack array to the set,
+// creating a thread-safe set first if the wrapper is empty. Nil leaves the
+// set unchanged.
+func (s *Set[T]) UnmarshalMsgpack(data []byte) error {
+	if len(data) == 1 && data[0] == 0xc0 {
+		return nil
+	}
+	if s.Set == nil {
+		s.Set = mapset.NewSet[T]()
+	}
+	return Unmarshal(data, s.Set)
+}
+
+func elemType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func supported(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func appendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return appendUint16(append(b, 0xdc), uint16(n))
+	}
+	return appendUint32(append(b, 0xdd), uint32(n))
+}
+
+// appendValue appends the most compact encoding of v, whose kind must be
+// supported.
+func appendValue(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case reflect.String:
+		return appendString(b, v.String())
+	case reflect.Float32:
+		return appendUint32(append(b, 0xca), math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		return appendUint64(append(b, 0xcb), math.Float64bits(v.Float()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint(b, v.Uint())
+	}
+
+	i := v.Int()
+	if i >= 0 {
+		return appendUint(b, uint64(i))
+	}
+	switch {
+	case i >= -32:
+		return append(b, byte(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return appendUint16(append(b, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return appendUint32(append(b, 0xd2), uint32(i))
+	}
+	return appendUint64(append(b, 0xd3), uint64(i))
+}
+
+func appendUint(b []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(b, byte(u))
+	case u <= math.MaxUint8:
+		return append(b, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return appendUint16(append(b, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return appendUint32(append(b, 0xce), uint32(u))
+	}
+	return appendUint64(append(b, 0xcf), u)
+}
+
+func appendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = appendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = appendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// The AppendUint methods of binary.BigEndian need Go 1.19.
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
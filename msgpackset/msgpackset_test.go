@@ -0,0 +1,182 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package msgpackset
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+func roundTrip[T comparable](t *testing.T, s mapset.Set[T]) {
+	t.Helper()
+
+	b, err := Marshal(s)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	decoded := mapset.NewThreadUnsafeSet[T]()
+	if err := Unmarshal(b, decoded); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !decoded.Equal(mapset.NewThreadUnsafeSet(s.ToSlice()...)) {
+		t.Errorf("expected %v, got %v", s, decoded)
+	}
+}
+
+func Test_RoundTrip(t *testing.T) {
+	roundTrip(t, mapset.NewSet(0, 1, -1, -32, -33, 127, 128, 255, 256, -129, 65536, -40000, math.MaxInt64, math.MinInt64))
+	roundTrip(t, mapset.NewSet[uint64](0, 200, math.MaxUint16+1, math.MaxUint32+1))
+	roundTrip(t, mapset.NewSet[int8](math.MinInt8, math.MaxInt8))
+	roundTrip(t, mapset.NewSet(0.5, math.Inf(-1), -2))
+	roundTrip(t, mapset.NewSet[float32](1.5, -3))
+	roundTrip(t, mapset.NewSet(true, false))
+	roundTrip(t, mapset.NewSet("", "a", strings.Repeat("b", 31), strings.Repeat("c", 32), strings.Repeat("d", 300), strings.Repeat("e", 70000)))
+
+	large := mapset.NewSet[int]()
+	for i := 0; i < 70000; i++ {
+		large.Add(i)
+	}
+	roundTrip(t, large)
+}
+
+func Test_Encoding(t *testing.T) {
+	b, err := Marshal(mapset.NewSet(-1))
+	if err != nil || !bytes.Equal(b, []byte{0x91, 0xff}) {
+		t.Errorf("unexpected encoding %x, error: %v", b, err)
+	}
+	b, err = Marshal(mapset.NewSet("hi"))
+	if err != nil || !bytes.Equal(b, []byte{0x91, 0xa2, 'h', 'i'}) {
+		t.Errorf("unexpected encoding %x, error: %v", b, err)
+	}
+	if b, _ := Marshal(mapset.NewSet[int]()); !bytes.Equal(b, []byte{0x90}) {
+		t.Errorf("unexpected encoding of the empty set %x", b)
+	}
+}
+
+func Test_UnmarshalConversions(t *testing.T) {
+	// an int8 and an uint16 into floats, binary data into strings
+	floats := mapset.NewSet[float64]()
+	if err := Unmarshal([]byte{0x92, 0xd0, 0x80, 0xcd, 0x01, 0x00}, floats); err != nil || !floats.Equal(mapset.NewSet[float64](-128, 256)) {
+		t.Errorf("unexpected set %v, error: %v", floats, err)
+	}
+	strs := mapset.NewSet[string]()
+	if err := Unmarshal([]byte{0x91, 0xc4, 0x02, 'o', 'k'}, strs); err != nil || !strs.Equal(mapset.NewSet("ok")) {
+		t.Errorf("unexpected set %v, error: %v", strs, err)
+	}
+}
+
+func Test_UnmarshalInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":          nil,
+		"not an array":   {0xa1, 'a'},
+		"truncated":      {0x92, 0x01},
+		"huge array":     {0xdd, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"huge string":    {0x91, 0xdb, 0xff, 0xff, 0xff, 0xff},
+		"trailing bytes": {0x91, 0x01, 0x02},
+		"overflow":       {0x91, 0xcd, 0x01, 0x00},
+		"negative":       {0x91, 0xff},
+		"wrong type":     {0x91, 0xc3},
+		"nil element":    {0x91, 0xc0},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			target := mapset.NewSet[int8]()
+			if name == "negative" {
+				u := mapset.NewSet[uint]()
+				if err := Unmarshal(data, u); !errors.Is(err, ErrInvalidData) {
+					t.Errorf("expected ErrInvalidData, got %v", err)
+				}
+				return
+			}
+			if err := Unmarshal(data, target); !errors.Is(err, ErrInvalidData) {
+				t.Errorf("expected ErrInvalidData, got %v", err)
+			}
+			if !target.IsEmpty() {
+				t.Errorf("nothing should be added on error, got %v", target)
+			}
+		})
+	}
+}
+
+func Test_UnsupportedType(t *testing.T) {
+	type point struct{ X, Y int }
+	if _, err := Marshal(mapset.NewSet(point{})); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+	if err := Unmarshal([]byte{0x90}, mapset.NewSet[point]()); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+}
+
+func Test_Set(t *testing.T) {
+	b, err := Wrap(mapset.NewSet("a", "b")).MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	var s Set[string]
+	if err := s.UnmarshalMsgpack(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(mapset.NewSet("a", "b")) {
+		t.Errorf("unexpected set %v", s)
+	}
+
+	if b, _ := (Set[string]{}).MarshalMsgpack(); !bytes.Equal(b, []byte{0xc0}) {
+		t.Errorf("expected nil for an empty wrapper, got %x", b)
+	}
+	var empty Set[string]
+	if err := empty.UnmarshalMsgpack([]byte{0xc0}); err != nil || empty.Set != nil {
+		t.Errorf("nil should leave the wrapper empty, got %v, error: %v", empty.Set, err)
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, s := range []mapset.Set[string]{mapset.NewSet[string](), mapset.NewSet("a", strings.Repeat("b", 40))} {
+		b, _ := Marshal(s)
+		f.Add(b)
+	}
+	ints, _ := Marshal(mapset.NewSet(1, -1, 1000, -1000, math.MaxInt64))
+	f.Add(ints)
+	f.Add([]byte{0xdd, 0xff, 0xff, 0xff, 0xff})
+	f.Add([]byte{0x91, 0xdb, 0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		strs := mapset.NewSet[string]()
+		if err := Unmarshal(data, strs); err == nil {
+			roundTrip(t, strs)
+		}
+		nums := mapset.NewSet[int64]()
+		if err := Unmarshal(data, nums); err == nil {
+			roundTrip(t, nums)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Shift returns a new set with delta added to every element of s, for
+// instance to move a set of time slots or indexes by a fixed offset.
+// delta is converted to T, and elements wrap around on overflow following
+// Go's integer arithmetic, so that a negative delta also shifts the
+// elements of an unsigned set down. The returned set uses the same
+// implementation as s.
+func Shift[T Integer](s Set[T], delta int) Set[T] {
+	d := T(delta)
+	shifted := newSetLike(s, s.Cardinality())
+	s.Each(func(elem T) bool {
+		shifted.Add(elem + d)
+		return false
+	})
+	return shifted
+}
+
+// Scale returns a new set with every element of s multiplied by k, which
+// is converted to T. Since distinct elements may collide (k == 0, or on
+// overflow), the result can be smaller than s. The returned set uses the
+// same implementation as s.
+func Scale[T Integer](s Set[T], k int) Set[T] {
+	f := T(k)
+	scaled := newSetLike(s, s.Cardinality())
+	s.Each(func(elem T) bool {
+		scaled.Add(elem * f)
+		return false
+	})
+	return scaled
+}
+
+// Sum returns the sum of the elements of s, or zero for an empty set.
+// Integers wrap around on overflow following Go's integer arithmetic. The
+// set is scanned with Each, so thread-safe sets are read under a single lock
+// and no slice of the elements is allocated.
+func Sum[T Number](s Set[T]) T {
+	var sum T
+	s.Each(func(elem T) bool {
+		sum += elem
+		return false
+	})
+	return sum
+}
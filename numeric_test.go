@@ -0,0 +1,110 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_Shift(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(0, 1, 5)
+
+		if actual := Shift(s, 10); !actual.Equal(ctor(10, 11, 15)) {
+			t.Errorf("expected {10, 11, 15}, got %v", actual)
+		}
+		if actual := Shift(s, -1); !actual.Equal(ctor(-1, 0, 4)) {
+			t.Errorf("expected {-1, 0, 4}, got %v", actual)
+		}
+		if !s.Equal(ctor(0, 1, 5)) {
+			t.Error("Shift should not modify the original set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_ShiftWrapsAround(t *testing.T) {
+	if actual := Shift(NewSet[uint8](250, 255), 10); !actual.Equal(NewSet[uint8](4, 9)) {
+		t.Errorf("expected elements to wrap around, got %v", actual)
+	}
+	if actual := Shift(NewSet[uint8](3, 10), -5); !actual.Equal(NewSet[uint8](254, 5)) {
+		t.Errorf("expected a negative delta to shift unsigned elements down, got %v", actual)
+	}
+}
+
+func Test_Scale(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3)
+
+		if actual := Scale(s, 3); !actual.Equal(ctor(3, 6, 9)) {
+			t.Errorf("expected {3, 6, 9}, got %v", actual)
+		}
+		if actual := Scale(s, -1); !actual.Equal(ctor(-1, -2, -3)) {
+			t.Errorf("expected {-1, -2, -3}, got %v", actual)
+		}
+		if actual := Scale(s, 0); !actual.Equal(ctor(0)) {
+			t.Errorf("expected {0}, got %v", actual)
+		}
+		if actual := Scale(ctor(), 2); !actual.IsEmpty() {
+			t.Errorf("expected the empty set, got %v", actual)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_Sum(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		if sum := Sum(ctor()); sum != 0 {
+			t.Errorf("expected the sum of an empty set to be 0, got %d", sum)
+		}
+		if sum := Sum(ctor(1, 2, 3, -10)); sum != -4 {
+			t.Errorf("Expected -4, got: %d", sum)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+
+	if sum := Sum(NewSet(0.5, 0.25)); sum != 0.75 {
+		t.Errorf("Expected 0.75, got: %v", sum)
+	}
+}
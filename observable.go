@@ -0,0 +1,120 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"time"
+)
+
+// ObservableSet is a set that publishes its mutations to subscribers, so
+// that derived state such as caches or the sets maintained by
+// IncrementalUnion can follow its changes instead of recomputing them.
+// Only operations that actually changed the set are published; a bulk
+// operation such as Append publishes a single Mutation, and Clear publishes
+// an OpClear mutation with the elements it removed. Subscribers are
+// called synchronously after each mutation, outside of the lock of the
+// set, so they may call back into the set.
+type ObservableSet[T comparable] struct {
+	Set[T]
+
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]func(Mutation[T])
+}
+
+// NewObservableSet returns a set that forwards all operations to s and
+// publishes its mutations to the functions registered with Subscribe.
+//
+// Mutations must go through the returned set to be published; s itself
+// should no longer be used directly.
+func NewObservableSet[T comparable](s Set[T]) *ObservableSet[T] {
+	o := &ObservableSet[T]{subs: make(map[int]func(Mutation[T]))}
+	observed := newObservedSet(s, o.publish)
+	observed.clearOp = OpClear
+	o.Set = observed
+	return o
+}
+
+func (o *ObservableSet[T]) unwrap() Set[T] {
+	return o.Set
+}
+
+// Subscribe registers fn to be called with every mutation of the set, and
+// returns a function that unregisters it. fn must be safe for concurrent
+// use if the set is mutated concurrently.
+func (o *ObservableSet[T]) Subscribe(fn func(Mutation[T])) (cancel func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id := o.nextID
+	o.nextID++
+	o.subs[id] = fn
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		delete(o.subs, id)
+	}
+}
+
+// OnAdd registers fn to be called with every element added to the set,
+// after the mutation that added it, and returns a function that
+// unregisters it. See Subscribe.
+func (o *ObservableSet[T]) OnAdd(fn func(T)) (cancel func()) {
+	return o.on(OpAdd, fn)
+}
+
+// OnRemove registers fn to be called with every element removed from the
+// set, including by Clear, after the mutation that removed it, and returns
+// a function that unregisters it. See Subscribe.
+func (o *ObservableSet[T]) OnRemove(fn func(T)) (cancel func()) {
+	return o.on(OpRemove, fn)
+}
+
+func (o *ObservableSet[T]) on(op Op, fn func(T)) (cancel func()) {
+	return o.Subscribe(func(m Mutation[T]) {
+		if m.Op != op && !(op == OpRemove && m.Op == OpClear) {
+			return
+		}
+		for _, v := range m.Elems {
+			fn(v)
+		}
+	})
+}
+
+func (o *ObservableSet[T]) publish(op Op, vs []T) {
+	o.mu.RLock()
+	subs := make([]func(Mutation[T]), 0, len(o.subs))
+	for _, fn := range o.subs {
+		subs = append(subs, fn)
+	}
+	o.mu.RUnlock()
+
+	m := Mutation[T]{Op: op, Elems: vs, Time: time.Now()}
+	for _, fn := range subs {
+		fn(m)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ObservableSet(t *testing.T) {
+	s := NewObservableSet(NewSet(1))
+
+	var got []Mutation[int]
+	cancel := s.Subscribe(func(m Mutation[int]) {
+		got = append(got, m)
+	})
+	s.Add(1) // no change
+	s.Append(2, 3, 2)
+	s.Remove(1)
+	s.Remove(9) // no change
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 mutations, got %v", got)
+	}
+	if got[0].Op != OpAdd || !reflect.DeepEqual(got[0].Elems, []int{2, 3}) {
+		t.Errorf("expected the addition of 2 and 3, got %v %v", got[0].Op, got[0].Elems)
+	}
+	if got[1].Op != OpRemove || !reflect.DeepEqual(got[1].Elems, []int{1}) {
+		t.Errorf("expected the removal of 1, got %v %v", got[1].Op, got[1].Elems)
+	}
+
+	s.Clear()
+	if len(got) != 3 || got[2].Op != OpClear || !NewSet(got[2].Elems...).Equal(NewSet(2, 3)) {
+		t.Errorf("expected the clear of 2 and 3, got %v", got[2:])
+	}
+
+	cancel()
+	s.Add(4)
+	if len(got) != 3 {
+		t.Errorf("expected no mutation after cancel, got %v", got[3:])
+	}
+}
+
+func Test_ObservableSetHooks(t *testing.T) {
+	s := NewObservableSet(NewThreadUnsafeSet(1))
+
+	added, removed := NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]()
+	cancelAdd := s.OnAdd(func(v int) { added.Add(v) })
+	s.OnRemove(func(v int) { removed.Add(v) })
+
+	s.Append(1, 2, 3)
+	s.Remove(1)
+	s.Remove(9)
+	if !added.Equal(NewThreadUnsafeSet(2, 3)) || !removed.Equal(NewThreadUnsafeSet(1)) {
+		t.Errorf("expected 2 and 3 added and 1 removed, got %v and %v", added, removed)
+	}
+
+	cancelAdd()
+	s.Add(4)
+	s.Clear()
+	if added.Contains(4) || !removed.Equal(NewThreadUnsafeSet(1, 2, 3, 4)) {
+		t.Errorf("expected only removals after cancel, got %v and %v", added, removed)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Op identifies the kind of mutation applied to an element of a set.
+type Op int
+
+const (
+	// OpAdd means the element was added to the set.
+	OpAdd Op = iota + 1
+	// OpRemove means the element was removed from the set.
+	OpRemove
+	// OpClear means the element was removed by clearing the set. Only
+	// ObservableSet publishes it, other decorators report clearing the set
+	// as OpRemove.
+	OpClear
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpAdd:
+		return "add"
+	case OpRemove:
+		return "remove"
+	case OpClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// observedSet decorates a set and calls notify with the elements that were
+// actually added or removed by every mutation. notify is called after the
+// mutation, outside of any lock held by the decorated set, so it may safely
+// call back into the set. All other operations are forwarded to the
+// decorated set.
+//
+// Bulk operations such as Append are applied one element at a time to find
+// out which elements changed.
+type observedSet[T comparable] struct {
+	Set[T]
+	notify func(op Op, vs []T)
+	// clearOp is the Op notified by Clear, OpRemove by default.
+	clearOp Op
+}
+
+func newObservedSet[T comparable](s Set[T], notify func(op Op, vs []T)) *observedSet[T] {
+	return &observedSet[T]{Set: s, notify: notify, clearOp: OpRemove}
+}
+
+func (o *observedSet[T]) unwrap() Set[T] {
+	return o.Set
+}
+
+func (o *observedSet[T]) Add(v T) bool {
+	if !o.Set.Add(v) {
+		return false
+	}
+	o.notify(OpAdd, []T{v})
+	return true
+}
+
+func (o *observedSet[T]) Append(vs ...T) int {
+	var added []T
+	for _, v := range vs {
+		if o.Set.Add(v) {
+			added = append(added, v)
+		}
+	}
+	if len(added) > 0 {
+		o.notify(OpAdd, added)
+	}
+	return len(added)
+}
+
+func (o *observedSet[T]) AppendFrom(other Set[T]) int {
+	return o.Append(other.ToSlice()...)
+}
+
+func (o *observedSet[T]) Clear() {
+	// PopN removes everything under a single lock and reports what it removed
+	items, count := o.Set.PopN(math.MaxInt)
+	if count > 0 {
+		o.notify(o.clearOp, items)
+	}
+}
+
+func (o *observedSet[T]) Remove(v T) {
+	o.RemovedWhich(v)
+}
+
+func (o *observedSet[T]) RemoveAll(vs ...T) {
+	o.RemovedWhich(vs...)
+}
+
+func (o *observedSet[T]) RemovedWhich(vs ...T) []T {
+	removed := RemovedWhich(o.Set, vs...)
+	if len(removed) > 0 {
+		o.notify(OpRemove, removed)
+	}
+	return removed
+}
+
+func (o *observedSet[T]) removeIf(pred func(T) bool) []T {
+	removed := removeIf(o.Set, pred)
+	if len(removed) > 0 {
+		o.notify(OpRemove, removed)
+	}
+	return removed
+}
+
+func (o *observedSet[T]) update(fn func(MutableView[T])) {
+	var view *changesView[T]
+	Update(o.Set, func(tx MutableView[T]) {
+		view = &changesView[T]{MutableView: tx, changes: make(map[T]Op)}
+		fn(view)
+	})
+
+	added, removed := view.split()
+	if len(removed) > 0 {
+		o.notify(OpRemove, removed)
+	}
+	if len(added) > 0 {
+		o.notify(OpAdd, added)
+	}
+}
+
+func (o *observedSet[T]) Pop() (T, bool) {
+	v, ok := o.Set.Pop()
+	if ok {
+		o.notify(OpRemove, []T{v})
+	}
+	return v, ok
+}
+
+func (o *observedSet[T]) PopN(n int) ([]T, int) {
+	items, count := o.Set.PopN(n)
+	if count > 0 {
+		o.notify(OpRemove, items)
+	}
+	return items, count
+}
+
+func (o *observedSet[T]) UnmarshalJSON(b []byte) error {
+	return setCodec[T]{o}.UnmarshalJSON(b)
+}
+
+func (o *observedSet[T]) MarshalBinary() ([]byte, error) {
+	return setCodec[T]{o}.MarshalBinary()
+}
+
+func (o *observedSet[T]) UnmarshalBinary(data []byte) error {
+	return setCodec[T]{o}.UnmarshalBinary(data)
+}
+
+func (o *observedSet[T]) MarshalText() ([]byte, error) {
+	return setCodec[T]{o}.MarshalText()
+}
+
+func (o *observedSet[T]) UnmarshalText(text []byte) error {
+	return setCodec[T]{o}.UnmarshalText(text)
+}
+
+func (o *observedSet[T]) MarshalYAML() (interface{}, error) {
+	return setCodec[T]{o}.MarshalYAML()
+}
+
+func (o *observedSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return setCodec[T]{o}.UnmarshalYAML(unmarshal)
+}
+
+func (o *observedSet[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return setCodec[T]{o}.UnmarshalBSONValue(bt, b)
+}
+
+func (o *observedSet[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return setCodec[T]{o}.ReconcileTo(target, add, remove)
+}
@@ -0,0 +1,86 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// Of creates and returns a new set with the given elements, it is a
+// shorthand for NewSet. Operations on the resulting set are thread-safe.
+func Of[T comparable](vs ...T) Set[T] {
+	return NewSet(vs...)
+}
+
+// OfThreadUnsafe creates and returns a new set with the given elements, it
+// is a shorthand for NewThreadUnsafeSet. Operations on the resulting set
+// are not thread-safe.
+func OfThreadUnsafe[T comparable](vs ...T) Set[T] {
+	return NewThreadUnsafeSet(vs...)
+}
+
+// Frozen creates and returns an immutable set with the given elements. It
+// is meant for package-level variables, such as lists of reserved words,
+// that are built once and then only queried:
+//
+//	var reserved = mapset.Frozen("admin", "root", "system")
+//
+// Since a frozen set never changes, it is safe for concurrent use without
+// any locking and no caller can modify it through the returned SetView.
+func Frozen[T comparable](vs ...T) SetView[T] {
+	s := newThreadUnsafeSetWithSize[T](len(vs))
+	s.append(vs...)
+	return &frozenSet[T]{uss: s}
+}
+
+// frozenSet exposes the read-only methods of a set that is never modified.
+type frozenSet[T comparable] struct {
+	uss *threadUnsafeSet[T]
+}
+
+func (f *frozenSet[T]) Cardinality() int {
+	return f.uss.Cardinality()
+}
+
+func (f *frozenSet[T]) Contains(val ...T) bool {
+	return f.uss.Contains(val...)
+}
+
+func (f *frozenSet[T]) ContainsOne(val T) bool {
+	return f.uss.ContainsOne(val)
+}
+
+func (f *frozenSet[T]) Each(cb func(T) bool) {
+	f.uss.Each(cb)
+}
+
+func (f *frozenSet[T]) IsEmpty() bool {
+	return f.uss.IsEmpty()
+}
+
+func (f *frozenSet[T]) String() string {
+	return f.uss.String()
+}
+
+func (f *frozenSet[T]) ToSlice() []T {
+	return f.uss.ToSlice()
+}
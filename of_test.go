@@ -0,0 +1,79 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func Test_Of(t *testing.T) {
+	if s := Of(1, 2, 2, 3); !s.Equal(NewSet(1, 2, 3)) {
+		t.Errorf("expected Of to create {1, 2, 3}, got %v", s)
+	}
+	if s := OfThreadUnsafe("a", "b"); !s.Equal(NewThreadUnsafeSet("a", "b")) || CapabilitiesOf(s).Has(CapThreadSafe) {
+		t.Errorf("expected OfThreadUnsafe to create a thread-unsafe {a, b}, got %v", s)
+	}
+}
+
+func Test_Frozen(t *testing.T) {
+	f := Frozen("a", "b", "b")
+	if _, ok := f.(Set[string]); ok {
+		t.Error("a frozen set must not be mutable")
+	}
+	if f.Cardinality() != 2 || !f.Contains("a", "b") || f.ContainsOne("c") || f.IsEmpty() {
+		t.Errorf("unexpected frozen set contents: %v", f)
+	}
+	if !NewSet(f.ToSlice()...).Equal(NewSet("a", "b")) {
+		t.Errorf("expected ToSlice to return a and b, got %v", f.ToSlice())
+	}
+
+	// frozen sets are read concurrently without locking
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				f.ContainsOne("a")
+				f.Each(func(string) bool { return false })
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+var reservedNames = Frozen("admin", "root", "system")
+
+func ExampleFrozen() {
+	for _, name := range []string{"alice", "root"} {
+		fmt.Println(name, reservedNames.ContainsOne(name))
+	}
+	// Output:
+	// alice false
+	// root true
+}
@@ -0,0 +1,482 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// ErrZeroValue is reported when the zero value of the element type is
+// added to a set created with the RejectZero option.
+var ErrZeroValue = errors.New("mapset: zero value rejected")
+
+// ErrNilElement is reported when a nil element is added to a set created
+// with the RejectNil option.
+var ErrNilElement = errors.New("mapset: nil element rejected")
+
+// ErrSetFull is reported when an element is added to a set that reached
+// the maximum cardinality configured with WithMaxCardinality, and the
+// FullPolicy didn't make room for it.
+var ErrSetFull = errors.New("mapset: set is full")
+
+// Option configures a set created with NewSetWithOptions or
+// NewThreadUnsafeSetWithOptions.
+type Option[T comparable] func(*options[T])
+
+type options[T comparable] struct {
+	// validators are run against every element before it is added,
+	// an element is only added when all of them return nil.
+	validators []func(v T) error
+
+	// maxCardinality bounds the number of elements if positive, onFull
+	// decides what happens to elements added beyond it.
+	maxCardinality int
+	onFull         FullPolicy[T]
+
+	// stringLimit bounds the number of elements printed by String if
+	// positive, redact formats them instead of fmt's %v if not nil.
+	stringLimit int
+	redact      func(v T) string
+
+	// lockPolicy selects the lock of thread-safe sets.
+	lockPolicy LockPolicy
+
+	// iterChunk makes the iterations of thread-safe sets chunked if
+	// positive.
+	iterChunk int
+}
+
+// RejectZero makes the set refuse the zero value of T (the empty string,
+// a nil pointer, 0, ...), so that values coming from missed map lookups
+// or unset fields don't silently pollute the set. Add reports false for
+// rejected elements and they are not counted by Append.
+func RejectZero[T comparable]() Option[T] {
+	return func(o *options[T]) {
+		o.validators = append(o.validators, func(v T) error {
+			var zero T
+			if v == zero {
+				return ErrZeroValue
+			}
+			return nil
+		})
+	}
+}
+
+// RejectNil makes the set refuse nil elements. Unlike RejectZero it also
+// catches nil pointers stored in interface element types, for instance a
+// typed nil *MyError added to a Set[error]. Guarding at insertion is much
+// cheaper than defensive nil checks in every Each callback that
+// dereferences the elements.
+func RejectNil[T comparable]() Option[T] {
+	return func(o *options[T]) {
+		o.validators = append(o.validators, func(v T) error {
+			if isNil(v) {
+				return ErrNilElement
+			}
+			return nil
+		})
+	}
+}
+
+// isNil reports whether v is nil, or is an interface holding a nil pointer,
+// channel or other nillable value.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// FullPolicy decides what happens when an element is added to a set that
+// reached the maximum cardinality configured with WithMaxCardinality.
+//
+// It is called with the underlying set, without its options, and the new
+// element. Returning nil lets the element in, provided the policy made room
+// for it, for instance by removing another element; returning an error
+// rejects the element. The policy must not add elements to the set.
+type FullPolicy[T comparable] func(s Set[T], v T) error
+
+// RejectWhenFull returns a FullPolicy that rejects new elements with
+// ErrSetFull once the set is full.
+func RejectWhenFull[T comparable]() FullPolicy[T] {
+	return func(Set[T], T) error {
+		return ErrSetFull
+	}
+}
+
+// EvictArbitrary returns a FullPolicy that makes room for new elements by
+// removing an arbitrary element, as Pop does.
+func EvictArbitrary[T comparable]() FullPolicy[T] {
+	return func(s Set[T], _ T) error {
+		s.Pop()
+		return nil
+	}
+}
+
+// WithMaxCardinality bounds the set to at most n elements, so that
+// unbounded growth of, for instance, a deduplication set can't exhaust the
+// memory of the process silently. When an element is added to a full set,
+// onFull decides whether to reject it, evict another element, or call back
+// into the application. A nil onFull behaves as RejectWhenFull. Adding an
+// element that is already present never triggers onFull.
+//
+// Add reports false for rejected elements and they are not counted by
+// Append. Mutations of sets with a maximum cardinality are serialized.
+func WithMaxCardinality[T comparable](n int, onFull FullPolicy[T]) Option[T] {
+	if onFull == nil {
+		onFull = RejectWhenFull[T]()
+	}
+	return func(o *options[T]) {
+		o.maxCardinality = n
+		o.onFull = onFull
+	}
+}
+
+// WithStringLimit makes String print at most n elements, followed by the
+// number of elements left out, so that printing a large set doesn't flood
+// the logs. Which elements are printed is unspecified.
+func WithStringLimit[T comparable](n int) Option[T] {
+	return func(o *options[T]) {
+		o.stringLimit = n
+	}
+}
+
+// WithRedaction makes String format every element with redact instead of
+// printing it verbatim, so that sets of emails, tokens or other sensitive
+// values can be logged safely, for instance:
+//
+//	tokens := mapset.NewSetWithOptions(mapset.WithRedaction(func(string) string {
+//		return "***"
+//	}))
+//
+// Only String is affected, the encodings such as MarshalJSON still contain
+// the actual elements.
+func WithRedaction[T comparable](redact func(v T) string) Option[T] {
+	return func(o *options[T]) {
+		o.redact = redact
+	}
+}
+
+// NewSetWithOptions creates and returns a new empty set configured by the
+// given options. Operations on the resulting set are thread-safe.
+//
+// Sets returned by operations such as Union or Clone are plain sets and
+// don't inherit the options.
+func NewSetWithOptions[T comparable](opts ...Option[T]) Set[T] {
+	return newSetWithOptions[T](newThreadSafeSet[T](), opts)
+}
+
+// NewThreadUnsafeSetWithOptions creates and returns a new empty set
+// configured by the given options. Operations on the resulting set are
+// not thread-safe.
+//
+// Sets returned by operations such as Union or Clone are plain sets and
+// don't inherit the options.
+func NewThreadUnsafeSetWithOptions[T comparable](opts ...Option[T]) Set[T] {
+	return newSetWithOptions[T](newThreadUnsafeSet[T](), opts)
+}
+
+func newSetWithOptions[T comparable](s Set[T], opts []Option[T]) Set[T] {
+	o := &options[T]{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if ts, ok := s.(*threadSafeSet[T]); ok {
+		ts.locker = newLocker(o.lockPolicy)
+		ts.iterChunk = o.iterChunk
+	}
+
+	if len(o.validators) == 0 && o.maxCardinality <= 0 && o.stringLimit <= 0 && o.redact == nil {
+		return s
+	}
+	return &guardedSet[T]{Set: s, opts: o}
+}
+
+// guardedSet decorates a set and validates every element before it is
+// added, enforcing its maximum cardinality if any, and applies the string
+// policies. All other operations are forwarded to the decorated set.
+type guardedSet[T comparable] struct {
+	Set[T]
+	opts *options[T]
+
+	// mu serializes mutations when the cardinality is bounded, so that
+	// checking for room and adding happen atomically.
+	mu sync.Mutex
+}
+
+func (g *guardedSet[T]) unwrap() Set[T] {
+	return g.Set
+}
+
+// check returns the first validation error for v, if any.
+func (g *guardedSet[T]) check(v T) error {
+	for _, validate := range g.opts.validators {
+		if err := validate(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accepted returns the elements of vs that pass validation. It avoids
+// any allocation when all of them are valid.
+func (g *guardedSet[T]) accepted(vs []T) []T {
+	for i := range vs {
+		if g.check(vs[i]) == nil {
+			continue
+		}
+
+		valid := make([]T, i, len(vs))
+		copy(valid, vs[:i])
+		for _, v := range vs[i+1:] {
+			if g.check(v) == nil {
+				valid = append(valid, v)
+			}
+		}
+		return valid
+	}
+	return vs
+}
+
+// makeRoom returns nil if v can be added without exceeding the maximum
+// cardinality, applying the FullPolicy if needed. The caller must hold mu.
+func (g *guardedSet[T]) makeRoom(v T) error {
+	if g.Set.Cardinality() < g.opts.maxCardinality || g.Set.ContainsOne(v) {
+		return nil
+	}
+	if err := g.opts.onFull(g.Set, v); err != nil {
+		return err
+	}
+	if g.Set.Cardinality() >= g.opts.maxCardinality {
+		return ErrSetFull
+	}
+	return nil
+}
+
+// add validates v and adds it, returning why it was rejected if it was.
+func (g *guardedSet[T]) add(v T) (bool, error) {
+	if err := g.check(v); err != nil {
+		return false, err
+	}
+	if g.opts.maxCardinality <= 0 {
+		return g.Set.Add(v), nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.makeRoom(v); err != nil {
+		return false, err
+	}
+	return g.Set.Add(v), nil
+}
+
+// addThrough validates v and adds it to outer, a decorator of g, returning
+// why it was rejected if it was.
+func (g *guardedSet[T]) addThrough(outer Set[T], v T) (bool, error) {
+	if err := g.check(v); err != nil {
+		return false, err
+	}
+	if outer.Add(v) {
+		return true, nil
+	}
+	if g.opts.maxCardinality > 0 && !outer.ContainsOne(v) {
+		return false, ErrSetFull
+	}
+	return false, nil
+}
+
+func (g *guardedSet[T]) update(fn func(MutableView[T])) {
+	if g.opts.maxCardinality > 0 {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+	}
+	Update(g.Set, func(tx MutableView[T]) {
+		fn(guardedView[T]{MutableView: tx, g: g})
+	})
+}
+
+// guardedView validates the elements added through a view. The FullPolicy
+// can't run while the view is in use, so a full set rejects new elements.
+type guardedView[T comparable] struct {
+	MutableView[T]
+	g *guardedSet[T]
+}
+
+func (v guardedView[T]) Add(elem T) bool {
+	if v.g.check(elem) != nil {
+		return false
+	}
+	if n := v.g.opts.maxCardinality; n > 0 && v.Cardinality() >= n && !v.Contains(elem) {
+		return false
+	}
+	return v.MutableView.Add(elem)
+}
+
+func (g *guardedSet[T]) Capabilities() Capabilities {
+	caps := CapabilitiesOf(g.Set)
+	if g.opts.maxCardinality > 0 {
+		caps |= CapBounded
+	}
+	return caps
+}
+
+func (g *guardedSet[T]) Add(v T) bool {
+	added, _ := g.add(v)
+	return added
+}
+
+func (g *guardedSet[T]) Append(vs ...T) int {
+	if g.opts.maxCardinality <= 0 {
+		return g.Set.Append(g.accepted(vs)...)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := 0
+	for _, v := range vs {
+		if g.check(v) == nil && g.makeRoom(v) == nil && g.Set.Add(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// AppendChecked adds the given elements to s like Append, and also reports
+// why elements were rejected by the options of sets created with
+// NewSetWithOptions or NewThreadUnsafeSetWithOptions, so that bulk loads
+// don't drop invalid or over-capacity elements silently. errs holds one
+// error per rejected element, wrapping the reason such as ErrZeroValue or
+// ErrSetFull; elements that were already present are not errors.
+//
+// Sets without options never reject elements. The options are found
+// through decorators such as HistorySet, in which case the elements are
+// added through the decorators and a FullPolicy rejecting an element is
+// reported as ErrSetFull.
+func AppendChecked[T comparable](s Set[T], vals ...T) (added int, errs []error) {
+	g, ok := implementation[*guardedSet[T]](s)
+	if !ok {
+		return s.Append(vals...), nil
+	}
+
+	add := g.add
+	if Set[T](g) != s {
+		add = func(v T) (bool, error) {
+			return g.addThrough(s, v)
+		}
+	}
+	for _, v := range vals {
+		ok, err := add(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mapset: %v: %w", v, err))
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, errs
+}
+
+func (g *guardedSet[T]) AppendFrom(other Set[T]) int {
+	return g.Append(other.ToSlice()...)
+}
+
+func (g *guardedSet[T]) UnmarshalJSON(b []byte) error {
+	return setCodec[T]{g}.UnmarshalJSON(b)
+}
+
+func (g *guardedSet[T]) MarshalBinary() ([]byte, error) {
+	return setCodec[T]{g}.MarshalBinary()
+}
+
+func (g *guardedSet[T]) UnmarshalBinary(data []byte) error {
+	return setCodec[T]{g}.UnmarshalBinary(data)
+}
+
+func (g *guardedSet[T]) MarshalText() ([]byte, error) {
+	return setCodec[T]{g}.MarshalText()
+}
+
+func (g *guardedSet[T]) UnmarshalText(text []byte) error {
+	return setCodec[T]{g}.UnmarshalText(text)
+}
+
+func (g *guardedSet[T]) MarshalYAML() (interface{}, error) {
+	return setCodec[T]{g}.MarshalYAML()
+}
+
+func (g *guardedSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return setCodec[T]{g}.UnmarshalYAML(unmarshal)
+}
+
+func (g *guardedSet[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return setCodec[T]{g}.UnmarshalBSONValue(bt, b)
+}
+
+func (g *guardedSet[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return setCodec[T]{g}.ReconcileTo(target, add, remove)
+}
+
+func (g *guardedSet[T]) String() string {
+	if g.opts.stringLimit <= 0 && g.opts.redact == nil {
+		return g.Set.String()
+	}
+
+	n := g.Set.Cardinality()
+	if g.opts.stringLimit > 0 && n > g.opts.stringLimit {
+		n = g.opts.stringLimit
+	}
+	items := make([]string, 0, n+1)
+	total := 0
+	g.Set.Each(func(v T) bool {
+		total++
+		if g.opts.stringLimit > 0 && len(items) >= g.opts.stringLimit {
+			// keep counting the elements left out
+			return false
+		}
+		if g.opts.redact != nil {
+			items = append(items, g.opts.redact(v))
+		} else {
+			items = append(items, fmt.Sprintf("%v", v))
+		}
+		return false
+	})
+	if omitted := total - len(items); omitted > 0 {
+		items = append(items, fmt.Sprintf("... %d more", omitted))
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
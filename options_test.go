@@ -0,0 +1,322 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_RejectZero(t *testing.T) {
+	test := func(t *testing.T, ctor func(opts ...Option[string]) Set[string]) {
+		s := ctor(RejectZero[string]())
+
+		if s.Add("") {
+			t.Error("Add should reject the zero value")
+		}
+		if !s.Add("a") {
+			t.Error("Add should accept non-zero values")
+		}
+		if n := s.Append("", "b", "", "c"); n != 2 {
+			t.Errorf("Append should only count the accepted elements, got %d", n)
+		}
+		if n := s.AppendFrom(NewSet("", "d")); n != 1 {
+			t.Errorf("AppendFrom should only count the accepted elements, got %d", n)
+		}
+		if err := json.Unmarshal([]byte(`["", "e"]`), s); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+
+		if s.Contains("") {
+			t.Error("the set should not contain the zero value")
+		}
+		if !s.Contains("a", "b", "c", "d", "e") || s.Cardinality() != 5 {
+			t.Errorf("unexpected set contents: %v", s)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSetWithOptions[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSetWithOptions[string])
+	})
+}
+
+func Test_RejectZeroPointer(t *testing.T) {
+	s := NewSetWithOptions(RejectZero[*int]())
+
+	n := 1
+	if s.Add(nil) {
+		t.Error("Add should reject nil pointers")
+	}
+	if !s.Add(&n) {
+		t.Error("Add should accept non-nil pointers")
+	}
+}
+
+type testError struct{}
+
+func (*testError) Error() string { return "test error" }
+
+func Test_RejectNil(t *testing.T) {
+	numbers := []int{1, 2, 3}
+	widgets := NewSetWithOptions(RejectNil[*int]())
+	if n := widgets.Append(&numbers[0], &numbers[1], nil, &numbers[2]); n != 3 {
+		t.Errorf("Append should reject the nil element, got %d added", n)
+	}
+
+	// The same scenario as Test_DeadlockOnEachCallbackWhenPanic no longer panics.
+	widgets.Each(func(n *int) bool {
+		_ = *n * 2
+		return false
+	})
+
+	var typedNil *testError
+	var err error = typedNil
+	if !isNil(err) {
+		t.Error("a nil pointer stored in an interface should be considered nil")
+	}
+	if isNil(error(&testError{})) {
+		t.Error("a non-nil pointer stored in an interface should not be considered nil")
+	}
+
+	ints := NewSetWithOptions(RejectNil[int]())
+	if !ints.Add(0) {
+		t.Error("RejectNil should not reject zero values of non-nillable types")
+	}
+}
+
+func Test_NewSetWithOptionsNoOptions(t *testing.T) {
+	if _, ok := NewSetWithOptions[int]().(*threadSafeSet[int]); !ok {
+		t.Error("without options a plain thread-safe set should be returned")
+	}
+	if _, ok := NewThreadUnsafeSetWithOptions[int]().(*threadUnsafeSet[int]); !ok {
+		t.Error("without options a plain thread-unsafe set should be returned")
+	}
+}
+
+func Test_OptionsSetAsOperand(t *testing.T) {
+	test := func(t *testing.T, ctor func(opts ...Option[int]) Set[int], plain func(vals ...int) Set[int]) {
+		guarded := ctor(RejectZero[int]())
+		guarded.Append(1, 2, 3)
+		other := plain(3, 4)
+
+		if !other.Union(guarded).Equal(plain(1, 2, 3, 4)) {
+			t.Error("a plain set should accept a set with options as operand")
+		}
+		if !guarded.Intersect(other).Equal(plain(3)) {
+			t.Error("a set with options should accept a plain set as operand")
+		}
+		if !guarded.Equal(guarded.Clone()) {
+			t.Error("a set with options should be equal to its clone")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSetWithOptions[int], NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSetWithOptions[int], NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_WithMaxCardinalityReject(t *testing.T) {
+	test := func(t *testing.T, ctor func(opts ...Option[int]) Set[int]) {
+		s := ctor(WithMaxCardinality[int](3, nil))
+
+		if n := s.Append(1, 2, 3, 4, 5); n != 3 {
+			t.Errorf("Append should stop at the maximum cardinality, got %d added", n)
+		}
+		if s.Add(6) {
+			t.Error("Add should reject elements once the set is full")
+		}
+		if s.Add(1) {
+			t.Error("adding an existing element should report false")
+		}
+		if s.Cardinality() != 3 {
+			t.Errorf("expected 3 elements, got %d", s.Cardinality())
+		}
+
+		s.Remove(1)
+		if !s.Add(7) || !s.Contains(7) {
+			t.Error("Add should accept elements once room was made")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSetWithOptions[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSetWithOptions[int])
+	})
+}
+
+func Test_WithMaxCardinalityEvict(t *testing.T) {
+	s := NewSetWithOptions(WithMaxCardinality(2, EvictArbitrary[string]()))
+
+	s.Append("a", "b")
+	if !s.Add("c") {
+		t.Error("Add should accept new elements by evicting others")
+	}
+	if s.Cardinality() != 2 || !s.Contains("c") {
+		t.Errorf("expected c and one other element, got %v", s)
+	}
+}
+
+func Test_WithMaxCardinalityCallback(t *testing.T) {
+	var rejected []int
+	s := NewThreadUnsafeSetWithOptions(
+		RejectZero[int](),
+		WithMaxCardinality(1, func(s Set[int], v int) error {
+			rejected = append(rejected, v)
+			return ErrSetFull
+		}),
+	)
+
+	s.Append(0, 1, 2, 3)
+	if len(rejected) != 2 || rejected[0] != 2 || rejected[1] != 3 {
+		t.Errorf("expected 2 and 3 to be reported, got %v", rejected)
+	}
+	if !s.Equal(NewThreadUnsafeSet(1)) {
+		t.Errorf("expected {1}, got %v", s)
+	}
+
+	lazy := NewSetWithOptions(WithMaxCardinality(1, func(Set[int], int) error {
+		return nil
+	}))
+	lazy.Add(1)
+	if lazy.Add(2) {
+		t.Error("an element should be rejected if the policy didn't make room")
+	}
+}
+
+func Test_WithMaxCardinalityConcurrent(t *testing.T) {
+	s := NewSetWithOptions(WithMaxCardinality(N/2, EvictArbitrary[int]()))
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for i := 0; i < N; i++ {
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i)
+			if s.Cardinality() > N/2 {
+				t.Error("the maximum cardinality should never be exceeded")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Cardinality() != N/2 {
+		t.Errorf("expected %d elements, got %d", N/2, s.Cardinality())
+	}
+}
+
+func Test_WithStringLimit(t *testing.T) {
+	s := NewSetWithOptions(WithStringLimit[int](3))
+	if s.String() != "Set{}" {
+		t.Errorf("unexpected string for an empty set: %s", s)
+	}
+
+	s.Append(1, 2, 3)
+	if items := strings.Split(strings.TrimSuffix(strings.TrimPrefix(s.String(), "Set{"), "}"), ", "); len(items) != 3 {
+		t.Errorf("expected all 3 elements to be printed, got %s", s)
+	}
+
+	s.Append(nrand(N)...)
+	str := s.String()
+	if !strings.HasSuffix(str, fmt.Sprintf(", ... %d more}", s.Cardinality()-3)) {
+		t.Errorf("expected the number of omitted elements to be printed, got %s", str)
+	}
+	if items := strings.Split(str, ", "); len(items) != 4 {
+		t.Errorf("expected 3 elements and a summary, got %s", str)
+	}
+}
+
+func Test_WithRedaction(t *testing.T) {
+	test := func(t *testing.T, ctor func(opts ...Option[string]) Set[string]) {
+		redact := func(v string) string {
+			return v[:1] + "***"
+		}
+		s := ctor(WithRedaction(redact), WithStringLimit[string](5))
+		s.Append("alice@example.com", "bob@example.com")
+
+		str := fmt.Sprint(s)
+		if strings.Contains(str, "example.com") {
+			t.Errorf("the elements should be redacted, got %s", str)
+		}
+		if str != "Set{a***, b***}" && str != "Set{b***, a***}" {
+			t.Errorf("unexpected redacted string: %s", str)
+		}
+		if !s.Contains("alice@example.com") {
+			t.Error("redaction should not affect the elements")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSetWithOptions[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSetWithOptions[string])
+	})
+}
+
+func Test_AppendChecked(t *testing.T) {
+	s := NewSetWithOptions(RejectZero[string](), WithMaxCardinality[string](2, nil))
+
+	added, errs := AppendChecked(s, "a", "", "a", "b", "c")
+	if added != 2 {
+		t.Errorf("expected 2 elements to be added, got %d", added)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !errors.Is(errs[0], ErrZeroValue) || !errors.Is(errs[1], ErrSetFull) {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if !strings.Contains(errs[1].Error(), "c") {
+		t.Errorf("the error should name the rejected element, got: %v", errs[1])
+	}
+
+	added, errs = AppendChecked(NewThreadUnsafeSet[string](), "", "a")
+	if added != 2 || errs != nil {
+		t.Errorf("sets without options should accept everything, got %d, %v", added, errs)
+	}
+
+	// the options are found through decorators, which see the additions
+	h := NewHistorySet(NewSetWithOptions(RejectZero[string](), WithMaxCardinality[string](1, nil)), 0)
+	added, errs = AppendChecked[string](h, "", "a", "b")
+	if added != 1 || len(errs) != 2 || !errors.Is(errs[0], ErrZeroValue) || !errors.Is(errs[1], ErrSetFull) {
+		t.Errorf("unexpected result through a decorator: %d, %v", added, errs)
+	}
+	if history := h.History(); len(history) != 1 || history[0].Elems[0] != "a" {
+		t.Errorf("the addition should be recorded, got %v", history)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// poolShards is the number of shards of the cache of a Pool.
+	poolShards = 8
+	// poolShardSets is the number of sets cached by every shard.
+	poolShardSets = 4
+)
+
+// Pool caches empty thread-unsafe sets for reuse as scratch space, so that
+// request hot paths building short-lived sets don't allocate in steady
+// state. On top of a sync.Pool, which is emptied by garbage collections, it
+// keeps a few sets in a small sharded cache that survives them. Sets larger
+// than the pool's maximum size when they are put back are dropped, so that
+// one huge request doesn't pin its memory forever.
+//
+// A Pool must not be copied after first use. Its methods are safe for
+// concurrent use.
+type Pool[T comparable] struct {
+	maxSize int
+	next    uint32 // shard to try first, rotated atomically
+	shards  [poolShards]poolShard[T]
+	pool    sync.Pool
+}
+
+type poolShard[T comparable] struct {
+	sync.Mutex
+	sets []*threadUnsafeSet[T]
+	// keep shards on separate cache lines
+	_ [64]byte
+}
+
+// NewPool returns a pool of sets that keeps sets of up to maxSize elements.
+// A non-positive maxSize keeps sets of up to 65536 elements.
+func NewPool[T comparable](maxSize int) *Pool[T] {
+	if maxSize <= 0 {
+		maxSize = maxScratchSize
+	}
+	return &Pool[T]{maxSize: maxSize}
+}
+
+// Get returns an empty thread-unsafe set from the pool, or a new one if the
+// pool is empty. Once done with it, the caller should return it with Put.
+func (p *Pool[T]) Get() Set[T] {
+	start := atomic.AddUint32(&p.next, 1)
+	for i := uint32(0); i < poolShards; i++ {
+		sh := &p.shards[(start+i)%poolShards]
+		// a busy shard is skipped rather than waited for
+		if !sh.TryLock() {
+			continue
+		}
+		if n := len(sh.sets); n > 0 {
+			s := sh.sets[n-1]
+			sh.sets[n-1] = nil
+			sh.sets = sh.sets[:n-1]
+			sh.Unlock()
+			return s
+		}
+		sh.Unlock()
+	}
+
+	if s, ok := p.pool.Get().(*threadUnsafeSet[T]); ok {
+		return s
+	}
+	return newThreadUnsafeSet[T]()
+}
+
+// Put clears s and returns it to the pool. Only sets obtained from Get are
+// kept; others are ignored. s must not be used after Put.
+func (p *Pool[T]) Put(s Set[T]) {
+	u, ok := s.(*threadUnsafeSet[T])
+	if !ok || len(*u) > p.maxSize {
+		return
+	}
+	u.Clear()
+
+	start := atomic.AddUint32(&p.next, 1)
+	for i := uint32(0); i < poolShards; i++ {
+		sh := &p.shards[(start+i)%poolShards]
+		if !sh.TryLock() {
+			continue
+		}
+		if len(sh.sets) < poolShardSets {
+			sh.sets = append(sh.sets, u)
+			sh.Unlock()
+			return
+		}
+		sh.Unlock()
+	}
+	p.pool.Put(u)
+}
+
+// WithScratch calls fn with an empty set from the pool and returns the set
+// to the pool when fn returns, even if it panics. fn must not retain the
+// set.
+func (p *Pool[T]) WithScratch(fn func(s Set[T])) {
+	s := p.Get()
+	defer p.Put(s)
+	fn(s)
+}
+
+// defaultPools holds a *Pool per element type for WithScratch.
+var defaultPools sync.Map
+
+// WithScratch calls fn with an empty scratch set taken from a pool shared
+// by the whole program for the element type, see Pool.WithScratch.
+func WithScratch[T comparable](fn func(s Set[T])) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	p, ok := defaultPools.Load(typ)
+	if !ok {
+		p, _ = defaultPools.LoadOrStore(typ, NewPool[T](0))
+	}
+	p.(*Pool[T]).WithScratch(fn)
+}
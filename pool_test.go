@@ -0,0 +1,133 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_Pool(t *testing.T) {
+	p := NewPool[int](10)
+
+	s := p.Get()
+	if !s.IsEmpty() {
+		t.Fatalf("expected an empty set, got %v", s)
+	}
+	s.Append(1, 2, 3)
+	p.Put(s)
+
+	reused := p.Get()
+	if reused != s {
+		t.Error("expected the set to be reused")
+	}
+	if !reused.IsEmpty() {
+		t.Errorf("expected a cleared set, got %v", reused)
+	}
+
+	// sets over the maximum size and foreign sets are dropped
+	for i := 0; i < 11; i++ {
+		reused.Add(i)
+	}
+	p.Put(reused)
+	p.Put(NewSet[int]())
+	if got := p.Get(); got == reused {
+		t.Error("a set over the maximum size should not be reused")
+	}
+}
+
+func Test_PoolWithScratch(t *testing.T) {
+	p := NewPool[string](0)
+
+	var scratch Set[string]
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		p.WithScratch(func(s Set[string]) {
+			scratch = s
+			s.Add("a")
+			panic("boom")
+		})
+	}()
+
+	if got := p.Get(); got != scratch || !got.IsEmpty() {
+		t.Errorf("expected the scratch set to be returned cleared, got %v", got)
+	}
+
+	n := 0
+	WithScratch(func(s Set[int]) {
+		s.Append(1, 2)
+		n = s.Cardinality()
+	})
+	WithScratch(func(s Set[int]) {
+		n += s.Cardinality()
+	})
+	if n != 2 {
+		t.Errorf("expected an empty set for every call, got %d elements", n)
+	}
+}
+
+func Test_PoolConcurrent(t *testing.T) {
+	p := NewPool[int](0)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				p.WithScratch(func(s Set[int]) {
+					if !s.IsEmpty() {
+						t.Errorf("expected an empty set, got %v", s)
+					}
+					s.Append(g, i)
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func Test_PoolAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector makes sync.Pool drop items")
+	}
+
+	p := NewPool[int](0)
+	fill := func(s Set[int]) {
+		for i := 0; i < 100; i++ {
+			s.Add(i)
+		}
+	}
+	p.WithScratch(fill)
+
+	if allocs := testing.AllocsPerRun(100, func() { p.WithScratch(fill) }); allocs > 0 {
+		t.Errorf("expected no allocations in steady state, got %v", allocs)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+)
+
+// maxPowerSetCardinality bounds the cardinality of the sets given to
+// PowerSet, whose result grows as 2^n.
+const maxPowerSetCardinality = 30
+
+// PowerSet returns all the subsets of s, from the empty set to a copy of s,
+// 2^n sets for a set of n elements. The subsets use the same implementation
+// as s. It panics if s has more than 30 elements, since the result could
+// not fit in memory anyway.
+func PowerSet[T comparable](s Set[T]) []Set[T] {
+	elems := s.ToSlice()
+	if len(elems) > maxPowerSetCardinality {
+		panic(fmt.Sprintf("mapset: power set of %d elements is too large", len(elems)))
+	}
+
+	subsets := make([]Set[T], 1<<len(elems))
+	for mask := range subsets {
+		subset := newSetLike(s, popCount(mask))
+		for i, elem := range elems {
+			if mask&(1<<i) != 0 {
+				subset.Add(elem)
+			}
+		}
+		subsets[mask] = subset
+	}
+	return subsets
+}
+
+func popCount(mask int) int {
+	n := 0
+	for ; mask != 0; mask &= mask - 1 {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_PowerSet(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		subsets := PowerSet(ctor(1, 2, 3))
+		if len(subsets) != 8 {
+			t.Fatalf("expected 8 subsets, got %d", len(subsets))
+		}
+
+		want := []Set[int]{
+			ctor(), ctor(1), ctor(2), ctor(3),
+			ctor(1, 2), ctor(1, 3), ctor(2, 3), ctor(1, 2, 3),
+		}
+		for _, w := range want {
+			found := 0
+			for _, s := range subsets {
+				if s.Equal(w) {
+					found++
+				}
+			}
+			if found != 1 {
+				t.Errorf("expected the subset %v once, found it %d times", w, found)
+			}
+		}
+		if !subsets[0].IsEmpty() {
+			t.Errorf("expected the first subset to be empty, got %v", subsets[0])
+		}
+
+		if empty := PowerSet(ctor()); len(empty) != 1 || !empty[0].IsEmpty() {
+			t.Errorf("expected the power set of the empty set to hold the empty set, got %v", empty)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_PowerSetTooLarge(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected PowerSet to panic on a set of 31 elements")
+		}
+	}()
+	PowerSet(NewSet(makeRange(31)...))
+}
@@ -0,0 +1,58 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+)
+
+// Pair is an ordered pair of elements, an element of the sets returned by
+// CartesianProduct2. It is comparable, so pairs can be used as set
+// elements and map keys.
+type Pair[A comparable, B comparable] struct {
+	First  A
+	Second B
+}
+
+// String returns the pair as "(first, second)".
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// CartesianProduct2 returns a new set with all the pairs made of an element
+// of a and an element of b, a set of a.Cardinality()*b.Cardinality()
+// elements. Operations on the resulting set are thread-safe.
+func CartesianProduct2[A comparable, B comparable](a Set[A], b Set[B]) Set[Pair[A, B]] {
+	as, bs := a.ToSlice(), b.ToSlice()
+
+	product := newThreadSafeSetWithSize[Pair[A, B]](len(as) * len(bs))
+	for _, x := range as {
+		for _, y := range bs {
+			product.uss.add(Pair[A, B]{First: x, Second: y})
+		}
+	}
+	return product
+}
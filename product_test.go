@@ -0,0 +1,54 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_CartesianProduct2(t *testing.T) {
+	sizes := NewSet("S", "M")
+	counts := NewThreadUnsafeSet(1, 2, 3)
+
+	product := CartesianProduct2(sizes, counts)
+	if product.Cardinality() != 6 {
+		t.Errorf("expected 6 pairs, got %d", product.Cardinality())
+	}
+	for _, size := range sizes.ToSlice() {
+		for _, count := range counts.ToSlice() {
+			if !product.ContainsOne(Pair[string, int]{First: size, Second: count}) {
+				t.Errorf("expected the pair (%s, %d)", size, count)
+			}
+		}
+	}
+
+	if p := CartesianProduct2(sizes, NewSet[int]()); !p.IsEmpty() {
+		t.Errorf("expected the product with an empty set to be empty, got %v", p)
+	}
+	if s := (Pair[string, int]{First: "S", Second: 1}).String(); s != "(S, 1)" {
+		t.Errorf("expected (S, 1), got %q", s)
+	}
+}
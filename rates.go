@@ -0,0 +1,117 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Rates holds exponentially weighted moving averages of the number of
+// elements added to and removed from a set per second.
+type Rates struct {
+	Adds    float64
+	Removes float64
+}
+
+// RatedSet is a set that tracks how fast elements are added and removed,
+// so that operators can alert on anomalous churn in sets that should be
+// mostly static, such as cluster membership. Only the elements that were
+// actually added or removed are counted.
+type RatedSet[T comparable] struct {
+	Set[T]
+
+	mu       sync.Mutex
+	tau      float64 // time constant of the averages, in seconds
+	adds     float64
+	removes  float64
+	lastTick time.Time
+	now      func() time.Time
+}
+
+// NewRatedSet returns a set that forwards all operations to s and tracks
+// the rates of change of its elements. halfLife is the time after which an
+// event weighs half as much in the averages: a short half-life reacts
+// quickly to bursts, a long one smooths them out. A halfLife below one
+// nanosecond is raised to one nanosecond.
+//
+// Mutations must go through the returned set to be counted; s itself
+// should no longer be used directly.
+func NewRatedSet[T comparable](s Set[T], halfLife time.Duration) *RatedSet[T] {
+	if halfLife < time.Nanosecond {
+		halfLife = time.Nanosecond
+	}
+	r := &RatedSet[T]{
+		tau: halfLife.Seconds() / math.Ln2,
+		now: time.Now,
+	}
+	r.lastTick = r.now()
+	r.Set = newObservedSet(s, r.record)
+	return r
+}
+
+func (r *RatedSet[T]) unwrap() Set[T] {
+	return r.Set
+}
+
+// Rates returns the current rates of change of the set.
+func (r *RatedSet[T]) Rates() Rates {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decay()
+	return Rates{Adds: r.adds, Removes: r.removes}
+}
+
+func (r *RatedSet[T]) record(op Op, vs []T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decay()
+	// every event contributes 1/tau, so that a steady stream of n events
+	// per second converges to a rate of n
+	switch op {
+	case OpAdd:
+		r.adds += float64(len(vs)) / r.tau
+	case OpRemove:
+		r.removes += float64(len(vs)) / r.tau
+	}
+}
+
+// decay ages the averages to the current time, it must be called with mu
+// held.
+func (r *RatedSet[T]) decay() {
+	now := r.now()
+	elapsed := now.Sub(r.lastTick).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	factor := math.Exp(-elapsed / r.tau)
+	r.adds *= factor
+	r.removes *= factor
+	r.lastTick = now
+}
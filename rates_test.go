@@ -0,0 +1,104 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_RatedSet(t *testing.T) {
+	now, advance := manualClock(time.Unix(0, 0))
+	r := NewRatedSet[int](NewSet[int](), 10*time.Second)
+	r.now = now
+	r.lastTick = now()
+
+	// a steady stream of 5 adds and 1 remove per second
+	v := 0
+	for i := 0; i < 600; i++ {
+		advance(time.Second)
+		for j := 0; j < 5; j++ {
+			r.Add(v)
+			v++
+		}
+		r.Remove(v - 1)
+	}
+
+	rates := r.Rates()
+	if math.Abs(rates.Adds-5) > 0.5 || math.Abs(rates.Removes-1) > 0.1 {
+		t.Errorf("expected rates near 5 adds and 1 remove per second, got %+v", rates)
+	}
+
+	// the rates halve after every half-life without changes
+	advance(10 * time.Second)
+	halved := r.Rates()
+	if math.Abs(halved.Adds-rates.Adds/2) > 1e-9 || math.Abs(halved.Removes-rates.Removes/2) > 1e-9 {
+		t.Errorf("expected the rates to halve, got %+v from %+v", halved, rates)
+	}
+
+	// no-op mutations are not counted
+	before := r.Rates()
+	r.Add(0)
+	r.Remove(-1)
+	if after := r.Rates(); after != before {
+		t.Errorf("no-op mutations should not change the rates, got %+v from %+v", after, before)
+	}
+
+	if r.Cardinality() != 600*5-600 {
+		t.Errorf("unexpected cardinality %d", r.Cardinality())
+	}
+}
+
+func Test_RatedSetAsOperand(t *testing.T) {
+	r := NewRatedSet[int](NewSet(1, 2), time.Second)
+	r.Append(3)
+	if u := NewSet(4).Union(r); !u.Equal(NewSet(1, 2, 3, 4)) {
+		t.Errorf("unexpected union: %v", u)
+	}
+	if r.Rates().Adds <= 0 {
+		t.Error("the append should be counted")
+	}
+}
+
+func Test_RatedSetNonPositiveHalfLife(t *testing.T) {
+	for _, halfLife := range []time.Duration{0, -time.Second} {
+		now, advance := manualClock(time.Unix(0, 0))
+		r := NewRatedSet[int](NewSet[int](), halfLife)
+		r.now = now
+		r.lastTick = now()
+
+		r.Append(1, 2)
+		r.Remove(1)
+		advance(time.Nanosecond)
+		rates := r.Rates()
+		for _, rate := range []float64{rates.Adds, rates.Removes} {
+			if math.IsNaN(rate) || math.IsInf(rate, 0) || rate < 0 {
+				t.Errorf("expected finite rates with a half-life of %v, got %+v", halfLife, rates)
+			}
+		}
+	}
+}
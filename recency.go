@@ -0,0 +1,133 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"container/list"
+)
+
+// recencyList keeps unique elements in recency order, most recently used
+// first, each with some metadata M. Looking up, touching, adding and
+// removing an element cost O(1). It is the bookkeeping shared by BoundedSet
+// and TouchSet, and isn't safe for concurrent use.
+type recencyList[T comparable, M any] struct {
+	members map[T]*list.Element
+	// order holds *recencyEntry values, most recently used first.
+	order *list.List
+}
+
+type recencyEntry[T comparable, M any] struct {
+	value T
+	meta  M
+}
+
+func newRecencyList[T comparable, M any](size int) *recencyList[T, M] {
+	return &recencyList[T, M]{
+		members: make(map[T]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+func (l *recencyList[T, M]) len() int {
+	return len(l.members)
+}
+
+// get returns the entry of v without changing its recency.
+func (l *recencyList[T, M]) get(v T) (*recencyEntry[T, M], bool) {
+	e, found := l.members[v]
+	if !found {
+		return nil, false
+	}
+	return e.Value.(*recencyEntry[T, M]), true
+}
+
+// touch returns the entry of v and makes it the most recently used.
+func (l *recencyList[T, M]) touch(v T) (*recencyEntry[T, M], bool) {
+	e, found := l.members[v]
+	if !found {
+		return nil, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*recencyEntry[T, M]), true
+}
+
+// push adds v, which must be absent, as the most recently used element.
+func (l *recencyList[T, M]) push(v T, meta M) {
+	l.members[v] = l.order.PushFront(&recencyEntry[T, M]{value: v, meta: meta})
+}
+
+// remove removes v and returns whether it was present.
+func (l *recencyList[T, M]) remove(v T) bool {
+	e, found := l.members[v]
+	if found {
+		l.order.Remove(e)
+		delete(l.members, v)
+	}
+	return found
+}
+
+// oldest returns the entry of the least recently used element.
+func (l *recencyList[T, M]) oldest() (*recencyEntry[T, M], bool) {
+	e := l.order.Back()
+	if e == nil {
+		return nil, false
+	}
+	return e.Value.(*recencyEntry[T, M]), true
+}
+
+func (l *recencyList[T, M]) clear() {
+	l.members = make(map[T]*list.Element)
+	l.order.Init()
+}
+
+// newestFirst calls cb with the entries, most recently used first, until
+// it returns true.
+func (l *recencyList[T, M]) newestFirst(cb func(*recencyEntry[T, M]) bool) {
+	for e := l.order.Front(); e != nil; e = e.Next() {
+		if cb(e.Value.(*recencyEntry[T, M])) {
+			return
+		}
+	}
+}
+
+// oldestFirst calls cb with the entries, least recently used first, until
+// it returns true.
+func (l *recencyList[T, M]) oldestFirst(cb func(*recencyEntry[T, M]) bool) {
+	for e := l.order.Back(); e != nil; e = e.Prev() {
+		if cb(e.Value.(*recencyEntry[T, M])) {
+			return
+		}
+	}
+}
+
+// toSet returns the elements as a new thread-safe Set.
+func (l *recencyList[T, M]) toSet() Set[T] {
+	set := newThreadSafeSetWithSize[T](len(l.members))
+	for v := range l.members {
+		set.uss.add(v)
+	}
+	return set
+}
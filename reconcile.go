@@ -0,0 +1,84 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+)
+
+// reconcile implements ReconcileTo on top of the public Set methods, so that
+// it works with any receiver, decorated or not. Both sets are copied first
+// so that no lock is held while the callbacks run.
+// ReconcileTo converges external state towards target. It calls add for
+// every element of target missing from s and remove for every element of s
+// missing from target, applying the change to s when the callback succeeds.
+// s thus tracks the state that was successfully applied, and calling
+// ReconcileTo again retries what failed.
+//
+// Errors returned by the callbacks don't stop the reconciliation, they are
+// wrapped together in the returned error. A nil callback skips the
+// corresponding changes. No lock is held while the callbacks run.
+func ReconcileTo[T comparable](s, target Set[T], add func(T) error, remove func(T) error) error {
+	type reconciler interface {
+		ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error
+	}
+	if r, ok := implementation[reconciler](s); ok {
+		return r.ReconcileTo(target, add, remove)
+	}
+	return reconcile[T](s, target, add, remove)
+}
+
+func reconcile[T comparable](s, target Set[T], add func(T) error, remove func(T) error) error {
+	current := s.ToSlice()
+	desired := target.ToSlice()
+
+	var errs []error
+	if add != nil {
+		for _, v := range desired {
+			if s.ContainsOne(v) {
+				continue
+			}
+			if err := add(v); err != nil {
+				errs = append(errs, fmt.Errorf("mapset: add %v: %w", v, err))
+				continue
+			}
+			s.Add(v)
+		}
+	}
+	if remove != nil {
+		for _, v := range current {
+			if target.ContainsOne(v) {
+				continue
+			}
+			if err := remove(v); err != nil {
+				errs = append(errs, fmt.Errorf("mapset: remove %v: %w", v, err))
+				continue
+			}
+			s.Remove(v)
+		}
+	}
+	return joinErrors(errs...)
+}
@@ -0,0 +1,123 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_ReconcileTo(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		external := map[string]bool{"a": true, "b": true}
+		current := ctor("a", "b")
+		target := ctor("b", "c", "d")
+
+		err := ReconcileTo(current, target, func(v string) error {
+			external[v] = true
+			return nil
+		}, func(v string) error {
+			delete(external, v)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+
+		if !current.Equal(target) {
+			t.Errorf("the receiver should track the reconciled state, got %v", current)
+		}
+		if !NewSetFromMapKeys(external).Equal(NewSet("b", "c", "d")) {
+			t.Errorf("the external state should match the target, got %v", external)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_ReconcileToErrors(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	current := NewSet(1, 2)
+	target := NewSet(2, 3, 4)
+
+	err := ReconcileTo(current, target, func(v int) error {
+		if v == 3 {
+			return errUnavailable
+		}
+		return nil
+	}, func(v int) error {
+		return errUnavailable
+	})
+
+	if !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected the callback errors to be wrapped, got %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "add 3") || !strings.Contains(msg, "remove 1") {
+		t.Errorf("expected the failed elements in the error, got %q", msg)
+	}
+	if !current.Equal(NewSet(1, 2, 4)) {
+		t.Errorf("only successful changes should be applied, got %v", current)
+	}
+
+	// retrying converges once the external system recovers
+	ok := func(int) error { return nil }
+	if err := ReconcileTo(current, target, ok, ok); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !current.Equal(target) {
+		t.Errorf("expected the receiver to converge, got %v", current)
+	}
+}
+
+func Test_ReconcileToNilCallback(t *testing.T) {
+	current := NewThreadUnsafeSet(1, 2)
+	if err := ReconcileTo(current, NewThreadUnsafeSet(2, 3), nil, func(int) error { return nil }); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !current.Equal(NewThreadUnsafeSet(2)) {
+		t.Errorf("a nil add callback should skip additions, got %v", current)
+	}
+}
+
+func Test_ReconcileToDecorated(t *testing.T) {
+	sink := &recordingSink[int]{}
+	current := NewAuditedSet[int](NewSet(1), sink)
+
+	ok := func(int) error { return nil }
+	if err := ReconcileTo(current, NewSet(2), ok, ok); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	ops := sink.ops()
+	if !ops[OpAdd].Equal(NewSet(2)) || !ops[OpRemove].Equal(NewSet(1)) {
+		t.Errorf("reconciliation of a decorated set should go through the decorator, got %v", sink.records)
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrSetExists is returned by Registry.Create for a name already in use.
+var ErrSetExists = errors.New("mapset: set already exists")
+
+// ErrNoSuchSet is returned by Registry.Drop for an unknown name.
+var ErrNoSuchSet = errors.New("mapset: no such set")
+
+// ErrInvalidName is returned by Registry.Create for names that can't name
+// a set: the empty name, "." and "..".
+var ErrInvalidName = errors.New("mapset: invalid set name")
+
+// SetMetrics describes the activity of a set of a Registry.
+type SetMetrics struct {
+	Cardinality int
+	// Adds and Removes count the elements actually added to and removed
+	// from the set since it was created or the registry opened.
+	Adds, Removes uint64
+}
+
+// Registry manages named thread-safe sets, such as the seen IDs of every
+// topic of an application, so that each one is created, looked up and
+// dropped by name and counts its own activity.
+//
+// A registry opened with OpenRegistry persists its sets: each set is a
+// WALSet in a subdirectory of the registry directory, named after the
+// escaped name of the set, and the sets found there are opened again.
+type Registry[T comparable] struct {
+	mu     sync.RWMutex
+	dir    string // empty for a registry in memory
+	policy CompactionPolicy
+	sets   map[string]*registryEntry[T]
+}
+
+type registryEntry[T comparable] struct {
+	// the counters come first to be 64-bit aligned for package atomic
+	adds, removes uint64
+
+	set Set[T]
+	wal *WALSet[T] // nil in memory
+}
+
+func (e *registryEntry[T]) count(op Op, vs []T) {
+	if op == OpAdd {
+		atomic.AddUint64(&e.adds, uint64(len(vs)))
+	} else {
+		atomic.AddUint64(&e.removes, uint64(len(vs)))
+	}
+}
+
+// NewRegistry returns an empty registry of sets kept in memory.
+func NewRegistry[T comparable]() *Registry[T] {
+	return &Registry[T]{sets: make(map[string]*registryEntry[T])}
+}
+
+// OpenRegistry opens the registry persisted in dir, creating dir if needed,
+// and opens the sets found there with the given compaction policy. dir must
+// not be used by another registry at the same time.
+func OpenRegistry[T comparable](dir string, policy CompactionPolicy) (*Registry[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Registry[T]{dir: dir, policy: policy, sets: make(map[string]*registryEntry[T])}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name, err := url.PathUnescape(entry.Name())
+		if err == nil {
+			err = r.create(name)
+		}
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// create adds an empty set, or the persisted one, named name. r.mu must be
+// held unless r isn't shared yet.
+func (r *Registry[T]) create(name string) error {
+	e := &registryEntry[T]{}
+	var base Set[T] = newThreadSafeSet[T]()
+	if r.dir != "" {
+		wal, err := OpenWALSet[T](filepath.Join(r.dir, url.PathEscape(name)), r.policy)
+		if err != nil {
+			return err
+		}
+		e.wal, base = wal, wal
+	}
+	e.set = newObservedSet[T](base, e.count)
+	r.sets[name] = e
+	return nil
+}
+
+// Create creates an empty set named name and returns it.
+func (r *Registry[T]) Create(name string) (Set[T], error) {
+	if name == "" || name == "." || name == ".." {
+		return nil, ErrInvalidName
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sets[name]; ok {
+		return nil, ErrSetExists
+	}
+	if err := r.create(name); err != nil {
+		return nil, err
+	}
+	return r.sets[name].set, nil
+}
+
+// Get returns the set named name, if any.
+func (r *Registry[T]) Get(name string) (Set[T], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.sets[name]
+	if !ok {
+		return nil, false
+	}
+	return e.set, true
+}
+
+// GetOrCreate returns the set named name, creating it if needed.
+func (r *Registry[T]) GetOrCreate(name string) (Set[T], error) {
+	if s, ok := r.Get(name); ok {
+		return s, nil
+	}
+	s, err := r.Create(name)
+	if err == ErrSetExists {
+		// another goroutine created it meanwhile
+		s, _ = r.Get(name)
+		return s, nil
+	}
+	return s, err
+}
+
+// Drop removes the set named name from the registry, along with its
+// persisted files. Holders of the set can still use it in memory.
+func (r *Registry[T]) Drop(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.sets[name]
+	if !ok {
+		return ErrNoSuchSet
+	}
+	delete(r.sets, name)
+	if e.wal == nil {
+		return nil
+	}
+	// the files are removed anyway, so only removal errors matter
+	_ = e.wal.Close()
+	return os.RemoveAll(filepath.Join(r.dir, url.PathEscape(name)))
+}
+
+// Names returns the names of the sets of the registry, sorted.
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.sets))
+	for name := range r.sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Metrics returns the metrics of the set named name, if any.
+func (r *Registry[T]) Metrics(name string) (SetMetrics, bool) {
+	r.mu.RLock()
+	e, ok := r.sets[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return SetMetrics{}, false
+	}
+	return SetMetrics{
+		Cardinality: e.set.Cardinality(),
+		Adds:        atomic.LoadUint64(&e.adds),
+		Removes:     atomic.LoadUint64(&e.removes),
+	}, true
+}
+
+// Close closes the persisted sets of the registry, which stay usable in
+// memory but are no longer persisted, and returns the errors met. It does
+// nothing for a registry in memory.
+func (r *Registry[T]) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for _, e := range r.sets {
+		if e.wal != nil {
+			if err := e.wal.Close(); err != nil && err != os.ErrClosed {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return joinErrors(errs...)
+}
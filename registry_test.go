@@ -0,0 +1,116 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_Registry(t *testing.T) {
+	r := NewRegistry[string]()
+
+	s, err := r.Create("topic/a")
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	s.Append("x", "y", "x")
+	s.Remove("y")
+	s.Remove("z")
+
+	if _, err := r.Create("topic/a"); err != ErrSetExists {
+		t.Errorf("expected ErrSetExists, got %v", err)
+	}
+	if _, err := r.Create(".."); err != ErrInvalidName {
+		t.Errorf("expected ErrInvalidName, got %v", err)
+	}
+	if got, err := r.GetOrCreate("topic/a"); err != nil || got != s {
+		t.Errorf("expected the existing set, got %v and %v", got, err)
+	}
+	if _, err := r.GetOrCreate("topic/b"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if names := r.Names(); len(names) != 2 || names[0] != "topic/a" || names[1] != "topic/b" {
+		t.Errorf("expected both names, got %v", names)
+	}
+
+	m, ok := r.Metrics("topic/a")
+	if !ok || m != (SetMetrics{Cardinality: 1, Adds: 2, Removes: 1}) {
+		t.Errorf("Expected %v, got: %v", SetMetrics{Cardinality: 1, Adds: 2, Removes: 1}, m)
+	}
+
+	if err := r.Drop("topic/a"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := r.Drop("topic/a"); err != ErrNoSuchSet {
+		t.Errorf("expected ErrNoSuchSet, got %v", err)
+	}
+	if _, ok := r.Get("topic/a"); ok {
+		t.Error("expected the dropped set to be gone")
+	}
+	if _, ok := r.Metrics("topic/a"); ok {
+		t.Error("expected no metrics for the dropped set")
+	}
+}
+
+func Test_RegistryPersistence(t *testing.T) {
+	dir := t.TempDir()
+	r, err := OpenRegistry[string](dir, CompactionPolicy{})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	for _, name := range []string{"a/b", "c", "dropped"} {
+		s, err := r.Create(name)
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		s.Append(name, "shared")
+	}
+	if err := r.Drop("dropped"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	r, err = OpenRegistry[string](dir, CompactionPolicy{})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer r.Close()
+	if names := r.Names(); len(names) != 2 || names[0] != "a/b" || names[1] != "c" {
+		t.Errorf("expected a/b and c, got %v", names)
+	}
+	if s, ok := r.Get("a/b"); !ok || !s.Equal(NewSet("a/b", "shared")) {
+		t.Errorf("expected the persisted elements, got %v", s)
+	}
+	if caps := CapabilitiesOf[string](r.sets["c"].set); !caps.Has(CapPersistent) {
+		t.Errorf("expected a persistent set, got %v", caps)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected 2 set directories, got %d", len(entries))
+	}
+}
@@ -0,0 +1,458 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Client is a mapset.Set whose elements are held by a server created with
+// NewHandler. Every method makes at least one request to the server.
+//
+// Since the methods of mapset.Set don't return errors, failed requests are
+// recorded and reported by Err; the failed method itself behaves as if the
+// set was empty. Methods returning sets, such as Union or Clone, return
+// local thread-safe sets holding the result.
+//
+// Methods reading all the elements, such as Each or Intersect, operate on a
+// copy fetched from the server, except Union and ContainsAnyElement which
+// are computed by the server. A Client is safe for concurrent use.
+//
+// Like any mix of implementations, a Client cannot be the argument of the
+// operations of a local set that require an operand of the same type, such
+// as Union or Intersect, which panic; use the local copy returned by Clone:
+//
+//	merged := local.Union(client.Clone())
+type Client[T comparable] struct {
+	url  string
+	http *http.Client
+
+	mu  sync.Mutex
+	err error
+}
+
+var _ mapset.Set[int] = (*Client[int])(nil)
+
+// DefaultTimeout bounds the requests of the clients created by NewClient
+// without an http.Client.
+const DefaultTimeout = 30 * time.Second
+
+// NewClient returns a Client for the set served at url. If httpClient is
+// nil, a client whose requests time out after DefaultTimeout is used, unlike
+// http.DefaultClient which waits forever for an unresponsive server.
+func NewClient[T comparable](url string, httpClient *http.Client) *Client[T] {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Client[T]{url: strings.TrimSuffix(url, "/"), http: httpClient}
+}
+
+// Err returns the first error that occurred since the previous call to Err,
+// and resets it.
+func (c *Client[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.err
+	c.err = nil
+	return err
+}
+
+func (c *Client[T]) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// call sends in, if not nil, to the given path and decodes the response
+// into out, if not nil. Errors are recorded and returned.
+func (c *Client[T]) call(path string, in, out any) error {
+	err := c.do(context.Background(), path, in, out)
+	if err != nil {
+		err = fmt.Errorf("remote: %s: %w", path, err)
+		c.fail(err)
+	}
+	return err
+}
+
+func (c *Client[T]) do(ctx context.Context, path string, in, out any) error {
+	method := methods[path]
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// snapshot fetches a local copy of the elements.
+func (c *Client[T]) snapshot() mapset.Set[T] {
+	s := mapset.NewSet[T]()
+	if err := c.call(pathElements, nil, s); err != nil {
+		return mapset.NewSet[T]()
+	}
+	return s
+}
+
+// local returns a local thread-safe copy of other, so that it can be
+// combined with snapshots whatever its implementation.
+func local[T comparable](other mapset.Set[T]) mapset.Set[T] {
+	if c, ok := other.(*Client[T]); ok {
+		return c.snapshot()
+	}
+	return mapset.NewSet(other.ToSlice()...)
+}
+
+func (c *Client[T]) add(elems []T) (int, error) {
+	var resp addResponse
+	err := c.call(pathAdd, elems, &resp)
+	return resp.Added, err
+}
+
+func (c *Client[T]) remove(elems []T) []T {
+	var removed []T
+	c.call(pathRemove, elems, &removed)
+	return removed
+}
+
+func (c *Client[T]) contains(elems []T) containsResponse {
+	var resp containsResponse
+	c.call(pathContains, elems, &resp)
+	return resp
+}
+
+func (c *Client[T]) Add(val T) bool {
+	n, _ := c.add([]T{val})
+	return n == 1
+}
+
+func (c *Client[T]) Append(val ...T) int {
+	n, _ := c.add(val)
+	return n
+}
+
+func (c *Client[T]) AppendFrom(other mapset.Set[T]) int {
+	n, _ := c.add(other.ToSlice())
+	return n
+}
+
+func (c *Client[T]) Cardinality() int {
+	var resp cardinalityResponse
+	c.call(pathCardinality, nil, &resp)
+	return resp.Cardinality
+}
+
+func (c *Client[T]) Capabilities() mapset.Capabilities {
+	return mapset.CapThreadSafe | mapset.CapRemote
+}
+
+func (c *Client[T]) Clear() {
+	c.call(pathClear, struct{}{}, nil)
+}
+
+func (c *Client[T]) Clone() mapset.Set[T] {
+	return c.snapshot()
+}
+
+func (c *Client[T]) Contains(val ...T) bool {
+	return c.contains(val).All
+}
+
+func (c *Client[T]) ContainsOne(val T) bool {
+	return c.contains([]T{val}).All
+}
+
+func (c *Client[T]) ContainsAny(val ...T) bool {
+	return c.contains(val).Any
+}
+
+func (c *Client[T]) ContainsAnyElement(other mapset.Set[T]) bool {
+	return c.contains(other.ToSlice()).Any
+}
+
+func (c *Client[T]) Difference(other mapset.Set[T]) mapset.Set[T] {
+	return c.snapshot().Difference(local(other))
+}
+
+func (c *Client[T]) Equal(other mapset.Set[T]) bool {
+	return c.snapshot().Equal(local(other))
+}
+
+func (c *Client[T]) Intersect(other mapset.Set[T]) mapset.Set[T] {
+	return c.snapshot().Intersect(local(other))
+}
+
+func (c *Client[T]) IsEmpty() bool {
+	return c.Cardinality() == 0
+}
+
+func (c *Client[T]) IsProperSubset(other mapset.Set[T]) bool {
+	return c.snapshot().IsProperSubset(local(other))
+}
+
+func (c *Client[T]) IsProperSuperset(other mapset.Set[T]) bool {
+	return c.snapshot().IsProperSuperset(local(other))
+}
+
+func (c *Client[T]) IsSubset(other mapset.Set[T]) bool {
+	return c.snapshot().IsSubset(local(other))
+}
+
+func (c *Client[T]) IsSuperset(other mapset.Set[T]) bool {
+	return c.contains(other.ToSlice()).All
+}
+
+func (c *Client[T]) Each(cb func(T) bool) {
+	c.snapshot().Each(cb)
+}
+
+func (c *Client[T]) EachErr(cb func(T) error) error {
+	return mapset.EachErr(c.snapshot(), cb)
+}
+
+func (c *Client[T]) Filter(cb func(T) bool) mapset.Set[T] {
+	return c.snapshot().Filter(cb)
+}
+
+func (c *Client[T]) Partition(pred func(T) bool) (mapset.Set[T], mapset.Set[T]) {
+	return mapset.Partition(c.snapshot(), pred)
+}
+
+func (c *Client[T]) PartitionN(n int, hash func(T) uint64) []mapset.Set[T] {
+	return mapset.PartitionN(c.snapshot(), n, hash)
+}
+
+func (c *Client[T]) All() func(yield func(element T) bool) {
+	return mapset.Elements(c.snapshot())
+}
+
+func (c *Client[T]) Iter() <-chan T {
+	return c.snapshot().Iter()
+}
+
+func (c *Client[T]) Iterator() *mapset.Iterator[T] {
+	return c.snapshot().Iterator()
+}
+
+func (c *Client[T]) Remove(i T) {
+	c.remove([]T{i})
+}
+
+func (c *Client[T]) RemoveAll(i ...T) {
+	c.remove(i)
+}
+
+func (c *Client[T]) RemovedWhich(i ...T) []T {
+	return c.remove(i)
+}
+
+func (c *Client[T]) ReconcileTo(target mapset.Set[T], add func(T) error, remove func(T) error) error {
+	// the callbacks run against a snapshot, and every change is applied
+	// to the server once its callback succeeded
+	current := c.snapshot()
+	applied := current.Clone()
+	err := mapset.ReconcileTo(applied, target, add, remove)
+
+	if added := applied.Difference(current); !added.IsEmpty() {
+		c.add(added.ToSlice())
+	}
+	if removed := current.Difference(applied); !removed.IsEmpty() {
+		c.remove(removed.ToSlice())
+	}
+	return err
+}
+
+func (c *Client[T]) String() string {
+	return c.snapshot().String()
+}
+
+func (c *Client[T]) SymmetricDifference(other mapset.Set[T]) mapset.Set[T] {
+	return c.snapshot().SymmetricDifference(local(other))
+}
+
+func (c *Client[T]) Union(other mapset.Set[T]) mapset.Set[T] {
+	union := mapset.NewSet[T]()
+	if err := c.call(pathUnion, other.ToSlice(), union); err != nil {
+		return mapset.NewSet[T]()
+	}
+	return union
+}
+
+func (c *Client[T]) Pop() (v T, ok bool) {
+	popped, n := c.PopN(1)
+	if n == 0 {
+		return v, false
+	}
+	return popped[0], true
+}
+
+func (c *Client[T]) PopN(n int) ([]T, int) {
+	var popped []T
+	if n > 0 {
+		c.call(pathPop, popRequest{N: n}, &popped)
+	}
+	return popped, len(popped)
+}
+
+func (c *Client[T]) ToSlice() []T {
+	return c.snapshot().ToSlice()
+}
+
+func (c *Client[T]) CanonicalBytes() []byte {
+	return mapset.CanonicalBytes(c.snapshot())
+}
+
+func (c *Client[T]) MarshalJSON() ([]byte, error) {
+	s := mapset.NewSet[T]()
+	if err := c.call(pathElements, nil, s); err != nil {
+		return nil, err
+	}
+	return s.MarshalJSON()
+}
+
+// UnmarshalJSON adds the elements of the JSON array b to the set.
+func (c *Client[T]) UnmarshalJSON(b []byte) error {
+	s := mapset.NewSet[T]()
+	if err := s.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	_, err := c.add(s.ToSlice())
+	return err
+}
+
+func (c *Client[T]) MarshalBinary() ([]byte, error) {
+	s := mapset.NewSet[T]()
+	if err := c.call(pathElements, nil, s); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := mapset.WriteSnapshot(&buf, s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary adds the elements of the snapshot data to the set.
+func (c *Client[T]) UnmarshalBinary(data []byte) error {
+	s := mapset.NewSet[T]()
+	if err := mapset.ReadSnapshot(bytes.NewReader(data), s); err != nil {
+		return err
+	}
+	_, err := c.add(s.ToSlice())
+	return err
+}
+
+func (c *Client[T]) MarshalText() ([]byte, error) {
+	s := mapset.NewSet[T]()
+	if err := c.call(pathElements, nil, s); err != nil {
+		return nil, err
+	}
+	return mapset.EncodeText(s, mapset.DefaultTextSeparator)
+}
+
+// UnmarshalText adds the elements of comma-separated text to the set.
+func (c *Client[T]) UnmarshalText(text []byte) error {
+	s := mapset.NewSet[T]()
+	if err := mapset.DecodeText(text, s, mapset.DefaultTextSeparator); err != nil {
+		return err
+	}
+	_, err := c.add(s.ToSlice())
+	return err
+}
+
+func (c *Client[T]) MarshalYAML() (interface{}, error) {
+	s := mapset.NewSet[T]()
+	if err := c.call(pathElements, nil, s); err != nil {
+		return nil, err
+	}
+	return s.ToSlice(), nil
+}
+
+// UnmarshalYAML adds the elements of a YAML sequence to the set.
+func (c *Client[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var elems []T
+	if err := unmarshal(&elems); err != nil {
+		return err
+	}
+	_, err := c.add(elems)
+	return err
+}
+
+func (c *Client[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	s := mapset.NewSet[T]()
+	if err := c.call(pathElements, nil, s); err != nil {
+		return 0, nil, err
+	}
+	return s.MarshalBSONValue()
+}
+
+// UnmarshalBSONValue adds the elements of the BSON array b to the set.
+func (c *Client[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	s := mapset.NewSet[T]()
+	if err := s.UnmarshalBSONValue(bt, b); err != nil {
+		return err
+	}
+	_, err := c.add(s.ToSlice())
+	return err
+}
@@ -0,0 +1,168 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// Paths served by the handler, relative to where it is mounted.
+const (
+	pathElements    = "/"
+	pathCardinality = "/cardinality"
+	pathAdd         = "/add"
+	pathRemove      = "/remove"
+	pathContains    = "/contains"
+	pathUnion       = "/union"
+	pathPop         = "/pop"
+	pathClear       = "/clear"
+)
+
+// methods maps every path to the method it accepts.
+var methods = map[string]string{
+	pathElements:    http.MethodGet,
+	pathCardinality: http.MethodGet,
+	pathAdd:         http.MethodPost,
+	pathRemove:      http.MethodPost,
+	pathContains:    http.MethodPost,
+	pathUnion:       http.MethodPost,
+	pathPop:         http.MethodPost,
+	pathClear:       http.MethodPost,
+}
+
+type cardinalityResponse struct {
+	Cardinality int `json:"cardinality"`
+}
+
+type addResponse struct {
+	Added int `json:"added"`
+}
+
+type containsResponse struct {
+	All bool `json:"all"`
+	Any bool `json:"any"`
+}
+
+type popRequest struct {
+	N int `json:"n"`
+}
+
+// maxRequestBytes bounds the size of the request bodies read by the handler.
+const maxRequestBytes = 32 << 20
+
+// handler serves a set, see NewHandler.
+type handler[T comparable] struct {
+	s mapset.Set[T]
+}
+
+// NewHandler returns an http.Handler serving s to clients created with
+// NewClient. The handler is safe for concurrent use if s is.
+//
+// The endpoints are:
+//
+//	GET  /            the elements
+//	GET  /cardinality the number of elements
+//	POST /add         adds the posted elements
+//	POST /remove      removes the posted elements, returns the removed ones
+//	POST /contains    whether all, and any, of the posted elements are present
+//	POST /union       returns the union with the posted elements
+//	POST /pop         removes and returns up to n arbitrary elements
+//	POST /clear       removes all the elements
+//
+// Request bodies larger than 32 MiB are rejected.
+func NewHandler[T comparable](s mapset.Set[T]) http.Handler {
+	return &handler[T]{s: s}
+}
+
+func (h *handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, found := methods[r.URL.Path]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Path {
+	case pathElements:
+		writeJSON(w, h.s)
+	case pathCardinality:
+		writeJSON(w, cardinalityResponse{h.s.Cardinality()})
+	case pathClear:
+		h.s.Clear()
+		w.WriteHeader(http.StatusNoContent)
+	case pathPop:
+		var req popRequest
+		if readJSON(w, r, &req) {
+			popped, _ := h.s.PopN(req.N)
+			writeJSON(w, popped)
+		}
+	default:
+		var elems []T
+		if !readJSON(w, r, &elems) {
+			return
+		}
+		switch r.URL.Path {
+		case pathAdd:
+			writeJSON(w, addResponse{h.s.Append(elems...)})
+		case pathRemove:
+			writeJSON(w, mapset.RemovedWhich(h.s, elems...))
+		case pathContains:
+			writeJSON(w, containsResponse{All: h.s.Contains(elems...), Any: h.s.ContainsAny(elems...)})
+		case pathUnion:
+			union := h.s.Clone()
+			union.Append(elems...)
+			writeJSON(w, union)
+		}
+	}
+}
+
+// readJSON decodes the body of r into v, it replies with an error and
+// returns false if that fails.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	body := http.MaxBytesReader(w, r.Body, maxRequestBytes)
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
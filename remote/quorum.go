@@ -0,0 +1,268 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNoQuorum is returned by the methods of QuorumClient when not enough
+// replicas answered, or agreed, to reach the quorum.
+var ErrNoQuorum = errors.New("remote: no quorum")
+
+// QuorumClient accesses a set replicated on several servers, each served by
+// NewHandler, and resolves every operation by quorum, so that membership
+// stays correct when a replica lags or fails:
+//
+//   - Contains asks quorum replicas and returns the answer as soon as
+//     quorum of them agree. Replicas that are slower than the hedge delay
+//     are hedged by asking one more replica, so a single slow replica
+//     doesn't slow reads down.
+//   - Add and Remove are sent to all replicas and return as soon as quorum
+//     of them acknowledged the change.
+//
+// The reads of a call are canceled once its outcome is decided. Writes are
+// not: they keep going after the call returned, or its context was
+// canceled, so that the slower replicas apply them too, and are only
+// bounded by DefaultTimeout. Replicas that failed or timed out may miss a
+// write and must be repaired by other means, for instance with
+// ReconcileTo.
+//
+// With a majority quorum, the default, reads see the writes that returned
+// successfully. A QuorumClient is safe for concurrent use.
+type QuorumClient[T comparable] struct {
+	replicas []*Client[T]
+	quorum   int
+	hedge    time.Duration
+}
+
+type replicaResult struct {
+	value bool
+	err   error
+}
+
+// NewQuorumClient returns a QuorumClient for the set replicated on the
+// servers of replicas. A quorum that is not positive or larger than the
+// number of replicas selects a majority; a hedge delay that is not positive
+// sends reads to all the replicas at once.
+func NewQuorumClient[T comparable](replicas []*Client[T], quorum int, hedge time.Duration) *QuorumClient[T] {
+	if quorum <= 0 || quorum > len(replicas) {
+		quorum = len(replicas)/2 + 1
+	}
+	return &QuorumClient[T]{replicas: replicas, quorum: quorum, hedge: hedge}
+}
+
+// Quorum returns the number of replicas that must agree on an answer.
+func (q *QuorumClient[T]) Quorum() int {
+	return q.quorum
+}
+
+// Contains returns whether v is in the set according to a quorum of
+// replicas.
+func (q *QuorumClient[T]) Contains(ctx context.Context, v T) (bool, error) {
+	return q.read(ctx, func(ctx context.Context, c *Client[T]) (bool, error) {
+		var resp containsResponse
+		err := c.do(ctx, pathContains, []T{v}, &resp)
+		return resp.All, wrapErr(pathContains, err)
+	})
+}
+
+// Add adds v to all the replicas and returns once a quorum of them
+// acknowledged it. Returns whether v was added to any of them.
+func (q *QuorumClient[T]) Add(ctx context.Context, v T) (bool, error) {
+	return q.write(ctx, func(ctx context.Context, c *Client[T]) (bool, error) {
+		var resp addResponse
+		err := c.do(ctx, pathAdd, []T{v}, &resp)
+		return resp.Added == 1, wrapErr(pathAdd, err)
+	})
+}
+
+// Remove removes v from all the replicas and returns once a quorum of them
+// acknowledged it. Returns whether v was removed from any of them.
+func (q *QuorumClient[T]) Remove(ctx context.Context, v T) (bool, error) {
+	return q.write(ctx, func(ctx context.Context, c *Client[T]) (bool, error) {
+		var removed []T
+		err := c.do(ctx, pathRemove, []T{v}, &removed)
+		return len(removed) > 0, wrapErr(pathRemove, err)
+	})
+}
+
+// read asks replicas in turn until a quorum of them gave the same answer:
+// quorum replicas first, then one more whenever one fails, disagrees or,
+// when hedging, takes longer than the hedge delay.
+func (q *QuorumClient[T]) read(ctx context.Context, ask func(context.Context, *Client[T]) (bool, error)) (bool, error) {
+	if len(q.replicas) == 0 {
+		return false, ErrNoQuorum
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan replicaResult, len(q.replicas))
+	next, pending := 0, 0
+	launch := func() {
+		c := q.replicas[next]
+		next++
+		pending++
+		go func() {
+			v, err := ask(ctx, c)
+			results <- replicaResult{value: v, err: err}
+		}()
+	}
+
+	first := q.quorum
+	if q.hedge <= 0 {
+		first = len(q.replicas)
+	}
+	for next < first {
+		launch()
+	}
+	var hedge <-chan time.Time
+	var timer *time.Timer
+	if next < len(q.replicas) {
+		timer = time.NewTimer(q.hedge)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	var votes [2]int
+	var lastErr error
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				i := boolIndex(r.value)
+				if votes[i]++; votes[i] >= q.quorum {
+					return r.value, nil
+				}
+			} else {
+				lastErr = r.err
+			}
+			// ask another replica to replace a failed one, or to break a
+			// tie once all the pending ones answered
+			if r.err == nil && pending > 0 {
+				continue
+			}
+			if next < len(q.replicas) {
+				launch()
+			} else if pending == 0 {
+				return false, noQuorum(votes, lastErr)
+			}
+		case <-hedge:
+			hedge = nil
+			if next < len(q.replicas) {
+				launch()
+			}
+			if next < len(q.replicas) {
+				timer.Reset(q.hedge)
+				hedge = timer.C
+			}
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// write sends a mutation to all the replicas and waits for a quorum of
+// acknowledgements. Canceling ctx only stops the wait: the mutation is sent
+// with a context that keeps the values of ctx, but not its cancellation, so
+// that the replicas that didn't answer yet still apply it.
+func (q *QuorumClient[T]) write(ctx context.Context, apply func(context.Context, *Client[T]) (bool, error)) (bool, error) {
+	if len(q.replicas) == 0 {
+		return false, ErrNoQuorum
+	}
+	writeCtx, cancel := context.WithTimeout(detachedContext{ctx}, DefaultTimeout)
+
+	results := make(chan replicaResult, len(q.replicas))
+	var wg sync.WaitGroup
+	wg.Add(len(q.replicas))
+	for _, c := range q.replicas {
+		go func(c *Client[T]) {
+			defer wg.Done()
+			changed, err := apply(writeCtx, c)
+			results <- replicaResult{value: changed, err: err}
+		}(c)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+	}()
+
+	acks, failures, changed := 0, 0, false
+	for range q.replicas {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				if failures++; failures > len(q.replicas)-q.quorum {
+					return false, fmt.Errorf("%w: %d of %d replicas failed: %v", ErrNoQuorum, failures, len(q.replicas), r.err)
+				}
+				continue
+			}
+			changed = changed || r.value
+			if acks++; acks >= q.quorum {
+				return changed, nil
+			}
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	return false, ErrNoQuorum // unreachable
+}
+
+// detachedContext carries the values of a context without its deadline and
+// cancellation, like context.WithoutCancel of Go 1.21.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func noQuorum(votes [2]int, lastErr error) error {
+	if lastErr != nil {
+		return fmt.Errorf("%w: %d present, %d absent: %v", ErrNoQuorum, votes[1], votes[0], lastErr)
+	}
+	return fmt.Errorf("%w: %d present, %d absent", ErrNoQuorum, votes[1], votes[0])
+}
+
+func wrapErr(path string, err error) error {
+	if err != nil {
+		return fmt.Errorf("remote: %s: %w", path, err)
+	}
+	return nil
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,151 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// newReplica serves s, delaying every request by delay.
+func newReplica(t *testing.T, s mapset.Set[string], delay time.Duration) (*Client[string], *httptest.Server) {
+	h := NewHandler(s)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server only notices that the client went away once the
+		// body has been read
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		select {
+		case <-time.After(delay):
+			h.ServeHTTP(w, r)
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient[string](srv.URL, srv.Client()), srv
+}
+
+func Test_QuorumClient(t *testing.T) {
+	ctx := context.Background()
+	sets := []mapset.Set[string]{mapset.NewSet("a"), mapset.NewSet("a"), mapset.NewSet[string]()}
+	var replicas []*Client[string]
+	for _, s := range sets {
+		c, _ := newReplica(t, s, 0)
+		replicas = append(replicas, c)
+	}
+	q := NewQuorumClient(replicas, 0, 0)
+	if q.Quorum() != 2 {
+		t.Errorf("expected a majority quorum of 2, got %d", q.Quorum())
+	}
+
+	// the third replica lags
+	if found, err := q.Contains(ctx, "a"); err != nil || !found {
+		t.Errorf("expected a to be found by quorum, got %v, %v", found, err)
+	}
+
+	if added, err := q.Add(ctx, "b"); err != nil || !added {
+		t.Errorf("expected b to be added, got %v, %v", added, err)
+	}
+	if found, err := q.Contains(ctx, "b"); err != nil || !found {
+		t.Errorf("expected b to be found, got %v, %v", found, err)
+	}
+	if removed, err := q.Remove(ctx, "a"); err != nil || !removed {
+		t.Errorf("expected a to be removed, got %v, %v", removed, err)
+	}
+	if found, err := q.Contains(ctx, "a"); err != nil || found {
+		t.Errorf("expected a to be absent, got %v, %v", found, err)
+	}
+}
+
+func Test_QuorumClientHedged(t *testing.T) {
+	slow, _ := newReplica(t, mapset.NewSet("a"), time.Hour)
+	fast1, _ := newReplica(t, mapset.NewSet("a"), 0)
+	fast2, _ := newReplica(t, mapset.NewSet("a"), 0)
+	q := NewQuorumClient([]*Client[string]{slow, fast1, fast2}, 2, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if found, err := q.Contains(ctx, "a"); err != nil || !found {
+		t.Errorf("expected the slow replica to be hedged, got %v, %v", found, err)
+	}
+}
+
+func Test_QuorumClientFailures(t *testing.T) {
+	ctx := context.Background()
+	up, _ := newReplica(t, mapset.NewSet("a"), 0)
+	down1, srv1 := newReplica(t, mapset.NewSet("a"), 0)
+	down2, srv2 := newReplica(t, mapset.NewSet("a"), 0)
+	srv1.Close()
+
+	q := NewQuorumClient([]*Client[string]{down1, up, down2}, 0, time.Millisecond)
+	if found, err := q.Contains(ctx, "a"); err != nil || !found {
+		t.Errorf("expected a quorum with one replica down, got %v, %v", found, err)
+	}
+	if _, err := q.Add(ctx, "b"); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+
+	srv2.Close()
+	if _, err := q.Contains(ctx, "a"); !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("Expected %v, got: %v", ErrNoQuorum, err)
+	}
+	if _, err := q.Add(ctx, "c"); !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("Expected %v, got: %v", ErrNoQuorum, err)
+	}
+	if _, err := NewQuorumClient[string](nil, 0, 0).Contains(ctx, "a"); !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("Expected %v without replicas, got: %v", ErrNoQuorum, err)
+	}
+}
+
+func Test_QuorumClientSlowWrite(t *testing.T) {
+	lagging := mapset.NewSet[string]()
+	slow, _ := newReplica(t, lagging, 50*time.Millisecond)
+	fast1, _ := newReplica(t, mapset.NewSet[string](), 0)
+	fast2, _ := newReplica(t, mapset.NewSet[string](), 0)
+	q := NewQuorumClient([]*Client[string]{slow, fast1, fast2}, 2, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := q.Add(ctx, "a"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	// neither the quorum nor canceling the call aborts the slower write
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for !lagging.ContainsOne("a") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the slow replica to apply the write")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
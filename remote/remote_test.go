@@ -0,0 +1,234 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+func newTestClient(t *testing.T, s mapset.Set[string]) *Client[string] {
+	srv := httptest.NewServer(http.StripPrefix("/set", NewHandler(s)))
+	t.Cleanup(srv.Close)
+	return NewClient[string](srv.URL+"/set/", srv.Client())
+}
+
+func Test_Client(t *testing.T) {
+	authoritative := mapset.NewSet[string]()
+	c := newTestClient(t, authoritative)
+
+	if caps := c.Capabilities(); !caps.Has(mapset.CapRemote | mapset.CapThreadSafe) {
+		t.Errorf("expected the client to be remote and thread-safe, got %v", caps)
+	}
+	if !c.Add("alice") || c.Add("alice") {
+		t.Error("Add should report whether the element was added")
+	}
+	if n := c.Append("bob", "carol", "bob"); n != 2 {
+		t.Errorf("expected 2 elements to be appended, got %d", n)
+	}
+	if n := c.AppendFrom(mapset.NewThreadUnsafeSet("dave")); n != 1 {
+		t.Errorf("expected 1 element to be appended, got %d", n)
+	}
+	if !authoritative.Equal(mapset.NewSet("alice", "bob", "carol", "dave")) {
+		t.Errorf("the changes should be applied to the served set, got %v", authoritative)
+	}
+
+	if c.Cardinality() != 4 || c.IsEmpty() {
+		t.Errorf("expected 4 elements, got %d", c.Cardinality())
+	}
+	if !c.Contains("alice", "bob") || c.Contains("alice", "eve") || !c.ContainsOne("carol") {
+		t.Error("unexpected result of Contains")
+	}
+	if !c.ContainsAny("eve", "dave") || c.ContainsAny("eve") {
+		t.Error("unexpected result of ContainsAny")
+	}
+	if !c.ContainsAnyElement(mapset.NewSet("eve", "dave")) {
+		t.Error("unexpected result of ContainsAnyElement")
+	}
+
+	removed := c.RemovedWhich("alice", "eve")
+	if len(removed) != 1 || removed[0] != "alice" {
+		t.Errorf("expected alice to be removed, got %v", removed)
+	}
+	c.Remove("bob")
+	c.RemoveAll("carol")
+	if !c.Equal(mapset.NewSet("dave")) {
+		t.Errorf("expected only dave to remain, got %v", c)
+	}
+
+	if v, ok := c.Pop(); !ok || v != "dave" {
+		t.Errorf("expected dave to be popped, got %v", v)
+	}
+	if _, ok := c.Pop(); ok {
+		t.Error("popping an empty set should report false")
+	}
+
+	c.Append("x", "y")
+	c.Clear()
+	if !authoritative.IsEmpty() {
+		t.Errorf("Clear should empty the served set, got %v", authoritative)
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+}
+
+func Test_ClientAlgebra(t *testing.T) {
+	c := newTestClient(t, mapset.NewSet("a", "b", "c"))
+
+	for _, other := range []mapset.Set[string]{mapset.NewSet("b", "c", "d"), mapset.NewThreadUnsafeSet("b", "c", "d")} {
+		if u := c.Union(other); !u.Equal(mapset.NewSet("a", "b", "c", "d")) {
+			t.Errorf("unexpected union: %v", u)
+		}
+		if i := c.Intersect(other); !i.Equal(mapset.NewSet("b", "c")) {
+			t.Errorf("unexpected intersection: %v", i)
+		}
+		if d := c.Difference(other); !d.Equal(mapset.NewSet("a")) {
+			t.Errorf("unexpected difference: %v", d)
+		}
+		if sd := c.SymmetricDifference(other); !sd.Equal(mapset.NewSet("a", "d")) {
+			t.Errorf("unexpected symmetric difference: %v", sd)
+		}
+		if c.IsSubset(other) || c.IsSuperset(other) {
+			t.Error("the sets should not be subsets of each other")
+		}
+	}
+
+	if !c.IsSuperset(mapset.NewSet("a")) || !c.IsProperSuperset(mapset.NewSet("a")) {
+		t.Error("the set should be a proper superset of {a}")
+	}
+	if !c.IsSubset(mapset.NewSet("a", "b", "c")) || c.IsProperSubset(mapset.NewSet("a", "b", "c")) {
+		t.Error("the set should be a subset, but not a proper one, of itself")
+	}
+
+	// another client can be used as an operand
+	other := newTestClient(t, mapset.NewSet("c"))
+	if i := c.Intersect(other); !i.Equal(mapset.NewSet("c")) {
+		t.Errorf("unexpected intersection with another client: %v", i)
+	}
+
+	if f := c.Filter(func(v string) bool { return v != "a" }); !f.Equal(mapset.NewSet("b", "c")) {
+		t.Errorf("unexpected filtered set: %v", f)
+	}
+	if clone := c.Clone(); !clone.Equal(mapset.NewSet("a", "b", "c")) {
+		t.Errorf("unexpected clone: %v", clone)
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+}
+
+func Test_ClientReconcileTo(t *testing.T) {
+	authoritative := mapset.NewSet("a", "b")
+	c := newTestClient(t, authoritative)
+
+	errFailed := errors.New("failed")
+	err := c.ReconcileTo(mapset.NewSet("b", "c", "d"), func(v string) error {
+		if v == "d" {
+			return errFailed
+		}
+		return nil
+	}, func(string) error {
+		return nil
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Expected the error of the callback, got: %v", err)
+	}
+	if !authoritative.Equal(mapset.NewSet("b", "c")) {
+		t.Errorf("only the successful changes should be applied, got %v", authoritative)
+	}
+}
+
+func Test_ClientJSON(t *testing.T) {
+	c := newTestClient(t, mapset.NewSet("a"))
+
+	if err := json.Unmarshal([]byte(`["b"]`), c); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	decoded := mapset.NewSet[string]()
+	if err := json.Unmarshal(b, decoded); err != nil || !decoded.Equal(mapset.NewSet("a", "b")) {
+		t.Errorf("unexpected JSON encoding %s: %v", b, err)
+	}
+}
+
+func Test_ClientErr(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(mapset.NewSet("a")))
+	c := NewClient[string](srv.URL, nil)
+	srv.Close()
+
+	if c.Contains("a") || c.Cardinality() != 0 || !c.Clone().IsEmpty() {
+		t.Error("failed calls should behave as if the set was empty")
+	}
+	if err := c.Err(); err == nil {
+		t.Error("the failure should be reported by Err")
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("Err should reset the error, got: %v", err)
+	}
+	if _, err := c.MarshalJSON(); err == nil {
+		t.Error("MarshalJSON should report the failure")
+	}
+}
+
+func Test_NewClientTimeout(t *testing.T) {
+	if c := NewClient[string]("http://localhost", nil); c.http.Timeout != DefaultTimeout {
+		t.Errorf("expected a %v timeout, got %v", DefaultTimeout, c.http.Timeout)
+	}
+}
+
+func Test_Handler(t *testing.T) {
+	h := NewHandler(mapset.NewSet("a"))
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/", "", http.StatusOK},
+		{http.MethodPost, "/", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/add", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/add", "not json", http.StatusBadRequest},
+		{http.MethodPost, "/add", `["` + strings.Repeat("a", maxRequestBytes) + `"]`, http.StatusBadRequest},
+		{http.MethodPost, "/pop", `{"n": 1}`, http.StatusOK},
+		{http.MethodGet, "/unknown", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, rec.Code)
+		}
+	}
+}
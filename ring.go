@@ -0,0 +1,170 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// DefaultReplicas is the number of points every node gets on a Ring when
+// NewRing is called with a non-positive number of replicas.
+const DefaultReplicas = 128
+
+// Ring is a consistent hashing ring over a set of nodes: every key is owned
+// by one node, and adding or removing a node only moves the keys of that
+// node, about 1/n of them. Each node is placed on the ring several times,
+// as virtual nodes, so that keys are spread evenly. A Ring is safe for
+// concurrent use.
+//
+// Node positions derive from the JSON encoding of the nodes, so rings built
+// from the same nodes agree on the owners of all keys across processes.
+type Ring[T comparable] struct {
+	mu       sync.RWMutex
+	replicas int
+	nodes    Set[T]
+	// points holds the positions of the virtual nodes, sorted by hash.
+	points []ringPoint[T]
+}
+
+type ringPoint[T comparable] struct {
+	hash uint64
+	node T
+}
+
+// NewRing creates and returns a Ring with the elements of nodes as members,
+// each placed replicas times on the ring. nodes is copied, later changes
+// must go through Add and Remove.
+func NewRing[T comparable](nodes Set[T], replicas int) *Ring[T] {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	r := &Ring[T]{replicas: replicas, nodes: NewThreadUnsafeSet[T]()}
+	for _, node := range nodes.ToSlice() {
+		r.add(node)
+	}
+	r.sort()
+	return r
+}
+
+// Add adds a node to the ring and returns whether it was added. Keys only
+// move from other nodes to the new one.
+func (r *Ring[T]) Add(node T) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.add(node) {
+		return false
+	}
+	r.sort()
+	return true
+}
+
+// add must be called with the write lock held, and followed by sort.
+func (r *Ring[T]) add(node T) bool {
+	if !r.nodes.Add(node) {
+		return false
+	}
+	base := canonicalElement(node)
+	for i := 0; i < r.replicas; i++ {
+		r.points = append(r.points, ringPoint[T]{hash: ringHash(base, i), node: node})
+	}
+	return true
+}
+
+// sort must be called with the write lock held.
+func (r *Ring[T]) sort() {
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i].hash < r.points[j].hash
+	})
+}
+
+// Remove removes a node from the ring and returns whether it was present.
+// Only the keys of the removed node move to other nodes.
+func (r *Ring[T]) Remove(node T) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.nodes.ContainsOne(node) {
+		return false
+	}
+	r.nodes.Remove(node)
+	points := r.points[:0]
+	for _, p := range r.points {
+		if p.node != node {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+	return true
+}
+
+// Owner returns the node owning key, and false if the ring has no node.
+func (r *Ring[T]) Owner(key []byte) (node T, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.points) == 0 {
+		return node, false
+	}
+	h := ringHash(key, -1)
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].node, true
+}
+
+// Nodes returns the members of the ring as a new thread-safe set.
+func (r *Ring[T]) Nodes() Set[T] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return NewSet(r.nodes.ToSlice()...)
+}
+
+// ringHash hashes b, suffixed by replica unless it is negative, with FNV-1a
+// followed by the splitmix64 finalizer which spreads similar inputs.
+func ringHash(b []byte, replica int) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	if replica >= 0 {
+		var buf [binary.MaxVarintLen64]byte
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(replica))])
+	}
+
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
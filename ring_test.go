@@ -0,0 +1,110 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ringOwners(r *Ring[string], keys int) map[string]string {
+	owners := make(map[string]string, keys)
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		owners[key], _ = r.Owner([]byte(key))
+	}
+	return owners
+}
+
+func Test_Ring(t *testing.T) {
+	r := NewRing[string](NewSet("a", "b", "c", "d"), 0)
+
+	owners := ringOwners(r, 10000)
+	counts := make(map[string]int)
+	for _, node := range owners {
+		counts[node]++
+	}
+	for _, node := range []string{"a", "b", "c", "d"} {
+		if math.Abs(float64(counts[node])-2500) > 750 {
+			t.Errorf("expected keys to be spread evenly, got %v", counts)
+			break
+		}
+	}
+
+	// removing a node only moves its keys
+	if !r.Remove("b") || r.Remove("b") {
+		t.Error("Remove should report whether the node was present")
+	}
+	for key, node := range ringOwners(r, 10000) {
+		if node == "b" {
+			t.Fatalf("%s is still owned by the removed node", key)
+		}
+		if owners[key] != "b" && owners[key] != node {
+			t.Fatalf("%s moved from %s to %s", key, owners[key], node)
+		}
+	}
+
+	// adding it back restores the original owners
+	if !r.Add("b") || r.Add("b") {
+		t.Error("Add should report whether the node was added")
+	}
+	for key, node := range ringOwners(r, 10000) {
+		if owners[key] != node {
+			t.Fatalf("%s moved from %s to %s", key, owners[key], node)
+		}
+	}
+
+	if !r.Nodes().Equal(NewSet("a", "b", "c", "d")) {
+		t.Errorf("unexpected nodes: %v", r.Nodes())
+	}
+}
+
+func Test_RingConsistent(t *testing.T) {
+	// rings built from the same nodes agree, whatever the insertion order
+	a := NewRing[string](NewSet("x", "y", "z"), 16)
+	b := NewRing[string](NewThreadUnsafeSet[string](), 16)
+	b.Add("z")
+	b.Add("x")
+	b.Add("y")
+
+	for key, node := range ringOwners(a, 1000) {
+		if owner, _ := b.Owner([]byte(key)); owner != node {
+			t.Fatalf("the rings disagree on %s: %s and %s", key, node, owner)
+		}
+	}
+}
+
+func Test_RingEmpty(t *testing.T) {
+	r := NewRing[int](NewSet[int](), 8)
+	if _, ok := r.Owner([]byte("key")); ok {
+		t.Error("an empty ring has no owner")
+	}
+	r.Add(1)
+	if node, ok := r.Owner([]byte("key")); !ok || node != 1 {
+		t.Errorf("expected the only node to own every key, got %v", node)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+)
+
+// StratifiedSample returns a random sample of s holding up to perKey
+// elements for every distinct value of key, so that a sample used as a test
+// dataset keeps the rare categories that uniform sampling would drown.
+// Categories with at most perKey elements are kept whole.
+//
+// The sample is drawn from r, or from the default source of math/rand if r
+// is nil. For a given r seed and set, the sample is always the same
+// regardless of the iteration order of the set. The returned set uses the
+// same implementation as s.
+func StratifiedSample[T comparable, K comparable](s Set[T], key func(T) K, perKey int, r *rand.Rand) Set[T] {
+	intn := rand.Intn
+	if r != nil {
+		intn = r.Intn
+	}
+	if perKey <= 0 {
+		return newSetLike(s, 0)
+	}
+
+	// visit the elements in their canonical order, so that the sample only
+	// depends on the random source
+	elems := s.ToSlice()
+	encoded := make([][]byte, len(elems))
+	for i := range elems {
+		encoded[i] = canonicalElement(elems[i])
+	}
+	sort.Sort(byEncoding[T]{elems, encoded})
+
+	// reservoir sampling, independently for every key
+	reservoirs := make(map[K][]T)
+	seen := make(map[K]int)
+	for _, elem := range elems {
+		k := key(elem)
+		seen[k]++
+		if reservoir := reservoirs[k]; len(reservoir) < perKey {
+			reservoirs[k] = append(reservoir, elem)
+		} else if j := intn(seen[k]); j < perKey {
+			reservoir[j] = elem
+		}
+	}
+
+	sample := newSetLike(s, len(reservoirs)*perKey)
+	for _, reservoir := range reservoirs {
+		sample.Append(reservoir...)
+	}
+	return sample
+}
+
+// byEncoding sorts elements by their encoding.
+type byEncoding[T comparable] struct {
+	elems   []T
+	encoded [][]byte
+}
+
+func (b byEncoding[T]) Len() int { return len(b.elems) }
+
+func (b byEncoding[T]) Less(i, j int) bool { return bytes.Compare(b.encoded[i], b.encoded[j]) < 0 }
+
+func (b byEncoding[T]) Swap(i, j int) {
+	b.elems[i], b.elems[j] = b.elems[j], b.elems[i]
+	b.encoded[i], b.encoded[j] = b.encoded[j], b.encoded[i]
+}
@@ -0,0 +1,89 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_StratifiedSample(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		// 1000 common elements and 3 rare ones
+		s := ctor()
+		for i := 0; i < 1000; i++ {
+			s.Add(i * 2)
+		}
+		s.Append(1, 3, 5)
+		parity := func(v int) int { return v % 2 }
+
+		sample := StratifiedSample(s, parity, 10, rand.New(rand.NewSource(1)))
+		if sample.Cardinality() != 13 {
+			t.Errorf("expected 10 even and 3 odd elements, got %v", sample)
+		}
+		if !sample.Contains(1, 3, 5) {
+			t.Errorf("small categories should be kept whole, got %v", sample)
+		}
+		if !sample.IsSubset(s) {
+			t.Errorf("the sample should be a subset of the set, got %v", sample)
+		}
+
+		// the sample only depends on the seed
+		again := StratifiedSample(s.Clone(), parity, 10, rand.New(rand.NewSource(1)))
+		if !again.Equal(sample) {
+			t.Errorf("the same seed should produce the same sample, got %v and %v", sample, again)
+		}
+
+		if empty := StratifiedSample(s, parity, 0, nil); !empty.IsEmpty() {
+			t.Errorf("expected an empty sample, got %v", empty)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_StratifiedSampleUniform(t *testing.T) {
+	s := NewSet(0, 1, 2, 3)
+	counts := make(map[int]int)
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 4000; i++ {
+		StratifiedSample(s, func(int) bool { return true }, 1, r).Each(func(v int) bool {
+			counts[v]++
+			return false
+		})
+	}
+	for v := 0; v < 4; v++ {
+		if counts[v] < 800 || counts[v] > 1200 {
+			t.Errorf("expected every element to be drawn about 1000 times, got %v", counts)
+			break
+		}
+	}
+}
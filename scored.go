@@ -0,0 +1,277 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+)
+
+// ScoredSet is a thread-safe set that associates a score with every
+// member. PopMin and PopMax atomically remove the extreme-scored member,
+// which makes ScoredSet a deduplicating priority queue, for instance for
+// schedulers that must not enqueue the same job twice. A NaN score is
+// considered lower than any other score, as in SortedSet.
+type ScoredSet[T comparable] struct {
+	mu      sync.Mutex
+	members map[T]*scoredEntry[T]
+	min     scoredHeap[T]
+	max     scoredHeap[T]
+}
+
+type scoredEntry[T comparable] struct {
+	value T
+	score float64
+	// positions of the entry in the min and max heaps
+	minIndex int
+	maxIndex int
+}
+
+// NewScoredSet creates and returns a new empty ScoredSet.
+func NewScoredSet[T comparable]() *ScoredSet[T] {
+	return &ScoredSet[T]{
+		members: make(map[T]*scoredEntry[T]),
+		min: scoredHeap[T]{
+			less:  scoreLess,
+			index: func(e *scoredEntry[T]) *int { return &e.minIndex },
+		},
+		max: scoredHeap[T]{
+			less:  func(a, b float64) bool { return scoreLess(b, a) },
+			index: func(e *scoredEntry[T]) *int { return &e.maxIndex },
+		},
+	}
+}
+
+// Add adds an element with the given score, or updates the score of an
+// element that is already present. Returns whether the element was added.
+func (s *ScoredSet[T]) Add(v T, score float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, found := s.members[v]; found {
+		e.score = score
+		s.min.fix(e.minIndex)
+		s.max.fix(e.maxIndex)
+		return false
+	}
+
+	e := &scoredEntry[T]{value: v, score: score}
+	s.members[v] = e
+	s.min.push(e)
+	s.max.push(e)
+	return true
+}
+
+// Score returns the score of the given element and whether it is in the set.
+func (s *ScoredSet[T]) Score(v T) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.members[v]
+	if !found {
+		return 0, false
+	}
+	return e.score, true
+}
+
+// Contains returns whether the given element is in the set.
+func (s *ScoredSet[T]) Contains(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, found := s.members[v]
+	return found
+}
+
+// Remove removes a single element from the set. Returns whether the element
+// was present.
+func (s *ScoredSet[T]) Remove(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.members[v]
+	if found {
+		s.remove(e)
+	}
+	return found
+}
+
+// remove must be called with the lock held.
+func (s *ScoredSet[T]) remove(e *scoredEntry[T]) {
+	s.min.remove(e.minIndex)
+	s.max.remove(e.maxIndex)
+	delete(s.members, e.value)
+}
+
+// Cardinality returns the number of elements in the set.
+func (s *ScoredSet[T]) Cardinality() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.members)
+}
+
+// Min returns the element with the lowest score without removing it. The
+// boolean is false if the set is empty.
+func (s *ScoredSet[T]) Min() (T, float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.min.peek()
+}
+
+// Max returns the element with the highest score without removing it. The
+// boolean is false if the set is empty.
+func (s *ScoredSet[T]) Max() (T, float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.max.peek()
+}
+
+// PopMin removes and returns the element with the lowest score. The boolean
+// is false if the set is empty. Among elements with the same score, which
+// one is popped is unspecified.
+func (s *ScoredSet[T]) PopMin() (T, float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, score, ok := s.min.peek()
+	if ok {
+		s.remove(s.members[v])
+	}
+	return v, score, ok
+}
+
+// PopMax removes and returns the element with the highest score. The
+// boolean is false if the set is empty. Among elements with the same score,
+// which one is popped is unspecified.
+func (s *ScoredSet[T]) PopMax() (T, float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, score, ok := s.max.peek()
+	if ok {
+		s.remove(s.members[v])
+	}
+	return v, score, ok
+}
+
+// ToSet returns the members of the set, without their scores, as a new
+// thread-safe Set.
+func (s *ScoredSet[T]) ToSet() Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	set := newThreadSafeSetWithSize[T](len(s.members))
+	for v := range s.members {
+		set.uss.add(v)
+	}
+	return set
+}
+
+// scoreLess orders scores with NaN before any other score, so that the heaps
+// stay consistent whatever the scores.
+func scoreLess(a, b float64) bool {
+	return a < b || (a != a && b == b)
+}
+
+// scoredHeap is a binary heap of entries ordered by less on their scores,
+// which keeps every entry informed of its position so that arbitrary
+// entries can be fixed or removed in O(log n).
+type scoredHeap[T comparable] struct {
+	entries []*scoredEntry[T]
+	less    func(a, b float64) bool
+	index   func(e *scoredEntry[T]) *int
+}
+
+func (h *scoredHeap[T]) peek() (v T, score float64, ok bool) {
+	if len(h.entries) == 0 {
+		return v, 0, false
+	}
+	return h.entries[0].value, h.entries[0].score, true
+}
+
+func (h *scoredHeap[T]) push(e *scoredEntry[T]) {
+	h.entries = append(h.entries, e)
+	*h.index(e) = len(h.entries) - 1
+	h.up(len(h.entries) - 1)
+}
+
+func (h *scoredHeap[T]) remove(i int) {
+	last := len(h.entries) - 1
+	if i != last {
+		h.swap(i, last)
+	}
+	h.entries[last] = nil
+	h.entries = h.entries[:last]
+	if i != last {
+		h.fix(i)
+	}
+}
+
+func (h *scoredHeap[T]) fix(i int) {
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+func (h *scoredHeap[T]) swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	*h.index(h.entries[i]) = i
+	*h.index(h.entries[j]) = j
+}
+
+func (h *scoredHeap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.entries[i].score, h.entries[parent].score) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down reports whether the entry moved.
+func (h *scoredHeap[T]) down(i int) bool {
+	start := i
+	n := len(h.entries)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && h.less(h.entries[right].score, h.entries[child].score) {
+			child = right
+		}
+		if !h.less(h.entries[child].score, h.entries[i].score) {
+			break
+		}
+		h.swap(i, child)
+		i = child
+	}
+	return i > start
+}
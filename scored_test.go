@@ -0,0 +1,177 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func Test_ScoredSet(t *testing.T) {
+	s := NewScoredSet[string]()
+
+	if !s.Add("backup", 5) || !s.Add("deploy", 1) || !s.Add("report", 3) {
+		t.Error("adding new elements should report true")
+	}
+	if s.Add("backup", 0.5) {
+		t.Error("updating the score of an element should report false")
+	}
+	if score, ok := s.Score("backup"); !ok || score != 0.5 {
+		t.Errorf("expected the updated score 0.5, got %v", score)
+	}
+	if s.Cardinality() != 3 || !s.Contains("report") {
+		t.Error("unexpected set contents")
+	}
+
+	if v, score, ok := s.Min(); !ok || v != "backup" || score != 0.5 {
+		t.Errorf("expected backup as minimum, got %v (%v)", v, score)
+	}
+	if v, _, ok := s.PopMax(); !ok || v != "report" {
+		t.Errorf("expected report to be popped as maximum, got %v", v)
+	}
+	if v, _, ok := s.PopMin(); !ok || v != "backup" {
+		t.Errorf("expected backup to be popped as minimum, got %v", v)
+	}
+	if !s.ToSet().Equal(NewSet("deploy")) {
+		t.Errorf("expected only deploy to remain, got %v", s.ToSet())
+	}
+
+	if !s.Remove("deploy") || s.Remove("deploy") {
+		t.Error("Remove should report whether the element was present")
+	}
+	if _, _, ok := s.PopMin(); ok {
+		t.Error("popping an empty set should report false")
+	}
+	if _, _, ok := s.Max(); ok {
+		t.Error("the maximum of an empty set should report false")
+	}
+}
+
+func Test_ScoredSetOrder(t *testing.T) {
+	s := NewScoredSet[int]()
+	scores := map[int]float64{}
+	for i := 0; i < 200; i++ {
+		v := rand.Intn(100)
+		scores[v] = rand.Float64()
+		s.Add(v, scores[v])
+		if i%7 == 0 {
+			s.Remove(v)
+			delete(scores, v)
+		}
+	}
+
+	expected := make([]float64, 0, len(scores))
+	for _, score := range scores {
+		expected = append(expected, score)
+	}
+	sort.Float64s(expected)
+
+	for lo, hi := 0, len(expected)-1; lo <= hi; {
+		if _, score, _ := s.PopMin(); score != expected[lo] {
+			t.Fatalf("expected min score %v, got %v", expected[lo], score)
+		}
+		lo++
+		if lo > hi {
+			break
+		}
+		if _, score, _ := s.PopMax(); score != expected[hi] {
+			t.Fatalf("expected max score %v, got %v", expected[hi], score)
+		}
+		hi--
+	}
+	if s.Cardinality() != 0 {
+		t.Errorf("expected the set to be drained, got %d elements", s.Cardinality())
+	}
+}
+
+func Test_ScoredSetNaN(t *testing.T) {
+	s := NewScoredSet[int]()
+	for i := 0; i < 50; i++ {
+		score := float64(i % 10)
+		if i%3 == 0 {
+			score = math.NaN()
+		}
+		s.Add(i, score)
+	}
+	s.Add(1, math.NaN())
+	s.Add(0, 5)
+
+	nans := 0
+	prev := math.Inf(1)
+	for s.Cardinality() > 0 {
+		_, score, _ := s.PopMax()
+		if math.IsNaN(score) {
+			nans++
+			continue
+		}
+		if nans > 0 || score > prev {
+			t.Fatalf("expected scores in descending order with NaNs last, got %v after %v", score, prev)
+		}
+		prev = score
+	}
+	if nans != 17 {
+		t.Errorf("expected 17 NaN scores, got %d", nans)
+	}
+
+	s.Add(1, 1)
+	s.Add(2, math.NaN())
+	if v, _, _ := s.PopMin(); v != 2 {
+		t.Errorf("expected the NaN score to be the lowest, got %v", v)
+	}
+}
+
+func Test_ScoredSetConcurrent(t *testing.T) {
+	s := NewScoredSet[int]()
+	for i := 0; i < N; i++ {
+		s.Add(i, float64(i))
+	}
+
+	popped := NewSet[int]()
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for w := 0; w < 4; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				v, _, ok := s.PopMin()
+				if !ok {
+					return
+				}
+				if !popped.Add(v) {
+					t.Errorf("%d was popped twice", v)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if popped.Cardinality() != N {
+		t.Errorf("expected %d elements to be popped, got %d", N, popped.Cardinality())
+	}
+}
@@ -0,0 +1,62 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"iter"
+)
+
+// IntersectSeq returns an iterator over the elements of seq that are in s,
+// filtering the stream lazily by membership. This is the semi-join used
+// when streaming rows against an in-memory key set: every matching value
+// of seq is yielded, duplicates included, in the order of seq.
+//
+// Membership is checked for every value when it is produced, so changes
+// made to s during the iteration are taken into account.
+func IntersectSeq[T comparable](s Set[T], seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if s.ContainsOne(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ContainsAnyOf returns whether at least one of the values produced by seq
+// is in s, stopping the iteration at the first match. Unlike ContainsAny it
+// doesn't require collecting the values into a slice first.
+func ContainsAnyOf[T comparable](s Set[T], seq iter.Seq[T]) bool {
+	for v := range seq {
+		if s.ContainsOne(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_IntersectSeq(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		keys := ctor(2, 3, 5, 7)
+		rows := slices.Values([]int{1, 2, 3, 4, 5, 5, 6, 7, 8})
+
+		actual := slices.Collect(IntersectSeq(keys, rows))
+		expected := []int{2, 3, 5, 5, 7}
+		if !slices.Equal(actual, expected) {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+
+		var first []int
+		for v := range IntersectSeq(keys, rows) {
+			if len(first) == 2 {
+				break
+			}
+			first = append(first, v)
+		}
+		if !slices.Equal(first, []int{2, 3}) {
+			t.Errorf("iteration should stop on the way, got %v", first)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_ContainsAnyOf(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		s := ctor("debug", "trace")
+
+		if !ContainsAnyOf(s, slices.Values([]string{"info", "trace"})) {
+			t.Error("expected a match")
+		}
+		if ContainsAnyOf(s, slices.Values([]string{"info", "warn"})) {
+			t.Error("expected no match")
+		}
+		if ContainsAnyOf(s, slices.Values([]string(nil))) {
+			t.Error("an empty sequence should not match")
+		}
+
+		var produced int
+		seq := func(yield func(string) bool) {
+			for _, v := range []string{"debug", "info", "warn"} {
+				produced++
+				if !yield(v) {
+					return
+				}
+			}
+		}
+		if !ContainsAnyOf(s, seq) || produced != 1 {
+			t.Errorf("iteration should stop at the first match, produced %d values", produced)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_ContainsAnyOfAllocs(t *testing.T) {
+	s := NewSet("debug", "trace")
+	values := []string{"info", "warn", "trace"}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ContainsAnyOf(s, slices.Values(values))
+	})
+	if allocs != 0 {
+		t.Errorf("ContainsAnyOf should not allocate, got %v allocations", allocs)
+	}
+}
@@ -37,43 +37,13 @@ package mapset
 
 import "go.mongodb.org/mongo-driver/bson/bsontype"
 
-// Set is the primary interface provided by the mapset package.  It
-// represents an unordered set of data and a large number of
-// operations that can be applied to that set.
-type Set[T comparable] interface {
-	// Add adds an element to the set. Returns whether
-	// the item was added.
-	Add(val T) bool
-
-	// Append multiple elements to the set. Returns
-	// the number of elements added.
-	Append(val ...T) int
-
-	// AppendFrom elements from another set into this set. (shorthand of s.Append(other.ToSlice()...))
-	// Returns the number of elements added.
-	AppendFrom(other Set[T]) int
-
-	// Cardinality returns the number of elements in the set.
-	Cardinality() int
-
-	// Clear removes all elements from the set, leaving
-	// the empty set.
-	Clear()
-
-	// Clone returns a clone of the set using the same
-	// implementation, duplicating all keys.
-	Clone() Set[T]
-
-	// Contains returns whether the given items
-	// are all in the set.
-	Contains(val ...T) bool
-
-	// ContainsOne returns whether the given item
-	// is in the set.
-	//
-	// Contains may cause the argument to escape to the heap.
-	// See: https://github.com/deckarep/golang-set/issues/118
-	ContainsOne(val T) bool
+// ReadOnlySet is the part of Set that queries a set without modifying it,
+// so that functions which only read a set can accept a ReadOnlySet and
+// make clear that they don't take ownership of it. Every Set is a
+// ReadOnlySet; views that can't be compared to other sets, such as the
+// ones returned by FilterView, only implement SetView.
+type ReadOnlySet[T comparable] interface {
+	SetView[T]
 
 	// ContainsAny returns whether at least one of the
 	// given items are in the set.
@@ -83,17 +53,6 @@ type Set[T comparable] interface {
 	// given element are in the set.
 	ContainsAnyElement(other Set[T]) bool
 
-	// Difference returns the difference between this set
-	// and other. The returned set will contain
-	// all elements of this set that are not also
-	// elements of other.
-	//
-	// Note that the argument to Difference
-	// must be of the same type as the receiver
-	// of the method. Otherwise, Difference will
-	// panic.
-	Difference(other Set[T]) Set[T]
-
 	// Equal determines if two sets are equal to each
 	// other. If they have the same cardinality
 	// and contain the same elements, they are
@@ -105,18 +64,6 @@ type Set[T comparable] interface {
 	// method. Otherwise, Equal will panic.
 	Equal(other Set[T]) bool
 
-	// Intersect returns a new set containing only the elements
-	// that exist only in both sets.
-	//
-	// Note that the argument to Intersect
-	// must be of the same type as the receiver
-	// of the method. Otherwise, Intersect will
-	// panic.
-	Intersect(other Set[T]) Set[T]
-
-	// IsEmpty determines if there are elements in the set.
-	IsEmpty() bool
-
 	// IsProperSubset determines if every element in this set is in
 	// the other set but the two sets are not equal.
 	//
@@ -153,33 +100,111 @@ type Set[T comparable] interface {
 	// of the method. Otherwise, IsSuperset will
 	// panic.
 	IsSuperset(other Set[T]) bool
+}
 
-	// Each iterates over elements and executes the passed func against each element.
-	// If passed func returns true, stop iteration at the time.
-	Each(func(T) bool)
+// MutableSet is the part of Set that modifies a set in place.
+type MutableSet[T comparable] interface {
+	// Add adds an element to the set. Returns whether
+	// the item was added.
+	Add(val T) bool
+
+	// Append multiple elements to the set. Returns
+	// the number of elements added.
+	Append(val ...T) int
+
+	// AppendFrom elements from another set into this set. (shorthand of s.Append(other.ToSlice()...))
+	// Returns the number of elements added.
+	// AppendFrom is the in-place version of Union: the elements are added
+	// directly to the receiver, without allocating a new set or a slice
+	// of the elements of other.
+	AppendFrom(other Set[T]) int
+
+	// Clear removes all elements from the set, leaving
+	// the empty set.
+	Clear()
+
+	// Remove removes a single element from the set.
+	Remove(i T)
+
+	// RemoveAll removes multiple elements from the set.
+	RemoveAll(i ...T)
+
+	// Pop removes and returns an arbitrary item from the set.
+	// The boolean is false if the set was empty. Checking and removing
+	// happen atomically, so unlike calling IsEmpty before Pop it doesn't
+	// race with other consumers.
+	Pop() (T, bool)
+
+	// PopN removes and returns up to n arbitrary items from the set.
+	// It returns a slice of the removed items and the actual number of items removed.
+	// If the set is empty or n is less than or equal to 0, it returns an empty slice and 0.
+	// If n is greater than the set's size, all items are removed and returned.
+	// Thread-safe sets remove the items under a single lock.
+	PopN(n int) ([]T, int)
+
+	// UnmarshalJSON will unmarshal a JSON-based byte slice into a full Set datastructure.
+	// For this to work, set subtypes must implement the Marshal/Unmarshal interface.
+	UnmarshalJSON(b []byte) error
+
+	// UnmarshalBSONValue will unmarshal a BSON-based byte slice into a full Set datastructure.
+	// For this to work, set subtypes must implement the Marshal/Unmarshal interface.
+	UnmarshalBSONValue(bt bsontype.Type, b []byte) error
+}
+
+// Set is the primary interface provided by the mapset package.  It
+// represents an unordered set of data and a large number of
+// operations that can be applied to that set.
+//
+// The sets created by this package also implement encoding.BinaryMarshaler,
+// encoding.BinaryUnmarshaler, encoding.TextMarshaler, encoding.TextUnmarshaler
+// and the yaml.Marshaler and yaml.Unmarshaler interfaces. gob encodes values
+// of type Set[T], such as struct fields, once their implementations are
+// registered with gob.Register, for instance gob.Register(NewSet[string]()).
+//
+// Operations that are not part of the interface, such as EachErr or
+// Partition, are package-level functions that use the method of the
+// implementation when there is one.
+type Set[T comparable] interface {
+	ReadOnlySet[T]
+	MutableSet[T]
+
+	// Clone returns a clone of the set using the same
+	// implementation, duplicating all keys.
+	Clone() Set[T]
+
+	// Difference returns the difference between this set
+	// and other. The returned set will contain
+	// all elements of this set that are not also
+	// elements of other.
+	//
+	// Note that the argument to Difference
+	// must be of the same type as the receiver
+	// of the method. Otherwise, Difference will
+	// panic.
+	Difference(other Set[T]) Set[T]
+
+	// Intersect returns a new set containing only the elements
+	// that exist only in both sets.
+	//
+	// Note that the argument to Intersect
+	// must be of the same type as the receiver
+	// of the method. Otherwise, Intersect will
+	// panic.
+	Intersect(other Set[T]) Set[T]
 
 	// Filter iterates over elements and executes the passed func against each element.
 	// If passed func returns true, the element will be added to the returned set.
 	Filter(func(T) bool) Set[T]
 
 	// Iter returns a channel of elements that you can
-	// range over.
+	// range over. The channel must be drained, or the goroutine
+	// feeding it leaks; use Iterator to stop early.
 	Iter() <-chan T
 
 	// Iterator returns an Iterator object that you can
 	// use to range over the set.
 	Iterator() *Iterator[T]
 
-	// Remove removes a single element from the set.
-	Remove(i T)
-
-	// RemoveAll removes multiple elements from the set.
-	RemoveAll(i ...T)
-
-	// String provides a convenient string representation
-	// of the current state of the set.
-	String() string
-
 	// SymmetricDifference returns a new set with all elements which are
 	// in either this set or the other set but not in both.
 	//
@@ -196,31 +221,11 @@ type Set[T comparable] interface {
 	// Otherwise, Union will panic.
 	Union(other Set[T]) Set[T]
 
-	// Pop removes and returns an arbitrary item from the set.
-	Pop() (T, bool)
-
-	// PopN removes and returns up to n arbitrary items from the set.
-	// It returns a slice of the removed items and the actual number of items removed.
-	// If the set is empty or n is less than or equal to 0s, it returns an empty slice and 0.
-	// If n is greater than the set's size, all items are
-	PopN(n int) ([]T, int)
-
-	// ToSlice returns the members of the set as a slice.
-	ToSlice() []T
-
 	// MarshalJSON will marshal the set into a JSON-based representation.
 	MarshalJSON() ([]byte, error)
 
-	// UnmarshalJSON will unmarshal a JSON-based byte slice into a full Set datastructure.
-	// For this to work, set subtypes must implement the Marshal/Unmarshal interface.
-	UnmarshalJSON(b []byte) error
-
 	// MarshalBSONValue will marshal the set into a BSON-based representation.
 	MarshalBSONValue() (bsontype.Type, []byte, error)
-
-	// UnmarshalBSONValue will unmarshal a BSON-based byte slice into a full Set datastructure.
-	// For this to work, set subtypes must implement the Marshal/Unmarshal interface.
-	UnmarshalBSONValue(bt bsontype.Type, b []byte) error
 }
 
 // NewSet creates and returns a new set with the given elements.
@@ -232,7 +237,10 @@ func NewSet[T comparable](vs ...T) Set[T] {
 }
 
 // NewSetWithSize creates and returns a reference to an empty set with a specified
-// capacity. Operations on the resulting set are thread-safe.
+// capacity, which avoids growing the set while it is filled with that many
+// elements. The sets returned by set operations such as Union, Clone or
+// Difference are presized in the same way from the cardinalities of their
+// operands. Operations on the resulting set are thread-safe.
 func NewSetWithSize[T comparable](cardinality int) Set[T] {
 	return newThreadSafeSetWithSize[T](cardinality)
 }
@@ -251,6 +259,62 @@ func NewThreadUnsafeSetWithSize[T comparable](cardinality int) Set[T] {
 	return newThreadUnsafeSetWithSize[T](cardinality)
 }
 
+// wrapper is implemented by sets that decorate another set, such as the
+// ones returned by NewSetWithOptions.
+type wrapper[T comparable] interface {
+	unwrap() Set[T]
+}
+
+// unwrapSet strips all decorators from s and returns the underlying set,
+// so that decorated sets can be used as arguments of set operations.
+func unwrapSet[T comparable](s Set[T]) Set[T] {
+	for {
+		w, ok := s.(wrapper[T])
+		if !ok {
+			return s
+		}
+		s = w.unwrap()
+	}
+}
+
+// implementation returns the first set of the decorator chain of s, starting
+// with s itself, that implements I. It lets package-level functions use the
+// optional methods of the underlying implementation through decorators that
+// don't override them.
+func implementation[I any, T comparable](s Set[T]) (I, bool) {
+	for {
+		if i, ok := any(s).(I); ok {
+			return i, true
+		}
+		w, ok := s.(wrapper[T])
+		if !ok {
+			var zero I
+			return zero, false
+		}
+		s = w.unwrap()
+	}
+}
+
+// newSetLike creates and returns a reference to an empty set with a specified
+// capacity that uses the same implementation as s. It lets package-level
+// helpers return results that match the thread-safety of their input.
+func newSetLike[T comparable](s Set[T], cardinality int) Set[T] {
+	switch u := unwrapSet(s).(type) {
+	case *threadUnsafeSet[T]:
+		return newThreadUnsafeSetWithSize[T](cardinality)
+	case likeMaker[T]:
+		return u.newLike(cardinality)
+	}
+	return newThreadSafeSetWithSize[T](cardinality)
+}
+
+// likeMaker is implemented by the set implementations that are not
+// available in every Go version, such as sorted sets, so that newSetLike
+// can create sets like them.
+type likeMaker[T comparable] interface {
+	newLike(cardinality int) Set[T]
+}
+
 // NewSetFromMapKeys creates and returns a new set with the given keys of the map.
 // Operations on the resulting set are thread-safe.
 func NewSetFromMapKeys[T comparable, V any](val map[T]V) Set[T] {
@@ -275,6 +339,46 @@ func NewThreadUnsafeSetFromMapKeys[T comparable, V any](val map[T]V) Set[T] {
 	return s
 }
 
+// WrapMap returns a set that uses m as its backing storage instead of copying
+// it, so that code holding a raw map[T]struct{} can use set operations on it
+// for free. Changes made through the set are visible in m and vice versa;
+// if m is nil, an empty map is allocated and m is left untouched.
+// Operations on the resulting set are not thread-safe, and m must not be
+// used concurrently with it.
+func WrapMap[T comparable](m map[T]struct{}) Set[T] {
+	if m == nil {
+		m = make(map[T]struct{})
+	}
+	s := threadUnsafeSet[T](m)
+	return &s
+}
+
+// NewSetFromFunc creates and returns a new set with the keys extracted by
+// key from every item, such as collecting all IDs from a slice of rows.
+// Operations on the resulting set are thread-safe.
+func NewSetFromFunc[T any, K comparable](items []T, key func(T) K) Set[K] {
+	s := newThreadSafeSetWithSize[K](len(items))
+
+	for i := range items {
+		s.uss.add(key(items[i]))
+	}
+
+	return s
+}
+
+// NewThreadUnsafeSetFromFunc creates and returns a new set with the keys
+// extracted by key from every item. Operations on the resulting set are
+// not thread-safe.
+func NewThreadUnsafeSetFromFunc[T any, K comparable](items []T, key func(T) K) Set[K] {
+	s := newThreadUnsafeSetWithSize[K](len(items))
+
+	for i := range items {
+		s.add(key(items[i]))
+	}
+
+	return s
+}
+
 // Elements returns an iterator that yields the elements of the set. Starting
 // with Go 1.23, users can use a for loop to iterate over it.
 func Elements[T comparable](s Set[T]) func(func(element T) bool) {
@@ -284,3 +388,234 @@ func Elements[T comparable](s Set[T]) func(func(element T) bool) {
 		})
 	}
 }
+
+// EachErr iterates over the elements of s and executes cb against each
+// element. It stops at the first non-nil error returned by cb and returns
+// it. The locks held by the sets of this package are released even if cb
+// panics.
+func EachErr[T comparable](s Set[T], cb func(T) error) error {
+	if e, ok := implementation[interface{ EachErr(func(T) error) error }](s); ok {
+		return e.EachErr(cb)
+	}
+
+	var err error
+	s.Each(func(v T) bool {
+		err = cb(v)
+		return err != nil
+	})
+	return err
+}
+
+// Any reports whether pred returns true for at least one element of s. It
+// stops at the first such element.
+func Any[T comparable](s Set[T], pred func(T) bool) bool {
+	found := false
+	s.Each(func(v T) bool {
+		found = pred(v)
+		return found
+	})
+	return found
+}
+
+// All reports whether pred returns true for every element of s, which is
+// the case for an empty set. It stops at the first element for which pred
+// returns false.
+func All[T comparable](s Set[T], pred func(T) bool) bool {
+	return !Any(s, func(v T) bool { return !pred(v) })
+}
+
+// None reports whether pred returns false for every element of s, which
+// is the case for an empty set. It stops at the first element for which
+// pred returns true.
+func None[T comparable](s Set[T], pred func(T) bool) bool {
+	return !Any(s, pred)
+}
+
+// Partition splits s in a single pass into the elements for which pred
+// returns true and the rest. Both returned sets use the same implementation
+// as s.
+func Partition[T comparable](s Set[T], pred func(T) bool) (matching, rest Set[T]) {
+	type partitioner interface {
+		Partition(pred func(T) bool) (Set[T], Set[T])
+	}
+	if p, ok := implementation[partitioner](s); ok {
+		return p.Partition(pred)
+	}
+
+	matching, rest = newSetLike(s, 0), newSetLike(s, 0)
+	s.Each(func(v T) bool {
+		if pred(v) {
+			matching.Add(v)
+		} else {
+			rest.Add(v)
+		}
+		return false
+	})
+	return matching, rest
+}
+
+// PartitionN splits s into n disjoint sets in a single pass, placing each
+// element into the set at index hash(elem) % n. The returned sets use the
+// same implementation as s and their union is equal to it. How balanced the
+// partitions are depends on the distribution of hash.
+// If n is less than or equal to 0, PartitionN returns nil.
+func PartitionN[T comparable](s Set[T], n int, hash func(T) uint64) []Set[T] {
+	type partitioner interface {
+		PartitionN(n int, hash func(T) uint64) []Set[T]
+	}
+	if p, ok := implementation[partitioner](s); ok {
+		return p.PartitionN(n, hash)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	parts := make([]Set[T], n)
+	for i := range parts {
+		parts[i] = newSetLike(s, 0)
+	}
+	s.Each(func(v T) bool {
+		parts[hash(v)%uint64(n)].Add(v)
+		return false
+	})
+	return parts
+}
+
+// Chunk splits s into n disjoint sets whose cardinalities differ by at most
+// one, for instance to distribute work over n workers. Unlike PartitionN,
+// which element lands in which chunk is unspecified. The returned sets use
+// the same implementation as s and their union is equal to it; some are
+// empty if s has fewer than n elements.
+// If n is less than or equal to 0, Chunk returns nil.
+func Chunk[T comparable](s Set[T], n int) []Set[T] {
+	if n <= 0 {
+		return nil
+	}
+
+	chunks := make([]Set[T], n)
+	size := s.Cardinality()/n + 1
+	for i := range chunks {
+		chunks[i] = newSetLike(s, size)
+	}
+	i := 0
+	s.Each(func(v T) bool {
+		chunks[i].Add(v)
+		i = (i + 1) % n
+		return false
+	})
+	return chunks
+}
+
+// RemovedWhich removes the given elements from s and returns the ones that
+// were actually present before removal. The sets of this package remove
+// them atomically; for other implementations, checking and removing each
+// element are separate operations.
+func RemovedWhich[T comparable](s Set[T], vs ...T) []T {
+	if r, ok := implementation[interface{ RemovedWhich(...T) []T }](s); ok {
+		return r.RemovedWhich(vs...)
+	}
+
+	removed := make([]T, 0, len(vs))
+	for _, v := range vs {
+		if s.ContainsOne(v) {
+			s.Remove(v)
+			removed = append(removed, v)
+		}
+	}
+	return removed
+}
+
+// RemoveIf removes the elements of s for which pred returns true and
+// returns how many were removed. The sets of this package evaluate pred
+// and remove the elements under a single acquisition of their lock, one
+// per shard for a ShardedSet, so pred must not use s. For other
+// implementations, the matching elements are collected first and then
+// removed with RemovedWhich.
+func RemoveIf[T comparable](s Set[T], pred func(T) bool) int {
+	return len(removeIf(s, pred))
+}
+
+// RetainAll removes from s the elements that are not in other, intersecting
+// s with other in place, and returns how many were removed. Unlike
+// replacing s with s.Intersect(other), it keeps the identity of s, which
+// other goroutines may hold. other is not modified and may be s.
+func RetainAll[T comparable](s, other Set[T]) int {
+	switch x := s.(type) {
+	case *threadSafeSet[T]:
+		if o, ok := unwrapSet(other).(*threadSafeSet[T]); ok {
+			return x.retainAll(o)
+		}
+	case *threadUnsafeSet[T]:
+		if o, ok := unwrapSet(other).(*threadUnsafeSet[T]); ok {
+			return x.retainAll(*o)
+		}
+	}
+
+	// other is copied so that none of its locks are held while s is locked,
+	// since other may be s or a set that queries s
+	keep := newThreadUnsafeSet[T]()
+	keep.Append(other.ToSlice()...)
+	return RemoveIf(s, func(v T) bool {
+		return !keep.contains(v)
+	})
+}
+
+// Snapshot returns a copy of s that later changes of s don't affect, so
+// that readers can go through a consistent view while writers keep
+// modifying s. For the thread-safe sets created by NewSet, it takes O(1):
+// the copy shares the elements of s until either set is modified, and the
+// first modification of each then copies them. Other sets are cloned.
+// Like Clone, the copy doesn't inherit the options or decorators of s.
+func Snapshot[T comparable](s Set[T]) Set[T] {
+	if c, ok := implementation[interface{ snapshot() Set[T] }](s); ok {
+		return c.snapshot()
+	}
+	return s.Clone()
+}
+
+// AppendTo appends the elements of s to dst and returns the extended slice,
+// like append. Unlike ToSlice, it only allocates when dst lacks capacity,
+// so that hot paths can reuse a buffer:
+//
+//	buf = AppendTo(buf[:0], s)
+func AppendTo[T comparable](dst []T, s Set[T]) []T {
+	switch u := unwrapSet(s).(type) {
+	case *threadSafeSet[T]:
+		// sets iterating in chunks release their lock between chunks in Each
+		if u.iterChunk == 0 {
+			u.RLock()
+			defer u.RUnlock()
+			return u.uss.appendTo(dst)
+		}
+	case *threadUnsafeSet[T]:
+		return u.appendTo(dst)
+	}
+	return appendEach(dst, s)
+}
+
+// appendEach appends the elements of s to dst with Each. It is split from
+// AppendTo because the closure moves dst to the heap.
+func appendEach[T comparable](dst []T, s Set[T]) []T {
+	s.Each(func(v T) bool {
+		dst = append(dst, v)
+		return false
+	})
+	return dst
+}
+
+// removeIf removes the elements of s for which pred returns true and
+// returns them.
+func removeIf[T comparable](s Set[T], pred func(T) bool) []T {
+	if r, ok := implementation[interface{ removeIf(func(T) bool) []T }](s); ok {
+		return r.removeIf(pred)
+	}
+
+	var matching []T
+	s.Each(func(v T) bool {
+		if pred(v) {
+			matching = append(matching, v)
+		}
+		return false
+	})
+	return RemovedWhich(s, matching...)
+}
@@ -4,6 +4,7 @@
 package mapset
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -36,3 +37,60 @@ func Test_Elements123(t *testing.T) {
 		t.Error("Iteration should stop on the way")
 	}
 }
+
+func Test_All123(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3)
+
+		seen := NewSet[int]()
+		for elem := range Elements(s) {
+			seen.Add(elem)
+		}
+		if !seen.Equal(NewSet(1, 2, 3)) {
+			t.Errorf("expected every element to be yielded, got %v", seen)
+		}
+
+		count := 0
+		for range Elements(s) {
+			count++
+			break
+		}
+		if count != 1 {
+			t.Error("Iteration should stop on the way")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+	t.Run("Sorted", func(t *testing.T) {
+		test(t, func(vals ...int) Set[int] { return NewSortedSet(vals...) })
+	})
+}
+
+func Test_SortedElements123(t *testing.T) {
+	s := NewSet(3, 1, 2)
+
+	var elems []int
+	for elem := range SortedElements(s) {
+		// the elements are copied, so the set can be modified
+		s.Remove(elem)
+		elems = append(elems, elem)
+	}
+	if !slices.Equal(elems, []int{1, 2, 3}) {
+		t.Errorf("expected the elements in ascending order, got %v", elems)
+	}
+	if !s.IsEmpty() {
+		t.Errorf("expected an empty set, got %v", s)
+	}
+
+	for elem := range SortedElements(NewSet(5, 4)) {
+		if elem != 4 {
+			t.Errorf("expected 4 first, got %d", elem)
+		}
+		break
+	}
+}
@@ -26,7 +26,12 @@ SOFTWARE.
 package mapset
 
 import (
+	"bytes"
+	"encoding"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func makeSetInt(ints []int) Set[int] {
@@ -188,6 +193,32 @@ func Test_UnsafeAppendFrom(t *testing.T) {
 	}
 }
 
+func Test_AppendFromInPlace(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(nrand(100)...)
+		b := ctor(nrand(100)...)
+		a.AppendFrom(b)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			a.AppendFrom(b)
+		})
+		if allocs > 0 {
+			t.Errorf("AppendFrom should not allocate once the elements are present, got %v allocations", allocs)
+		}
+
+		if num := a.AppendFrom(a); num != 0 {
+			t.Errorf("appending a set to itself should add nothing, got %d", num)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
 func Test_RemoveSet(t *testing.T) {
 	a := makeSetInt([]int{6, 3, 1})
 
@@ -270,6 +301,297 @@ func Test_RemoveAllUnsafeSet(t *testing.T) {
 	}
 }
 
+func Test_RemovedWhichSet(t *testing.T) {
+	a := makeSetInt([]int{6, 3, 1, 8, 9})
+
+	removed := RemovedWhich(a, 3, 1, 42, 3)
+
+	if len(removed) != 2 {
+		t.Errorf("RemovedWhich should report 2 removed items, got %v", removed)
+	}
+
+	if !NewSet(removed...).Equal(NewSet(3, 1)) {
+		t.Errorf("RemovedWhich should report only items (3,1), got %v", removed)
+	}
+
+	if !a.Contains(6, 8, 9) || a.Cardinality() != 3 {
+		t.Error("RemovedWhich should leave only items (6,8,9) in the set")
+	}
+
+	if removed = RemovedWhich(a); len(removed) != 0 {
+		t.Errorf("RemovedWhich with no arguments should report nothing, got %v", removed)
+	}
+}
+
+func Test_RemovedWhichUnsafeSet(t *testing.T) {
+	a := makeUnsafeSetInt([]int{6, 3, 1, 8, 9})
+
+	removed := RemovedWhich(a, 3, 1, 42, 3)
+
+	if len(removed) != 2 {
+		t.Errorf("RemovedWhich should report 2 removed items, got %v", removed)
+	}
+
+	if !NewThreadUnsafeSet(removed...).Equal(NewThreadUnsafeSet(3, 1)) {
+		t.Errorf("RemovedWhich should report only items (3,1), got %v", removed)
+	}
+
+	if !a.Contains(6, 8, 9) || a.Cardinality() != 3 {
+		t.Error("RemovedWhich should leave only items (6,8,9) in the set")
+	}
+}
+
+func Test_AnyAllNone(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		even := func(v int) bool { return v%2 == 0 }
+		positive := func(v int) bool { return v > 0 }
+
+		s := ctor(1, 2, 3)
+		if !Any(s, even) || Any(s, func(v int) bool { return v > 3 }) {
+			t.Error("Any should report whether an element matches")
+		}
+		if !All(s, positive) || All(s, even) {
+			t.Error("All should report whether every element matches")
+		}
+		if !None(s, func(v int) bool { return v > 3 }) || None(s, even) {
+			t.Error("None should report whether no element matches")
+		}
+
+		empty := ctor()
+		if Any(empty, positive) || !All(empty, positive) || !None(empty, positive) {
+			t.Error("unexpected result on the empty set")
+		}
+
+		calls := 0
+		All(ctor(makeRange(100)...), func(v int) bool {
+			calls++
+			return false
+		})
+		if calls != 1 {
+			t.Errorf("All should stop at the first mismatch, got %d calls", calls)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_RemoveIf(t *testing.T) {
+	even := func(v int) bool { return v%2 == 0 }
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3, 4, 5, 6)
+		if n := RemoveIf(s, even); n != 3 || s.Cardinality() != 3 || !s.Contains(1, 3, 5) {
+			t.Errorf("Expected 3 removed and {1, 3, 5}, got: %d and %v", n, s)
+		}
+		if n := RemoveIf(s, even); n != 0 || s.Cardinality() != 3 {
+			t.Errorf("Expected nothing removed, got: %d and %v", n, s)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+	t.Run("Foreign", func(t *testing.T) {
+		test(t, func(vals ...int) Set[int] { return foreignSet[int]{NewSet(vals...)} })
+	})
+	t.Run("History", func(t *testing.T) {
+		h := NewHistorySet[int](NewSet(1, 2, 3, 4), 0)
+		if n := RemoveIf[int](h, even); n != 2 {
+			t.Errorf("Expected 2 removed, got: %d", n)
+		}
+		if history := h.History(); len(history) != 1 || !NewSet(history[0].Elems...).Equal(NewSet(2, 4)) {
+			t.Errorf("the removals should be recorded, got: %v", history)
+		}
+	})
+}
+
+func Test_RetainAll(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3, 4)
+		shared := s
+		if n := RetainAll(s, ctor(2, 4, 6)); n != 2 || !shared.Equal(ctor(2, 4)) {
+			t.Errorf("Expected 2 removed and {2, 4}, got: %d and %v", n, shared)
+		}
+		if n := RetainAll(s, s); n != 0 || s.Cardinality() != 2 {
+			t.Errorf("retaining a set's own elements should remove nothing, got %d and %v", n, s)
+		}
+		if n := RetainAll[int](s, foreignSet[int]{ctor(4)}); n != 1 || !s.Equal(ctor(4)) {
+			t.Errorf("Expected 1 removed and {4}, got: %d and %v", n, s)
+		}
+		if n := RetainAll(s, ctor()); n != 1 || !s.IsEmpty() {
+			t.Errorf("Expected 1 removed and an empty set, got: %d and %v", n, s)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+	t.Run("History", func(t *testing.T) {
+		h := NewHistorySet[int](NewSet(1, 2, 3), 0)
+		if n := RetainAll[int](h, NewSet(2)); n != 2 || len(h.History()) != 1 {
+			t.Errorf("the removals should be recorded, got %d and %v", n, h.History())
+		}
+	})
+}
+
+func Test_SnapshotCopy(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3)
+		snap := Snapshot(s)
+		s.Add(4)
+		s.Remove(1)
+		if !snap.Equal(ctor(1, 2, 3)) || !s.Equal(ctor(2, 3, 4)) {
+			t.Errorf("Expected {1, 2, 3} and {2, 3, 4}, got: %v and %v", snap, s)
+		}
+
+		again := Snapshot(snap)
+		snap.Clear()
+		if !again.Equal(ctor(1, 2, 3)) || !snap.IsEmpty() || !s.Equal(ctor(2, 3, 4)) {
+			t.Errorf("modifying a snapshot should not affect the other sets, got: %v, %v and %v", again, snap, s)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_SnapshotCopyConcurrent(t *testing.T) {
+	s := NewSet(makeRange(N)...)
+	if allocs := testing.AllocsPerRun(10, func() { Snapshot(s) }); allocs > 1 {
+		t.Errorf("Expected the snapshot to be a single allocation, got: %v", allocs)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < N; i++ {
+			s.Remove(i)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		n := 0
+		Snapshot(s).Each(func(int) bool {
+			n++
+			return false
+		})
+		if n > N {
+			t.Errorf("Expected at most %d elements, got: %d", N, n)
+		}
+	}
+	<-done
+}
+
+func Test_AppendTo(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3)
+		buf := AppendTo([]int{0}, s)
+		if len(buf) != 4 || buf[0] != 0 || !ctor(buf[1:]...).Equal(s) {
+			t.Errorf("Expected 0 followed by the elements, got: %v", buf)
+		}
+		if allocs := testing.AllocsPerRun(10, func() { buf = AppendTo(buf[:0], s) }); allocs != 0 {
+			t.Errorf("Expected no allocations when reusing the buffer, got: %v", allocs)
+		}
+		if got := AppendTo[int](nil, foreignSet[int]{s}); len(got) != 3 {
+			t.Errorf("Expected 3 elements, got: %v", got)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_ReadOnlySet(t *testing.T) {
+	// splitting the interface must not change the methods of Set
+	if n := reflect.TypeOf((*Set[int])(nil)).Elem().NumMethod(); n != 34 {
+		t.Errorf("Expected Set to have 34 methods, got: %d", n)
+	}
+
+	var ro ReadOnlySet[int] = NewSet(1, 2)
+	if !ro.IsSubset(NewSet(1, 2, 3)) || !ro.ContainsAny(2, 5) || ro.Cardinality() != 2 {
+		t.Errorf("unexpected result of queries on %v", ro)
+	}
+	var m MutableSet[int] = NewThreadUnsafeSet[int]()
+	if !m.Add(1) || m.Append(1, 2) != 1 {
+		t.Error("unexpected result of mutations")
+	}
+}
+
+// foreignSet is an implementation of Set from another package, which only
+// has the methods of the interface.
+type foreignSet[T comparable] struct {
+	Set[T]
+}
+
+func Test_OptionalMethodsFallback(t *testing.T) {
+	s := foreignSet[int]{NewSet(1, 2, 3, 4)}
+
+	if caps := CapabilitiesOf[int](s); caps != 0 {
+		t.Errorf("Expected no capabilities, got: %v", caps)
+	}
+	if !bytes.Equal(CanonicalBytes[int](s), CanonicalBytes(NewSet(1, 2, 3, 4))) {
+		t.Error("CanonicalBytes should not depend on the implementation")
+	}
+	evens, odds := Partition[int](s, func(v int) bool { return v%2 == 0 })
+	if !evens.Equal(NewSet(2, 4)) || !odds.Equal(NewSet(1, 3)) {
+		t.Errorf("Expected {2, 4} and {1, 3}, got: %v and %v", evens, odds)
+	}
+	if parts := PartitionN[int](s, 2, func(v int) uint64 { return uint64(v) }); len(parts) != 2 || !parts[1].Equal(NewSet(1, 3)) {
+		t.Errorf("Expected {1, 3} in the second partition, got: %v", parts)
+	}
+	errStop := errors.New("stop")
+	if err := EachErr[int](s, func(int) error { return errStop }); err != errStop {
+		t.Errorf("Expected %v, got: %v", errStop, err)
+	}
+	if removed := RemovedWhich[int](s, 4, 5); len(removed) != 1 || removed[0] != 4 {
+		t.Errorf("Expected [4], got: %v", removed)
+	}
+	if err := ReconcileTo[int](s, NewSet(1, 5), nil, func(int) error { return nil }); err != nil || !s.Equal(NewSet(1)) {
+		t.Errorf("Expected {1}, got: %v (%v)", s, err)
+	}
+}
+
+func Test_OptionalMethodsDecorated(t *testing.T) {
+	h := NewHistorySet[int](NewSet(1, 2, 3), 0)
+	if removed := RemovedWhich[int](h, 1, 4); len(removed) != 1 || removed[0] != 1 {
+		t.Errorf("Expected [1], got: %v", removed)
+	}
+	if history := h.History(); len(history) != 1 || history[0].Op != OpRemove {
+		t.Errorf("the removal should be recorded, got: %v", history)
+	}
+
+	g := NewSetWithOptions(RejectZero[int]())
+	g.Append(1, 2)
+	data, err := g.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	decoded := NewSetWithOptions(RejectZero[int]())
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil || !decoded.Equal(g) {
+		t.Errorf("Expected %v, got: %v (%v)", g, decoded, err)
+	}
+	if caps := CapabilitiesOf(g); caps != CapThreadSafe {
+		t.Errorf("Expected %v, got: %v", CapThreadSafe, caps)
+	}
+}
+
 func Test_ContainsSet(t *testing.T) {
 	a := NewSet[int]()
 
@@ -1105,6 +1427,69 @@ func Test_Each(t *testing.T) {
 	}
 }
 
+func Test_EachErr(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3, 4)
+
+		sum := 0
+		if err := EachErr(a, func(elem int) error {
+			sum += elem
+			return nil
+		}); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if sum != 10 {
+			t.Errorf("Expected every element to be visited, got sum %d", sum)
+		}
+
+		errStop := errors.New("stop")
+		count := 0
+		err := EachErr(a, func(elem int) error {
+			count++
+			if count == 2 {
+				return errStop
+			}
+			return nil
+		})
+		if err != errStop {
+			t.Errorf("Expected the error of the callback, got: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Iteration should stop at the first error, got %d calls", count)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != "boom" {
+					t.Errorf("Expected the panic to propagate, got: %v", r)
+				}
+			}()
+			_ = EachErr(a, func(int) error {
+				panic("boom")
+			})
+		}()
+
+		// the set must still be writable after the panic
+		done := make(chan struct{})
+		go func() {
+			a.Add(5)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the lock was not released after the callback panicked")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
 func Test_Filter(t *testing.T) {
 	a := NewSet[string]()
 	a.Add("Z")
@@ -1185,6 +1570,44 @@ func Test_UnsafeFilter(t *testing.T) {
 	}
 }
 
+func Test_Partition(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3, 4, 5)
+
+		evens, odds := Partition(a, func(elem int) bool {
+			return elem%2 == 0
+		})
+		if !evens.Equal(ctor(2, 4)) {
+			t.Errorf("Expected %v, got %v", ctor(2, 4), evens)
+		}
+		if !odds.Equal(ctor(1, 3, 5)) {
+			t.Errorf("Expected %v, got %v", ctor(1, 3, 5), odds)
+		}
+
+		// Source set must be unmodified.
+		if a.Cardinality() != 5 {
+			t.Errorf("Expected source cardinality 5, got %d", a.Cardinality())
+		}
+
+		all, none := Partition(a, func(int) bool { return true })
+		if !all.Equal(a) || !none.IsEmpty() {
+			t.Errorf("Expected all elements to match, got %v and %v", all, none)
+		}
+
+		emptyMatching, emptyRest := Partition(ctor(), func(int) bool { return true })
+		if !emptyMatching.IsEmpty() || !emptyRest.IsEmpty() {
+			t.Error("Expected empty sets from empty source")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
 func Test_FilterVisitsAllElements(t *testing.T) {
 	a := NewSet(1, 2, 3, 4, 5)
 
@@ -1199,6 +1622,90 @@ func Test_FilterVisitsAllElements(t *testing.T) {
 	}
 }
 
+func Test_PartitionN(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+		hash := func(v int) uint64 { return uint64(v) }
+
+		parts := PartitionN(a, 3, hash)
+		if len(parts) != 3 {
+			t.Fatalf("PartitionN should return 3 sets, got %d", len(parts))
+		}
+
+		union := ctor()
+		total := 0
+		for i, p := range parts {
+			p.Each(func(v int) bool {
+				if v%3 != i {
+					t.Errorf("element %d should not be in partition %d", v, i)
+				}
+				return false
+			})
+			total += p.Cardinality()
+			union.AppendFrom(p)
+		}
+
+		if total != a.Cardinality() {
+			t.Errorf("partitions should be disjoint, total cardinality %d != %d", total, a.Cardinality())
+		}
+		if !union.Equal(a) {
+			t.Error("the union of all partitions should equal the original set")
+		}
+
+		if parts := PartitionN(a, 0, hash); parts != nil {
+			t.Errorf("PartitionN(0) should return nil, got %v", parts)
+		}
+
+		parts = PartitionN(ctor(), 2, hash)
+		if len(parts) != 2 || !parts[0].IsEmpty() || !parts[1].IsEmpty() {
+			t.Error("partitioning an empty set should yield empty sets")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_Chunk(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(makeRange(11)...)
+
+		chunks := Chunk(a, 3)
+		if len(chunks) != 3 {
+			t.Fatalf("Chunk should return 3 sets, got %d", len(chunks))
+		}
+		union := ctor()
+		for _, c := range chunks {
+			if n := c.Cardinality(); n < 3 || n > 4 {
+				t.Errorf("chunks should hold 3 or 4 elements, got %d", n)
+			}
+			union.AppendFrom(c)
+		}
+		if !union.Equal(a) {
+			t.Error("the union of all chunks should equal the original set")
+		}
+
+		if chunks := Chunk(a, 0); chunks != nil {
+			t.Errorf("Chunk(0) should return nil, got %v", chunks)
+		}
+		chunks = Chunk(ctor(1), 2)
+		if len(chunks) != 2 || chunks[0].Cardinality()+chunks[1].Cardinality() != 1 {
+			t.Error("chunking a set smaller than n should yield empty sets")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
 func Test_Iter(t *testing.T) {
 	a := NewSet[string]()
 
@@ -1603,6 +2110,38 @@ func Test_NewThreadUnsafeSetFromMapKey_Strings(t *testing.T) {
 	}
 }
 
+func Test_WrapMap(t *testing.T) {
+	m := map[int]struct{}{1: {}, 2: {}}
+	s := WrapMap(m)
+
+	if !s.Equal(NewThreadUnsafeSet(1, 2)) {
+		t.Errorf("the wrapped set should contain the keys of the map, got %v", s)
+	}
+
+	s.Add(3)
+	s.Remove(1)
+	if _, found := m[3]; !found {
+		t.Error("elements added to the set should be visible in the map")
+	}
+	if _, found := m[1]; found {
+		t.Error("elements removed from the set should be removed from the map")
+	}
+
+	m[4] = struct{}{}
+	if !s.Contains(4) {
+		t.Error("keys added to the map should be visible in the set")
+	}
+
+	if !s.Union(NewThreadUnsafeSet(5)).Equal(NewThreadUnsafeSet(2, 3, 4, 5)) {
+		t.Error("the wrapped set should support set algebra with thread-unsafe sets")
+	}
+
+	empty := WrapMap[int](nil)
+	if !empty.Add(1) {
+		t.Error("a set wrapping a nil map should be usable")
+	}
+}
+
 func Test_Elements(t *testing.T) {
 	a := NewSet[string]()
 
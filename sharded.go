@@ -0,0 +1,784 @@
+//go:build go1.24
+// +build go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// ShardedSet is a thread-safe Set that spreads its elements over several
+// shards by hash, each with its own lock, so that concurrent writers
+// touching different elements rarely contend. It suits very large sets
+// updated by many goroutines, such as sets bulk loaded in parallel.
+//
+// Operations on single elements lock one shard. Operations spanning the
+// whole set, such as Cardinality, Each or Union, lock one shard at a time,
+// so they may observe concurrent changes partially. The set operations
+// accept any Set as argument and return sharded sets.
+//
+// Reshard changes the number of shards while the set is in use.
+type ShardedSet[T comparable] struct {
+	seed  maphash.Seed
+	table atomic.Pointer[shardTable[T]]
+
+	// resizing is held for writing while Reshard migrates a shard, and for
+	// reading by the operations spanning the whole set, so that those see
+	// every element exactly once.
+	resizing sync.RWMutex
+	// next is the table an ongoing Reshard fills, guarded by resizing.
+	next *shardTable[T]
+	// reshard serializes calls to Reshard.
+	reshard sync.Mutex
+}
+
+type shardTable[T comparable] struct {
+	shards []*setShard[T]
+}
+
+type setShard[T comparable] struct {
+	sync.RWMutex
+	elems threadUnsafeSet[T]
+	// moved is the table holding the elements once Reshard migrated them.
+	moved *shardTable[T]
+	// keep shards on separate cache lines
+	_ [64]byte
+}
+
+var _ Set[int] = (*ShardedSet[int])(nil)
+
+// NewShardedSet creates and returns a new sharded set with the given number
+// of shards and elements. A non-positive number of shards picks four per
+// available CPU.
+func NewShardedSet[T comparable](shards int, vals ...T) *ShardedSet[T] {
+	s := newShardedSet[T](maphash.MakeSeed(), shards, len(vals))
+	s.Append(vals...)
+	return s
+}
+
+func newShardedSet[T comparable](seed maphash.Seed, shards, cardinality int) *ShardedSet[T] {
+	s := &ShardedSet[T]{}
+	s.init(seed, shards, cardinality)
+	return s
+}
+
+func (s *ShardedSet[T]) init(seed maphash.Seed, shards, cardinality int) {
+	s.seed = seed
+	s.table.Store(newShardTable[T](shards, cardinality))
+}
+
+func newShardTable[T comparable](shards, cardinality int) *shardTable[T] {
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+	t := &shardTable[T]{shards: make([]*setShard[T], shards)}
+	for i := range t.shards {
+		t.shards[i] = &setShard[T]{elems: make(threadUnsafeSet[T], cardinality/shards)}
+	}
+	return t
+}
+
+func (t *shardTable[T]) shard(seed maphash.Seed, v T) *setShard[T] {
+	return t.shards[maphash.Comparable(seed, v)%uint64(len(t.shards))]
+}
+
+// newLike returns an empty set with the same seed and number of shards.
+func (s *ShardedSet[T]) newLike(cardinality int) Set[T] {
+	return newShardedSet[T](s.seed, s.Shards(), cardinality)
+}
+
+// lockShard locks and returns the shard holding v, following the shards
+// Reshard migrated to their new table.
+func (s *ShardedSet[T]) lockShard(v T) *setShard[T] {
+	t := s.table.Load()
+	for {
+		sh := t.shard(s.seed, v)
+		sh.Lock()
+		if sh.moved == nil {
+			return sh
+		}
+		t = sh.moved
+		sh.Unlock()
+	}
+}
+
+// rlockShard is like lockShard, but locks the shard for reading.
+func (s *ShardedSet[T]) rlockShard(v T) *setShard[T] {
+	t := s.table.Load()
+	for {
+		sh := t.shard(s.seed, v)
+		sh.RLock()
+		if sh.moved == nil {
+			return sh
+		}
+		t = sh.moved
+		sh.RUnlock()
+	}
+}
+
+// rlockShards locks s.resizing for reading and returns the shards holding
+// the elements, along with the number of shards to give to results. While
+// Reshard runs those mix shards of both tables, so aligned reports whether
+// shard i of a set with the same seed and number of shards holds the same
+// elements as shards[i].
+func (s *ShardedSet[T]) rlockShards() (shards []*setShard[T], n int, aligned bool) {
+	s.resizing.RLock()
+	t := s.table.Load()
+	if s.next == nil {
+		return t.shards, len(t.shards), true
+	}
+	for _, sh := range t.shards {
+		if sh.moved == nil {
+			shards = append(shards, sh)
+		}
+	}
+	return append(shards, s.next.shards...), len(s.next.shards), false
+}
+
+// into returns the elements of the shard for v of s, which must not be
+// shared yet, given that v comes from shard i as returned by rlockShards.
+func (s *ShardedSet[T]) into(i int, aligned bool, v T) *threadUnsafeSet[T] {
+	t := s.table.Load()
+	if aligned {
+		return &t.shards[i].elems
+	}
+	return &t.shard(s.seed, v).elems
+}
+
+// Shards returns the number of shards of the set. While Reshard runs, it
+// returns the number of shards before the change.
+func (s *ShardedSet[T]) Shards() int {
+	return len(s.table.Load().shards)
+}
+
+// Reshard changes the number of shards of the set, where a non-positive
+// number picks four per available CPU. The elements migrate one shard at a
+// time: operations on single elements go on meanwhile, and operations
+// spanning the whole set only wait for the shard being migrated.
+func (s *ShardedSet[T]) Reshard(shards int) {
+	s.reshard.Lock()
+	defer s.reshard.Unlock()
+
+	old := s.table.Load()
+	next := newShardTable[T](shards, s.Cardinality())
+	if len(next.shards) == len(old.shards) {
+		return
+	}
+
+	s.resizing.Lock()
+	s.next = next
+	s.resizing.Unlock()
+
+	for _, sh := range old.shards {
+		s.resizing.Lock()
+		sh.Lock()
+		for v := range sh.elems {
+			dst := next.shard(s.seed, v)
+			dst.Lock()
+			dst.elems.add(v)
+			dst.Unlock()
+		}
+		sh.elems, sh.moved = nil, next
+		sh.Unlock()
+		s.resizing.Unlock()
+	}
+
+	s.resizing.Lock()
+	s.table.Store(next)
+	s.next = nil
+	s.resizing.Unlock()
+}
+
+func (s *ShardedSet[T]) Add(v T) bool {
+	sh := s.lockShard(v)
+	defer sh.Unlock()
+
+	if sh.elems.contains(v) {
+		return false
+	}
+	sh.elems.add(v)
+	return true
+}
+
+func (s *ShardedSet[T]) Append(v ...T) int {
+	n := 0
+	for _, val := range v {
+		if s.Add(val) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *ShardedSet[T]) AppendFrom(other Set[T]) int {
+	if other == Set[T](s) {
+		return 0
+	}
+	return s.Append(other.ToSlice()...)
+}
+
+func (s *ShardedSet[T]) Cardinality() int {
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	n := 0
+	for _, sh := range shards {
+		sh.RLock()
+		n += len(sh.elems)
+		sh.RUnlock()
+	}
+	return n
+}
+
+func (s *ShardedSet[T]) Capabilities() Capabilities {
+	return CapThreadSafe
+}
+
+func (s *ShardedSet[T]) Clear() {
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	for _, sh := range shards {
+		sh.Lock()
+		sh.elems.Clear()
+		sh.Unlock()
+	}
+}
+
+func (s *ShardedSet[T]) Clone() Set[T] {
+	shards, n, aligned := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	clone := newShardedSet[T](s.seed, n, 0)
+	for i, sh := range shards {
+		sh.RLock()
+		if aligned {
+			// the clone shares the seed, so the shards are copied as they are
+			clone.table.Load().shards[i].elems = mapclone(sh.elems)
+		} else {
+			for v := range sh.elems {
+				clone.into(i, false, v).add(v)
+			}
+		}
+		sh.RUnlock()
+	}
+	return clone
+}
+
+func (s *ShardedSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.ContainsOne(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ShardedSet[T]) ContainsOne(v T) bool {
+	sh := s.rlockShard(v)
+	defer sh.RUnlock()
+
+	return sh.elems.contains(v)
+}
+
+func (s *ShardedSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.ContainsOne(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ShardedSet[T]) ContainsAnyElement(other Set[T]) bool {
+	return s.ContainsAny(other.ToSlice()...)
+}
+
+// filter returns a sharded set of the elements of s that satisfy keep. No
+// lock of s is held while keep runs, so keep may query other sets,
+// including s itself.
+func (s *ShardedSet[T]) filter(keep func(T) bool) *ShardedSet[T] {
+	shards, n, aligned := s.rlockShards()
+	elems := make([][]T, len(shards))
+	for i, sh := range shards {
+		sh.RLock()
+		elems[i] = sh.elems.ToSlice()
+		sh.RUnlock()
+	}
+	s.resizing.RUnlock()
+
+	result := newShardedSet[T](s.seed, n, 0)
+	for i := range elems {
+		if aligned {
+			result.table.Load().shards[i].elems = make(threadUnsafeSet[T], len(elems[i]))
+		}
+
+		// the result shares the seed, so aligned elements stay in the same shard
+		for _, v := range elems[i] {
+			if keep(v) {
+				result.into(i, aligned, v).add(v)
+			}
+		}
+	}
+	return result
+}
+
+func (s *ShardedSet[T]) Difference(other Set[T]) Set[T] {
+	return s.filter(func(v T) bool { return !other.ContainsOne(v) })
+}
+
+func (s *ShardedSet[T]) Equal(other Set[T]) bool {
+	return s.Cardinality() == other.Cardinality() && s.IsSubset(other)
+}
+
+func (s *ShardedSet[T]) Intersect(other Set[T]) Set[T] {
+	return s.filter(other.ContainsOne)
+}
+
+func (s *ShardedSet[T]) IsEmpty() bool {
+	return s.Cardinality() == 0
+}
+
+func (s *ShardedSet[T]) IsProperSubset(other Set[T]) bool {
+	return s.Cardinality() < other.Cardinality() && s.IsSubset(other)
+}
+
+func (s *ShardedSet[T]) IsProperSuperset(other Set[T]) bool {
+	return s.Cardinality() > other.Cardinality() && s.IsSuperset(other)
+}
+
+func (s *ShardedSet[T]) IsSubset(other Set[T]) bool {
+	for _, v := range s.ToSlice() {
+		if !other.ContainsOne(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ShardedSet[T]) IsSuperset(other Set[T]) bool {
+	return s.Contains(other.ToSlice()...)
+}
+
+func (s *ShardedSet[T]) Each(cb func(T) bool) {
+	_ = s.EachErr(func(v T) error {
+		if cb(v) {
+			return errStopEach
+		}
+		return nil
+	})
+}
+
+// errStopEach stops EachErr on behalf of Each.
+var errStopEach = errors.New("mapset: stop")
+
+func (s *ShardedSet[T]) EachErr(cb func(T) error) error {
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	for _, sh := range shards {
+		if err := s.eachShard(sh, cb); err != nil {
+			if err == errStopEach {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShardedSet[T]) eachShard(sh *setShard[T], cb func(T) error) error {
+	sh.RLock()
+	// the deferred unlock also runs while a panic of cb unwinds the stack
+	defer sh.RUnlock()
+	return sh.elems.EachErr(cb)
+}
+
+func (s *ShardedSet[T]) Filter(cb func(T) bool) Set[T] {
+	return s.filter(cb)
+}
+
+func (s *ShardedSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	shards, n, aligned := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	matching := newShardedSet[T](s.seed, n, 0)
+	rest := newShardedSet[T](s.seed, n, 0)
+	for i, sh := range shards {
+		sh.RLock()
+		for v := range sh.elems {
+			if pred(v) {
+				matching.into(i, aligned, v).add(v)
+			} else {
+				rest.into(i, aligned, v).add(v)
+			}
+		}
+		sh.RUnlock()
+	}
+	return matching, rest
+}
+
+func (s *ShardedSet[T]) PartitionN(n int, hash func(T) uint64) []Set[T] {
+	if n <= 0 {
+		return nil
+	}
+
+	shards, m, aligned := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	parts := make([]*ShardedSet[T], n)
+	for i := range parts {
+		parts[i] = newShardedSet[T](s.seed, m, 0)
+	}
+	for i, sh := range shards {
+		sh.RLock()
+		for v := range sh.elems {
+			parts[hash(v)%uint64(n)].into(i, aligned, v).add(v)
+		}
+		sh.RUnlock()
+	}
+
+	sets := make([]Set[T], n)
+	for i := range parts {
+		sets[i] = parts[i]
+	}
+	return sets
+}
+
+func (s *ShardedSet[T]) All() func(yield func(element T) bool) {
+	return Elements[T](s)
+}
+
+func (s *ShardedSet[T]) Iter() <-chan T {
+	elems := s.ToSlice()
+	ch := make(chan T)
+	go func() {
+		for _, elem := range elems {
+			ch <- elem
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (s *ShardedSet[T]) Iterator() *Iterator[T] {
+	elems := s.ToSlice()
+	iterator, ch, stopCh := newIterator[T]()
+
+	go func() {
+	L:
+		for _, elem := range elems {
+			select {
+			case <-stopCh:
+				break L
+			case ch <- elem:
+			}
+		}
+		close(ch)
+	}()
+
+	return iterator
+}
+
+func (s *ShardedSet[T]) Remove(v T) {
+	sh := s.lockShard(v)
+	sh.elems.Remove(v)
+	sh.Unlock()
+}
+
+func (s *ShardedSet[T]) RemoveAll(i ...T) {
+	for _, v := range i {
+		s.Remove(v)
+	}
+}
+
+func (s *ShardedSet[T]) RemovedWhich(i ...T) []T {
+	var removed []T
+	for _, v := range i {
+		sh := s.lockShard(v)
+		if sh.elems.contains(v) {
+			sh.elems.Remove(v)
+			removed = append(removed, v)
+		}
+		sh.Unlock()
+	}
+	return removed
+}
+
+func (s *ShardedSet[T]) removeIf(pred func(T) bool) []T {
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	var removed []T
+	for _, sh := range shards {
+		sh.Lock()
+		removed = append(removed, sh.elems.removeIf(pred)...)
+		sh.Unlock()
+	}
+	return removed
+}
+
+func (s *ShardedSet[T]) update(fn func(MutableView[T])) {
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	// every operation locking several shards holds s.resizing and locks
+	// them in the same order
+	for _, sh := range shards {
+		sh.Lock()
+		defer sh.Unlock()
+	}
+	fn(shardedView[T]{s: s, shards: shards})
+}
+
+// shardedView is the view of the elements of a sharded set whose shards
+// are all locked.
+type shardedView[T comparable] struct {
+	s      *ShardedSet[T]
+	shards []*setShard[T]
+}
+
+func (v shardedView[T]) shard(elem T) *setShard[T] {
+	sh := v.s.table.Load().shard(v.s.seed, elem)
+	for sh.moved != nil {
+		sh = sh.moved.shard(v.s.seed, elem)
+	}
+	return sh
+}
+
+func (v shardedView[T]) Add(elem T) bool {
+	return mapView[T](v.shard(elem).elems).Add(elem)
+}
+
+func (v shardedView[T]) Remove(elem T) bool {
+	return mapView[T](v.shard(elem).elems).Remove(elem)
+}
+
+func (v shardedView[T]) Contains(elem T) bool {
+	return v.shard(elem).elems.contains(elem)
+}
+
+func (v shardedView[T]) Cardinality() int {
+	n := 0
+	for _, sh := range v.shards {
+		n += len(sh.elems)
+	}
+	return n
+}
+
+func (s *ShardedSet[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return setCodec[T]{s}.ReconcileTo(target, add, remove)
+}
+
+func (s *ShardedSet[T]) String() string {
+	items := make([]string, 0, s.Cardinality())
+	s.Each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// Format implements fmt.Formatter, see formatSet.
+func (s *ShardedSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "NewShardedSet", s.ToSlice(), strconv.Itoa(s.Shards()))
+}
+
+// LogValue implements slog.LogValuer, see LogValue.
+func (s *ShardedSet[T]) LogValue() slog.Value {
+	return LogValue[T](s, DefaultLogValueLimit)
+}
+
+func (s *ShardedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	sd := s.filter(func(v T) bool { return !other.ContainsOne(v) })
+	for _, v := range other.ToSlice() {
+		if !s.ContainsOne(v) {
+			sd.Add(v)
+		}
+	}
+	return sd
+}
+
+func (s *ShardedSet[T]) Union(other Set[T]) Set[T] {
+	elems := other.ToSlice()
+
+	shards, n, aligned := s.rlockShards()
+	union := newShardedSet[T](s.seed, n, 0)
+	for i, sh := range shards {
+		sh.RLock()
+		if aligned {
+			// the maximum number of elements is the sum of both cardinalities
+			union.table.Load().shards[i].elems = make(threadUnsafeSet[T], len(sh.elems)+len(elems)/n)
+		}
+		for v := range sh.elems {
+			union.into(i, aligned, v).add(v)
+		}
+		sh.RUnlock()
+	}
+	s.resizing.RUnlock()
+
+	union.Append(elems...)
+	return union
+}
+
+func (s *ShardedSet[T]) Pop() (v T, ok bool) {
+	items, n := s.PopN(1)
+	if n == 0 {
+		return v, false
+	}
+	return items[0], true
+}
+
+// PopN removes up to n elements, one shard at a time, so unlike with other
+// thread-safe sets the removal is not atomic as a whole.
+func (s *ShardedSet[T]) PopN(n int) ([]T, int) {
+	if n <= 0 {
+		return make([]T, 0), 0
+	}
+
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	var items []T
+	for _, sh := range shards {
+		sh.Lock()
+		popped, _ := sh.elems.PopN(n - len(items))
+		sh.Unlock()
+
+		items = append(items, popped...)
+		if len(items) == n {
+			break
+		}
+	}
+	if items == nil {
+		items = make([]T, 0)
+	}
+	return items, len(items)
+}
+
+func (s *ShardedSet[T]) ToSlice() []T {
+	shards, _, _ := s.rlockShards()
+	defer s.resizing.RUnlock()
+
+	n := 0
+	for _, sh := range shards {
+		sh.RLock()
+		n += len(sh.elems)
+		sh.RUnlock()
+	}
+	elems := make([]T, 0, n)
+	for _, sh := range shards {
+		sh.RLock()
+		for v := range sh.elems {
+			elems = append(elems, v)
+		}
+		sh.RUnlock()
+	}
+	return elems
+}
+
+func (s *ShardedSet[T]) CanonicalBytes() []byte {
+	elems := s.ToSlice()
+	encoded := make([][]byte, 0, len(elems))
+	for _, elem := range elems {
+		encoded = append(encoded, canonicalElement(elem))
+	}
+	return canonicalBytes(encoded)
+}
+
+// MarshalJSON creates a JSON array from the set, it marshals all elements
+func (s *ShardedSet[T]) MarshalJSON() ([]byte, error) {
+	return setCodec[T]{s}.MarshalJSON()
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set.
+func (s *ShardedSet[T]) UnmarshalJSON(b []byte) error {
+	return setCodec[T]{s}.UnmarshalJSON(b)
+}
+
+// MarshalBinary encodes the set as a snapshot.
+func (s *ShardedSet[T]) MarshalBinary() ([]byte, error) {
+	return setCodec[T]{s}.MarshalBinary()
+}
+
+// UnmarshalBinary adds the elements of a snapshot to the set, creating its
+// shards if needed as gob decodes into zero values.
+func (s *ShardedSet[T]) UnmarshalBinary(data []byte) error {
+	if s.table.Load() == nil {
+		s.init(maphash.MakeSeed(), 0, 0)
+	}
+	return setCodec[T]{s}.UnmarshalBinary(data)
+}
+
+// MarshalText encodes the elements of the set as comma-separated text.
+func (s *ShardedSet[T]) MarshalText() ([]byte, error) {
+	return setCodec[T]{s}.MarshalText()
+}
+
+// UnmarshalText adds the elements of comma-separated text to the set.
+func (s *ShardedSet[T]) UnmarshalText(text []byte) error {
+	if s.table.Load() == nil {
+		s.init(maphash.MakeSeed(), 0, 0)
+	}
+	return setCodec[T]{s}.UnmarshalText(text)
+}
+
+// MarshalYAML returns the elements of the set as a slice, which YAML
+// encoders write as a sequence.
+func (s *ShardedSet[T]) MarshalYAML() (interface{}, error) {
+	return setCodec[T]{s}.MarshalYAML()
+}
+
+// UnmarshalYAML adds the elements of a YAML sequence to the set.
+func (s *ShardedSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if s.table.Load() == nil {
+		s.init(maphash.MakeSeed(), 0, 0)
+	}
+	return setCodec[T]{s}.UnmarshalYAML(unmarshal)
+}
+
+// MarshalBSONValue creates a BSON array from the set.
+func (s *ShardedSet[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return setCodec[T]{s}.MarshalBSONValue()
+}
+
+// UnmarshalBSONValue adds the elements of a BSON array to the set.
+func (s *ShardedSet[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return setCodec[T]{s}.UnmarshalBSONValue(bt, b)
+}
@@ -0,0 +1,262 @@
+//go:build go1.24
+// +build go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+func Test_ShardedSet(t *testing.T) {
+	s := NewShardedSet(4, 5, 3, 9, 3)
+
+	if caps := s.Capabilities(); caps != CapThreadSafe {
+		t.Errorf("expected the set to be thread-safe, got %v", caps)
+	}
+
+	if s.Shards() != 4 {
+		t.Errorf("expected 4 shards, got %d", s.Shards())
+	}
+	if !s.Add(1) || s.Add(5) {
+		t.Error("Add should report whether the element was added")
+	}
+	if n := s.Append(7, 2, 7, 9); n != 2 {
+		t.Errorf("expected 2 elements to be appended, got %d", n)
+	}
+	if n := s.AppendFrom(NewSet(4, 1)); n != 1 {
+		t.Errorf("expected 1 element to be appended, got %d", n)
+	}
+
+	expected := NewSet(1, 2, 3, 4, 5, 7, 9)
+	if !s.Equal(expected) || !expected.Equal(NewSet(s.ToSlice()...)) {
+		t.Errorf("expected %v, got %v", expected, s)
+	}
+	if s.Cardinality() != 7 || s.IsEmpty() {
+		t.Errorf("expected 7 elements, got %d", s.Cardinality())
+	}
+	if !s.Contains(1, 9) || s.Contains(1, 8) || !s.ContainsAny(8, 9) || s.ContainsOne(8) {
+		t.Error("unexpected membership")
+	}
+	if !s.IsSuperset(NewSet(1, 2)) || !s.IsProperSuperset(NewSet(1, 2)) || !s.IsSubset(expected) || s.IsProperSubset(expected) {
+		t.Error("unexpected subset relations")
+	}
+
+	other := NewThreadUnsafeSet(8, 9)
+	if d := s.Difference(other); !d.Equal(NewSet(1, 2, 3, 4, 5, 7)) {
+		t.Errorf("unexpected difference: %v", d)
+	}
+	if i := s.Intersect(other); !i.Equal(NewSet(9)) {
+		t.Errorf("unexpected intersection: %v", i)
+	}
+	if u := s.Union(other); !u.Equal(NewSet(1, 2, 3, 4, 5, 7, 8, 9)) {
+		t.Errorf("unexpected union: %v", u)
+	}
+	if sd := s.SymmetricDifference(other); !sd.Equal(NewSet(1, 2, 3, 4, 5, 7, 8)) {
+		t.Errorf("unexpected symmetric difference: %v", sd)
+	}
+	if _, ok := s.Union(other).(*ShardedSet[int]); !ok {
+		t.Error("set operations should return sharded sets")
+	}
+
+	if evens, odds := s.Partition(func(v int) bool { return v%2 == 0 }); !evens.Equal(NewSet(2, 4)) || !odds.Equal(NewSet(1, 3, 5, 7, 9)) {
+		t.Errorf("unexpected partition: %v and %v", evens, odds)
+	}
+
+	clone := s.Clone()
+	clone.Remove(1)
+	if !s.ContainsOne(1) || clone.ContainsOne(1) {
+		t.Error("clone should be independent of the original")
+	}
+
+	if removed := s.RemovedWhich(1, 8); len(removed) != 1 || removed[0] != 1 {
+		t.Errorf("expected [1] to be removed, got %v", removed)
+	}
+	items, n := s.PopN(4)
+	if n != 4 || len(items) != 4 || s.Cardinality() != 2 || s.ContainsAny(items...) {
+		t.Errorf("unexpected PopN result %v, %d, left %v", items, n, s)
+	}
+	s.Clear()
+	if _, ok := s.Pop(); ok || !s.IsEmpty() {
+		t.Error("expected cleared set to be empty")
+	}
+}
+
+func Test_ShardedSetSelfOperations(t *testing.T) {
+	s := NewShardedSet(2, 1, 2, 3)
+
+	if n := s.AppendFrom(s); n != 0 {
+		t.Errorf("expected nothing to be appended, got %d", n)
+	}
+	if !s.Intersect(s).Equal(s) || !s.Difference(s).IsEmpty() || !s.IsSubset(s) {
+		t.Error("unexpected result of operations of the set with itself")
+	}
+}
+
+func Test_ShardedSetRemoveIf(t *testing.T) {
+	s := NewShardedSet(4, makeRange(100)...)
+	if n := RemoveIf[int](s, func(v int) bool { return v >= 10 }); n != 90 || !s.Equal(NewSet(makeRange(10)...)) {
+		t.Errorf("expected 90 elements to be removed, got %d and %v", n, s)
+	}
+}
+
+func Test_ShardedSetConcurrent(t *testing.T) {
+	s := NewShardedSet[int](0)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				s.Add(g*N + i)
+				s.ContainsOne(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if s.Cardinality() != 8*N {
+		t.Errorf("expected %d elements, got %d", 8*N, s.Cardinality())
+	}
+}
+
+func Test_ShardedSetReshard(t *testing.T) {
+	s := NewShardedSet(4, makeRange(N)...)
+
+	// writers own disjoint ranges above N while the shards migrate
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				v := (g+1)*N + i
+				s.Add(v)
+				if i%2 == 1 {
+					s.Remove(v)
+				}
+			}
+		}(g)
+	}
+	readers := make(chan struct{})
+	go func() {
+		defer close(readers)
+		for i := 0; i < 20; i++ {
+			// every element is seen once, whichever shard holds it
+			initial := 0
+			s.Each(func(v int) bool {
+				if v < N {
+					initial++
+				}
+				return false
+			})
+			if initial != N {
+				t.Errorf("expected %d initial elements, got %d", N, initial)
+			}
+			if !s.Clone().Contains(makeRange(N)...) || !s.Union(NewSet(-1)).Contains(-1, 0) {
+				t.Error("expected the copies to contain the initial elements")
+			}
+		}
+	}()
+	for _, n := range []int{16, 3, 8} {
+		s.Reshard(n)
+		if s.Shards() != n {
+			t.Errorf("expected %d shards, got %d", n, s.Shards())
+		}
+	}
+	wg.Wait()
+	<-readers
+
+	expected := NewSet(makeRange(N)...)
+	for g := 0; g < 4; g++ {
+		for i := 0; i < N; i += 2 {
+			expected.Add((g+1)*N + i)
+		}
+	}
+	if s.Cardinality() != expected.Cardinality() || !s.IsSuperset(expected) {
+		t.Errorf("expected %d elements, got %d", expected.Cardinality(), s.Cardinality())
+	}
+	if c := s.Clone().(*ShardedSet[int]); c.Shards() != 8 || !c.Equal(expected) {
+		t.Error("expected the clone to have the same shards and elements")
+	}
+}
+
+func Test_ShardedSetJSON(t *testing.T) {
+	b, err := json.Marshal(NewShardedSet(3, "a", "b"))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	s := NewShardedSet[string](3)
+	if err := json.Unmarshal(b, s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(NewSet("a", "b")) {
+		t.Errorf("expected a and b, got %v", s)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	var decoded ShardedSet[string]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !decoded.Equal(s) {
+		t.Errorf("expected a and b, got %v", &decoded)
+	}
+
+	if string(s.CanonicalBytes()) != string(CanonicalBytes(NewSet("b", "a"))) {
+		t.Error("canonical bytes should not depend on the set implementation")
+	}
+}
+
+func Test_ShardedSetLogValue(t *testing.T) {
+	if got := slog.AnyValue(NewShardedSet(4, 3, 1, 2)).Resolve().String(); got != "[cardinality=3 elements=[1 2 3]]" {
+		t.Errorf("Expected the sorted elements, got: %s", got)
+	}
+}
+
+func Test_ShardedSetUpdate(t *testing.T) {
+	s := NewShardedSet(4, makeRange(10)...)
+	Update[int](s, func(tx MutableView[int]) {
+		for i := 0; i < 10; i += 2 {
+			tx.Remove(i)
+		}
+		tx.Add(10)
+		if tx.Cardinality() != 6 || !tx.Contains(10) || tx.Contains(0) {
+			t.Errorf("Expected 6 elements, got: %d", tx.Cardinality())
+		}
+	})
+	if !s.Equal(NewSet(1, 3, 5, 7, 9, 10)) {
+		t.Errorf("Expected the odd elements and 10, got: %v", s)
+	}
+}
@@ -0,0 +1,238 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package shmset
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// ErrFull is returned when adding a key to a set that has no free slot
+// left.
+var ErrFull = errors.New("shmset: set is full")
+
+// ErrReservedKey is returned when adding one of the two keys used to mark
+// free and removed slots, 0 and math.MaxUint64.
+var ErrReservedKey = errors.New("shmset: reserved key")
+
+// ErrInvalidFile is returned by Open for files that don't hold a set.
+var ErrInvalidFile = errors.New("shmset: not a set file")
+
+const (
+	// magic identifies set files, it also changes with the layout.
+	magic uint64 = 0x4d534554_53484d01 // "MSETSHM" + layout version 1
+
+	empty     uint64 = 0
+	tombstone uint64 = ^uint64(0)
+
+	// header fields, in words: magic, capacity, count, used slots
+	headerWords = 4
+	wordSize    = 8
+)
+
+// Set is a set of uint64 keys shared between processes. Its methods are safe
+// for concurrent use, within a process and across processes mapping the
+// same file.
+type Set struct {
+	data  []byte
+	words []uint64
+	slots []uint64
+}
+
+// Create creates, or truncates, the file at path and maps a new empty set
+// able to hold capacity keys into it. The file is created with mode 0600.
+func Create(path string, capacity int) (*Set, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("shmset: invalid capacity %d", capacity)
+	}
+	// keep a quarter of the slots free so that probe sequences stay short
+	slots := capacity + capacity/3 + 1
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(int64((headerWords + slots) * wordSize)); err != nil {
+		return nil, err
+	}
+	s, err := mmap(f)
+	if err != nil {
+		return nil, err
+	}
+
+	s.words[1] = uint64(capacity)
+	s.slots = s.words[headerWords:]
+	// the magic is published last, so that Open never sees a partially
+	// initialized header
+	atomic.StoreUint64(&s.words[0], magic)
+	return s, nil
+}
+
+// Open maps the set previously created with Create in the file at path.
+func Open(path string) (*Set, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := mmap(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.words) <= headerWords || atomic.LoadUint64(&s.words[0]) != magic {
+		s.Close()
+		return nil, ErrInvalidFile
+	}
+	s.slots = s.words[headerWords:]
+	return s, nil
+}
+
+func mmap(f *os.File) (*Set, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := int(info.Size())
+	if size < headerWords*wordSize || size%wordSize != 0 {
+		return nil, ErrInvalidFile
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	// mappings are page aligned, so the words are suitably aligned for
+	// atomic operations
+	words := unsafe.Slice((*uint64)(unsafe.Pointer(&data[0])), size/wordSize)
+	return &Set{data: data, words: words}, nil
+}
+
+// Close unmaps the set. The set remains in the file and in the mappings of
+// other processes.
+func (s *Set) Close() error {
+	if s.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(s.data)
+	s.data, s.words, s.slots = nil, nil, nil
+	return err
+}
+
+// Capacity returns the number of keys the set was created for.
+func (s *Set) Capacity() int {
+	return int(atomic.LoadUint64(&s.words[1]))
+}
+
+// Cardinality returns the number of keys in the set.
+func (s *Set) Cardinality() int {
+	return int(atomic.LoadUint64(&s.words[2]))
+}
+
+// Add adds a key to the set and returns whether it was added. It returns
+// ErrFull when the set has no free slot left, which may happen before
+// Capacity keys were added if keys were removed.
+func (s *Set) Add(key uint64) (bool, error) {
+	if key == empty || key == tombstone {
+		return false, ErrReservedKey
+	}
+
+	n := uint64(len(s.slots))
+	for i, probe := hash(key)%n, uint64(0); probe < n; i, probe = (i+1)%n, probe+1 {
+		for {
+			slot := atomic.LoadUint64(&s.slots[i])
+			if slot == key {
+				return false, nil
+			}
+			if slot != empty {
+				break
+			}
+			if atomic.LoadUint64(&s.words[3]) >= atomic.LoadUint64(&s.words[1]) {
+				return false, ErrFull
+			}
+			if atomic.CompareAndSwapUint64(&s.slots[i], empty, key) {
+				atomic.AddUint64(&s.words[2], 1)
+				atomic.AddUint64(&s.words[3], 1)
+				return true, nil
+			}
+			// another process claimed the slot, maybe for the same key
+		}
+	}
+	return false, ErrFull
+}
+
+// Remove removes a key from the set and returns whether it was present.
+func (s *Set) Remove(key uint64) bool {
+	i, found := s.find(key)
+	if !found || !atomic.CompareAndSwapUint64(&s.slots[i], key, tombstone) {
+		return false
+	}
+	atomic.AddUint64(&s.words[2], ^uint64(0))
+	return true
+}
+
+// Contains returns whether the key is in the set.
+func (s *Set) Contains(key uint64) bool {
+	_, found := s.find(key)
+	return found
+}
+
+func (s *Set) find(key uint64) (uint64, bool) {
+	if key == empty || key == tombstone {
+		return 0, false
+	}
+
+	n := uint64(len(s.slots))
+	for i, probe := hash(key)%n, uint64(0); probe < n; i, probe = (i+1)%n, probe+1 {
+		switch atomic.LoadUint64(&s.slots[i]) {
+		case key:
+			return i, true
+		case empty:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// hash is the splitmix64 finalizer, it spreads sequential keys over the
+// table.
+func hash(key uint64) uint64 {
+	key ^= key >> 30
+	key *= 0xbf58476d1ce4e5b9
+	key ^= key >> 27
+	key *= 0x94d049bb133111eb
+	key ^= key >> 31
+	return key
+}
@@ -0,0 +1,172 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package shmset
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func Test_Set(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allow")
+	s, err := Create(path, 10)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer s.Close()
+
+	if added, err := s.Add(42); !added || err != nil {
+		t.Errorf("expected 42 to be added, got %v, %v", added, err)
+	}
+	if added, _ := s.Add(42); added {
+		t.Error("adding a present key should report false")
+	}
+	if !s.Contains(42) || s.Contains(7) {
+		t.Error("unexpected set contents")
+	}
+	if s.Cardinality() != 1 || s.Capacity() != 10 {
+		t.Errorf("unexpected cardinality %d or capacity %d", s.Cardinality(), s.Capacity())
+	}
+	if !s.Remove(42) || s.Remove(42) || s.Contains(42) {
+		t.Error("Remove should remove the key once")
+	}
+	for _, key := range []uint64{0, ^uint64(0)} {
+		if _, err := s.Add(key); !errors.Is(err, ErrReservedKey) {
+			t.Errorf("Expected ErrReservedKey for %d, got: %v", key, err)
+		}
+	}
+}
+
+func Test_SetFull(t *testing.T) {
+	s, err := Create(filepath.Join(t.TempDir(), "allow"), 100)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer s.Close()
+
+	for key := uint64(1); key <= 100; key++ {
+		if _, err := s.Add(key); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+	}
+	if _, err := s.Add(101); !errors.Is(err, ErrFull) {
+		t.Errorf("Expected ErrFull, got: %v", err)
+	}
+	// present keys are still reported as such
+	if added, err := s.Add(100); added || err != nil {
+		t.Errorf("expected 100 to be present, got %v, %v", added, err)
+	}
+}
+
+func Test_SetSharedMappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allow")
+	a, err := Create(path, 1000)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer a.Close()
+	b, err := Open(path)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer b.Close()
+
+	var wg sync.WaitGroup
+	for w, s := range []*Set{a, b, a, b} {
+		wg.Add(1)
+		go func(w int, s *Set) {
+			defer wg.Done()
+			// every key is added by two of the workers
+			for key := uint64(w%2 + 1); key <= 1000; key += 2 {
+				if _, err := s.Add(key); err != nil {
+					t.Errorf("Error should be nil: %v", err)
+				}
+			}
+		}(w, s)
+	}
+	wg.Wait()
+
+	if a.Cardinality() != 1000 || b.Cardinality() != 1000 {
+		t.Errorf("expected 1000 keys, got %d and %d", a.Cardinality(), b.Cardinality())
+	}
+	for key := uint64(1); key <= 1000; key++ {
+		if !a.Contains(key) || !b.Contains(key) {
+			t.Fatalf("%d should be visible through both mappings", key)
+		}
+	}
+}
+
+func Test_SetOtherProcess(t *testing.T) {
+	if path := os.Getenv("SHMSET_TEST_PATH"); path != "" {
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		defer s.Close()
+		key, _ := strconv.ParseUint(os.Getenv("SHMSET_TEST_KEY"), 10, 64)
+		if _, err := s.Add(key); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "allow")
+	s, err := Create(path, 10)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer s.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_SetOtherProcess$")
+	cmd.Env = append(os.Environ(), "SHMSET_TEST_PATH="+path, "SHMSET_TEST_KEY=1234")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("child process failed: %v\n%s", err, out)
+	}
+	if !s.Contains(1234) {
+		t.Error("the key added by the other process should be visible")
+	}
+}
+
+func Test_OpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other")
+	if err := os.WriteFile(path, make([]byte, 64), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Expected ErrInvalidFile, got: %v", err)
+	}
+	if _, err := Create(path, 0); err == nil {
+		t.Error("a non-positive capacity should be rejected")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+)
+
+// IterShuffled returns an iterator that yields every element of s once, in
+// a random order that is fully determined by seed and the contents of s,
+// so that randomized processing stays repeatable in tests and simulations.
+// Starting with Go 1.23, users can use a for loop to iterate over it.
+//
+// Elements are ordered by their canonical encoding (see CanonicalBytes)
+// before shuffling, so the order doesn't depend on map iteration order.
+// Elements sharing the same canonical encoding, such as distinct pointers
+// to equal values, may still be yielded in varying order.
+func IterShuffled[T comparable](s Set[T], seed int64) func(func(element T) bool) {
+	return func(yield func(element T) bool) {
+		type keyed struct {
+			key  []byte
+			elem T
+		}
+
+		elems := make([]keyed, 0, s.Cardinality())
+		s.Each(func(elem T) bool {
+			elems = append(elems, keyed{canonicalElement(elem), elem})
+			return false
+		})
+		sort.Slice(elems, func(i, j int) bool {
+			return bytes.Compare(elems[i].key, elems[j].key) < 0
+		})
+
+		// Fisher-Yates shuffle, performed lazily so that breaking out of the
+		// loop early doesn't pay for shuffling the whole set.
+		r := rand.New(rand.NewSource(seed))
+		for i := range elems {
+			j := i + r.Intn(len(elems)-i)
+			elems[i], elems[j] = elems[j], elems[i]
+			if !yield(elems[i].elem) {
+				return
+			}
+		}
+	}
+}
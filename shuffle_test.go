@@ -0,0 +1,82 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func collectShuffled[T comparable](s Set[T], seed int64) []T {
+	var elems []T
+	IterShuffled(s, seed)(func(elem T) bool {
+		elems = append(elems, elem)
+		return true
+	})
+	return elems
+}
+
+func Test_IterShuffled(t *testing.T) {
+	vals := make([]int, 100)
+	for i := range vals {
+		vals[i] = i
+	}
+	safe := NewSet(vals...)
+	unsafe := NewThreadUnsafeSet(vals...)
+
+	first := collectShuffled(safe, 42)
+	if len(first) != len(vals) || !NewSet(first...).Equal(safe) {
+		t.Fatal("every element should be yielded exactly once")
+	}
+
+	for i := 0; i < 5; i++ {
+		again := collectShuffled(unsafe.Clone(), 42)
+		for j := range first {
+			if first[j] != again[j] {
+				t.Fatalf("the same seed should yield the same order, differs at %d", j)
+			}
+		}
+	}
+
+	other := collectShuffled(safe, 43)
+	same := true
+	for j := range first {
+		same = same && first[j] == other[j]
+	}
+	if same {
+		t.Error("different seeds should yield different orders")
+	}
+}
+
+func Test_IterShuffledStop(t *testing.T) {
+	var count int
+	IterShuffled(NewSet(1, 2, 3, 4), 1)(func(int) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("iteration should stop on the way, visited %d", count)
+	}
+}
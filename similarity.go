@@ -0,0 +1,115 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// Jaccard returns the Jaccard index of a and b, the cardinality of their
+// intersection divided by the cardinality of their union, from 0 for
+// disjoint sets to 1 for equal sets. Two empty sets have an index of 1.
+//
+// Like the other similarity measures, it counts the common elements in a
+// single pass over the smaller set, without building the intersection or
+// the union.
+func Jaccard[T comparable](a, b Set[T]) float64 {
+	common, na, nb := intersectionCardinality(a, b)
+	if na+nb == 0 {
+		return 1
+	}
+	return float64(common) / float64(na+nb-common)
+}
+
+// Overlap returns the overlap coefficient of a and b, the cardinality of
+// their intersection divided by the cardinality of the smaller set, which
+// is 1 when one set is a subset of the other. It returns 1 if either set
+// is empty.
+func Overlap[T comparable](a, b Set[T]) float64 {
+	common, na, nb := intersectionCardinality(a, b)
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	if nb < na {
+		na = nb
+	}
+	return float64(common) / float64(na)
+}
+
+// Dice returns the Sørensen–Dice coefficient of a and b, twice the
+// cardinality of their intersection divided by the sum of their
+// cardinalities, from 0 for disjoint sets to 1 for equal sets. Two empty
+// sets have a coefficient of 1.
+func Dice[T comparable](a, b Set[T]) float64 {
+	common, na, nb := intersectionCardinality(a, b)
+	if na+nb == 0 {
+		return 1
+	}
+	return 2 * float64(common) / float64(na+nb)
+}
+
+// intersectionCardinality returns the number of elements in both a and b,
+// and the cardinalities of a and b, by looking up the elements of the
+// smaller set in the larger one.
+func intersectionCardinality[T comparable](a, b Set[T]) (common, na, nb int) {
+	switch x := a.(type) {
+	case *threadUnsafeSet[T]:
+		if y, ok := b.(*threadUnsafeSet[T]); ok {
+			common, na, nb = x.intersectionCardinality(y)
+			return
+		}
+	case *threadSafeSet[T]:
+		if y, ok := b.(*threadSafeSet[T]); ok {
+			rlockBoth(x, y)
+			common, na, nb = x.uss.intersectionCardinality(y.uss)
+			runlockBoth(x, y)
+			return
+		}
+	}
+
+	na, nb = a.Cardinality(), b.Cardinality()
+	small, large := a, b
+	if nb < na {
+		small, large = b, a
+	}
+	// the elements are copied so that no lock of small is held while large
+	// is queried, as both may be the same set
+	for _, v := range small.ToSlice() {
+		if large.ContainsOne(v) {
+			common++
+		}
+	}
+	return common, na, nb
+}
+
+func (s *threadUnsafeSet[T]) intersectionCardinality(o *threadUnsafeSet[T]) (common, ns, no int) {
+	small, large := s, o
+	if len(*o) < len(*s) {
+		small, large = o, s
+	}
+	for elem := range *small {
+		if large.contains(elem) {
+			common++
+		}
+	}
+	return common, len(*s), len(*o)
+}
@@ -0,0 +1,76 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_Similarity(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a, b := ctor(1, 2, 3, 4), ctor(3, 4, 5)
+
+		for _, tt := range []struct {
+			name string
+			f    func(a, b Set[int]) float64
+			a, b Set[int]
+			want float64
+		}{
+			{"Jaccard", Jaccard[int], a, b, 2.0 / 5},
+			{"JaccardSelf", Jaccard[int], a, a, 1},
+			{"JaccardDisjoint", Jaccard[int], a, ctor(7), 0},
+			{"JaccardEmpty", Jaccard[int], ctor(), ctor(), 1},
+			{"Overlap", Overlap[int], a, b, 2.0 / 3},
+			{"OverlapSubset", Overlap[int], a, ctor(1, 2), 1},
+			{"OverlapEmpty", Overlap[int], a, ctor(), 1},
+			{"Dice", Dice[int], a, b, 4.0 / 7},
+			{"DiceEmpty", Dice[int], ctor(), ctor(), 1},
+			{"Foreign", Jaccard[int], a, foreignSet[int]{b}, 2.0 / 5},
+			{"Sorted", Dice[int], NewSortedSet(1, 2, 3, 4), b, 4.0 / 7},
+		} {
+			if got := tt.f(tt.a, tt.b); got != tt.want {
+				t.Errorf("%s: expected %v, got: %v", tt.name, tt.want, got)
+			}
+			if got := tt.f(tt.b, tt.a); got != tt.want {
+				t.Errorf("%s: expected a symmetric %v, got: %v", tt.name, tt.want, got)
+			}
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_JaccardAllocs(t *testing.T) {
+	a, b := NewSet(makeRange(1000)...), Shift(NewSet(makeRange(1000)...), 500)
+	if allocs := testing.AllocsPerRun(10, func() { Jaccard(a, b) }); allocs != 0 {
+		t.Errorf("Expected no allocations, got: %v", allocs)
+	}
+}
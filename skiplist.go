@@ -0,0 +1,231 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// skipListMaxLevel bounds the height of the nodes of a skip list, which is
+// enough for about 4^16 elements.
+const skipListMaxLevel = 16
+
+type skipNode[T any] struct {
+	value T
+	next  []*skipNode[T]
+}
+
+// skipList keeps unique elements in ascending order. Searching, inserting
+// and removing an element cost O(log n) on average, and removing a run of k
+// consecutive elements costs O(log n + k). The zero value is an empty list.
+type skipList[T cmp.Ordered] struct {
+	head  [skipListMaxLevel]*skipNode[T]
+	level int
+	len   int
+	seed  uint64
+}
+
+// skipLinks holds, for every level, the link to update when inserting or
+// removing at a given position.
+type skipLinks[T any] [skipListMaxLevel]**skipNode[T]
+
+// randomLevel returns the height of a new node, each level being kept with
+// a probability of 1/4.
+func (l *skipList[T]) randomLevel() int {
+	if l.seed == 0 {
+		l.seed = 0x9e3779b97f4a7c15
+	}
+	// xorshift64, which never returns to zero
+	l.seed ^= l.seed << 13
+	l.seed ^= l.seed >> 7
+	l.seed ^= l.seed << 17
+	return min(1+bits.TrailingZeros64(l.seed)/2, skipListMaxLevel)
+}
+
+// reset replaces the elements of the list with sorted, which must be in
+// ascending order without duplicates, in O(n).
+func (l *skipList[T]) reset(sorted []T) {
+	l.head = [skipListMaxLevel]*skipNode[T]{}
+	l.level, l.len = 0, len(sorted)
+
+	var tails skipLinks[T]
+	for i := range tails {
+		tails[i] = &l.head[i]
+	}
+	for _, v := range sorted {
+		n := &skipNode[T]{value: v, next: make([]*skipNode[T], l.randomLevel())}
+		l.level = max(l.level, len(n.next))
+		for i := range n.next {
+			*tails[i] = n
+			tails[i] = &n.next[i]
+		}
+	}
+}
+
+// seek fills links with the links to the first node not less than v, and
+// returns that node, or nil if all elements are less than v.
+func (l *skipList[T]) seek(v T, links *skipLinks[T]) *skipNode[T] {
+	cur := l.head[:]
+	for i := l.level - 1; i >= 0; i-- {
+		for cur[i] != nil && cmp.Less(cur[i].value, v) {
+			cur = cur[i].next
+		}
+		links[i] = &cur[i]
+	}
+	return cur[0]
+}
+
+func (l *skipList[T]) contains(v T) bool {
+	var links skipLinks[T]
+	n := l.seek(v, &links)
+	return n != nil && cmp.Compare(n.value, v) == 0
+}
+
+// insert adds v to the list and reports whether it was absent.
+func (l *skipList[T]) insert(v T) bool {
+	var links skipLinks[T]
+	if n := l.seek(v, &links); n != nil && cmp.Compare(n.value, v) == 0 {
+		return false
+	}
+
+	n := &skipNode[T]{value: v, next: make([]*skipNode[T], l.randomLevel())}
+	for ; l.level < len(n.next); l.level++ {
+		links[l.level] = &l.head[l.level]
+	}
+	for i := range n.next {
+		n.next[i] = *links[i]
+		*links[i] = n
+	}
+	l.len++
+	return true
+}
+
+// remove removes v from the list and reports whether it was present.
+func (l *skipList[T]) remove(v T) bool {
+	var links skipLinks[T]
+	n := l.seek(v, &links)
+	if n == nil || cmp.Compare(n.value, v) != 0 {
+		return false
+	}
+
+	for i := range n.next {
+		*links[i] = n.next[i]
+	}
+	l.len--
+	l.shrink()
+	return true
+}
+
+// removeRange removes the elements between lo and hi, both included, and
+// returns how many were removed.
+func (l *skipList[T]) removeRange(lo, hi T) int {
+	var links skipLinks[T]
+	l.seek(lo, &links)
+	return l.unlink(&links, hi)
+}
+
+// popFront removes and returns the n smallest elements of the list.
+func (l *skipList[T]) popFront(n int) []T {
+	items := make([]T, 0, min(n, l.len))
+	for node := l.head[0]; node != nil && len(items) < n; node = node.next[0] {
+		items = append(items, node.value)
+	}
+	if len(items) == 0 {
+		return items
+	}
+
+	var links skipLinks[T]
+	for i := 0; i < l.level; i++ {
+		links[i] = &l.head[i]
+	}
+	l.unlink(&links, items[len(items)-1])
+	return items
+}
+
+// unlink removes the nodes that follow links up to hi included, visiting
+// every removed node once per level it has, and returns how many were
+// removed.
+func (l *skipList[T]) unlink(links *skipLinks[T], hi T) int {
+	removed := 0
+	for i := 0; i < l.level; i++ {
+		n := *links[i]
+		for n != nil && !cmp.Less(hi, n.value) {
+			n = n.next[i]
+			if i == 0 {
+				removed++
+			}
+		}
+		*links[i] = n
+	}
+	l.len -= removed
+	l.shrink()
+	return removed
+}
+
+// shrink lowers the level of the list past its empty top levels.
+func (l *skipList[T]) shrink() {
+	for l.level > 0 && l.head[l.level-1] == nil {
+		l.level--
+	}
+}
+
+func (l *skipList[T]) first() (*skipNode[T], bool) {
+	return l.head[0], l.head[0] != nil
+}
+
+func (l *skipList[T]) last() (*skipNode[T], bool) {
+	var last *skipNode[T]
+	cur := l.head[:]
+	for i := l.level - 1; i >= 0; i-- {
+		for cur[i] != nil {
+			last = cur[i]
+			cur = last.next
+		}
+	}
+	return last, last != nil
+}
+
+// each calls cb with the elements in ascending order until it returns true.
+func (l *skipList[T]) each(cb func(T) bool) {
+	for n := l.head[0]; n != nil; n = n.next[0] {
+		if cb(n.value) {
+			return
+		}
+	}
+}
+
+// values returns the elements of the list in ascending order.
+func (l *skipList[T]) values() []T {
+	vs := make([]T, 0, l.len)
+	for n := l.head[0]; n != nil; n = n.next[0] {
+		vs = append(vs, n.value)
+	}
+	return vs
+}
@@ -0,0 +1,251 @@
+//go:build go1.24
+// +build go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// SlabSet is a thread-safe set that stores its elements contiguously in a
+// slab, a single slice, while its index map only holds their hashes and
+// positions. For sets of millions of large structs this keeps the elements
+// out of the map buckets, so that growing the set never rehashes or copies
+// them through the map and the garbage collector scans one flat slice.
+//
+// Removing an element moves the last element of the slab into its place,
+// so the order of ToSlice and Each changes as elements are removed.
+type SlabSet[T comparable] struct {
+	mu     sync.RWMutex
+	seed   maphash.Seed
+	slab   []T
+	hashes []uint64
+	// next chains the positions of the elements sharing a hash, -1 ends
+	// a chain
+	next  []int32
+	heads map[uint64]int32
+}
+
+// NewSlabSet creates and returns a new SlabSet with the given elements.
+func NewSlabSet[T comparable](vals ...T) *SlabSet[T] {
+	s := &SlabSet[T]{
+		seed:   maphash.MakeSeed(),
+		slab:   make([]T, 0, len(vals)),
+		hashes: make([]uint64, 0, len(vals)),
+		next:   make([]int32, 0, len(vals)),
+		heads:  make(map[uint64]int32, len(vals)),
+	}
+	for _, v := range vals {
+		s.add(v)
+	}
+	return s
+}
+
+// find returns the position of v in the slab, or -1.
+func (s *SlabSet[T]) find(v T, h uint64) int32 {
+	i, found := s.heads[h]
+	if !found {
+		return -1
+	}
+	for ; i >= 0; i = s.next[i] {
+		if s.slab[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// add must be called with the write lock held.
+func (s *SlabSet[T]) add(v T) bool {
+	h := maphash.Comparable(s.seed, v)
+	if s.find(v, h) >= 0 {
+		return false
+	}
+
+	head, found := s.heads[h]
+	if !found {
+		head = -1
+	}
+	s.heads[h] = int32(len(s.slab))
+	s.slab = append(s.slab, v)
+	s.hashes = append(s.hashes, h)
+	s.next = append(s.next, head)
+	return true
+}
+
+// relink makes whatever points to position from in its hash chain point to
+// position to instead.
+func (s *SlabSet[T]) relink(from, to int32) {
+	h := s.hashes[from]
+	if s.heads[h] == from {
+		if to < 0 {
+			delete(s.heads, h)
+		} else {
+			s.heads[h] = to
+		}
+		return
+	}
+	for i := s.heads[h]; ; i = s.next[i] {
+		if s.next[i] == from {
+			s.next[i] = to
+			return
+		}
+	}
+}
+
+// remove must be called with the write lock held.
+func (s *SlabSet[T]) remove(v T) bool {
+	i := s.find(v, maphash.Comparable(s.seed, v))
+	if i < 0 {
+		return false
+	}
+
+	// unlink i from its chain, then move the last element into the hole
+	s.relink(i, s.next[i])
+	if last := int32(len(s.slab) - 1); i != last {
+		s.relink(last, i)
+		s.slab[i], s.hashes[i], s.next[i] = s.slab[last], s.hashes[last], s.next[last]
+	}
+
+	var zero T
+	s.slab[len(s.slab)-1] = zero
+	s.slab = s.slab[:len(s.slab)-1]
+	s.hashes = s.hashes[:len(s.hashes)-1]
+	s.next = s.next[:len(s.next)-1]
+	return true
+}
+
+// Add adds an element to the set. Returns whether the element was added.
+func (s *SlabSet[T]) Add(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(v)
+}
+
+// Append adds multiple elements to the set. Returns the number of elements
+// added.
+func (s *SlabSet[T]) Append(vals ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, v := range vals {
+		if s.add(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Remove removes a single element from the set. Returns whether the element
+// was present.
+func (s *SlabSet[T]) Remove(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remove(v)
+}
+
+// Clear removes all elements from the set, keeping the allocated memory.
+func (s *SlabSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.slab)
+	s.slab = s.slab[:0]
+	s.hashes = s.hashes[:0]
+	s.next = s.next[:0]
+	clear(s.heads)
+}
+
+// Contains returns whether the given elements are all in the set.
+func (s *SlabSet[T]) Contains(vals ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range vals {
+		if s.find(v, maphash.Comparable(s.seed, v)) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsOne returns whether the given element is in the set.
+func (s *SlabSet[T]) ContainsOne(v T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.find(v, maphash.Comparable(s.seed, v)) >= 0
+}
+
+// Cardinality returns the number of elements in the set.
+func (s *SlabSet[T]) Cardinality() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.slab)
+}
+
+// IsEmpty returns whether the set has no element.
+func (s *SlabSet[T]) IsEmpty() bool {
+	return s.Cardinality() == 0
+}
+
+// Each calls cb with the elements of the set, in slab order, until cb
+// returns true. The read lock is held while cb runs.
+func (s *SlabSet[T]) Each(cb func(T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.slab {
+		if cb(s.slab[i]) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the elements of the set, in slab order.
+func (s *SlabSet[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]T(nil), s.slab...)
+}
+
+// String returns a representation of the set in the same format as Set.
+func (s *SlabSet[T]) String() string {
+	return NewThreadUnsafeSet(s.ToSlice()...).String()
+}
+
+// ToSet returns the elements of the set as a new thread-safe Set.
+func (s *SlabSet[T]) ToSet() Set[T] {
+	return NewSet(s.ToSlice()...)
+}
@@ -0,0 +1,103 @@
+//go:build go1.24
+// +build go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_SlabSet(t *testing.T) {
+	s := NewSlabSet("a", "b", "a")
+
+	if s.Cardinality() != 2 || !s.Contains("a", "b") || s.Contains("a", "c") {
+		t.Errorf("unexpected set contents: %v", s)
+	}
+	if !s.Add("c") || s.Add("c") {
+		t.Error("Add should report whether the element was added")
+	}
+	if n := s.Append("c", "d", "e"); n != 2 {
+		t.Errorf("expected 2 elements to be appended, got %d", n)
+	}
+	if !s.Remove("a") || s.Remove("a") || s.ContainsOne("a") {
+		t.Error("Remove should remove the element once")
+	}
+	if !s.ToSet().Equal(NewSet("b", "c", "d", "e")) {
+		t.Errorf("unexpected set contents: %v", s)
+	}
+
+	n := 0
+	s.Each(func(string) bool {
+		n++
+		return n == 2
+	})
+	if n != 2 {
+		t.Errorf("Each should stop when the callback returns true, got %d calls", n)
+	}
+
+	s.Clear()
+	if !s.IsEmpty() || s.ContainsOne("b") {
+		t.Errorf("expected an empty set, got %v", s)
+	}
+
+	var _ SetView[string] = s
+}
+
+type slabRecord struct {
+	id      int
+	payload [16]int64
+}
+
+func Test_SlabSetRandom(t *testing.T) {
+	s := NewSlabSet[slabRecord]()
+	model := NewThreadUnsafeSet[slabRecord]()
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		v := slabRecord{id: r.Intn(500)}
+		v.payload[0] = int64(v.id)
+		if r.Intn(2) == 0 {
+			if s.Add(v) != model.Add(v) {
+				t.Fatalf("Add(%d) disagrees with the model", v.id)
+			}
+		} else {
+			if s.Remove(v) != model.ContainsOne(v) {
+				t.Fatalf("Remove(%d) disagrees with the model", v.id)
+			}
+			model.Remove(v)
+		}
+	}
+
+	if !NewThreadUnsafeSet(s.ToSlice()...).Equal(model) {
+		t.Error("the set disagrees with the model")
+	}
+	if s.Cardinality() != model.Cardinality() {
+		t.Errorf("expected %d elements, got %d", model.Cardinality(), s.Cardinality())
+	}
+}
@@ -0,0 +1,128 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"container/heap"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+)
+
+// DefaultLogValueLimit is the number of elements logged by the LogValue
+// methods of the sets of this package.
+const DefaultLogValueLimit = 32
+
+// LogValue returns a log/slog value describing s by its cardinality and
+// its limit smallest elements, sorted, so that logging a huge set stays
+// cheap and its output deterministic. Numbers and strings are sorted by
+// value and other elements by their %v representation. A truncated
+// attribute is added when s has more elements than limit. s is scanned
+// with Each, so thread-safe sets are read under a single lock.
+//
+// The sets of this package implement slog.LogValuer with a limit of
+// DefaultLogValueLimit.
+func LogValue[T comparable](s Set[T], limit int) slog.Value {
+	if limit < 0 {
+		limit = 0
+	}
+	kept := &logHeap[T]{less: logOrder[T]()}
+	n := 0
+	s.Each(func(elem T) bool {
+		n++
+		if len(kept.elems) < limit {
+			heap.Push(kept, elem)
+		} else if limit > 0 && kept.less(elem, kept.elems[0]) {
+			kept.elems[0] = elem
+			heap.Fix(kept, 0)
+		}
+		return false
+	})
+	sort.Slice(kept.elems, func(i, j int) bool {
+		return kept.less(kept.elems[i], kept.elems[j])
+	})
+
+	attrs := []slog.Attr{
+		slog.Int("cardinality", n),
+		slog.Any("elements", kept.elems),
+	}
+	if n > len(kept.elems) {
+		attrs = append(attrs, slog.Bool("truncated", true))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logOrder returns the order in which LogValue sorts elements of type T.
+func logOrder[T comparable]() func(a, b T) bool {
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) bool { return reflect.ValueOf(a).Int() < reflect.ValueOf(b).Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) bool { return reflect.ValueOf(a).Uint() < reflect.ValueOf(b).Uint() }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) bool { return reflect.ValueOf(a).Float() < reflect.ValueOf(b).Float() }
+	case reflect.String:
+		return func(a, b T) bool { return reflect.ValueOf(a).String() < reflect.ValueOf(b).String() }
+	}
+	return func(a, b T) bool { return fmt.Sprint(a) < fmt.Sprint(b) }
+}
+
+// logHeap is a max-heap of the smallest elements seen by LogValue, whose
+// root is the first one to evict.
+type logHeap[T comparable] struct {
+	elems []T
+	less  func(a, b T) bool
+}
+
+func (h *logHeap[T]) Len() int           { return len(h.elems) }
+func (h *logHeap[T]) Less(i, j int) bool { return h.less(h.elems[j], h.elems[i]) }
+func (h *logHeap[T]) Swap(i, j int)      { h.elems[i], h.elems[j] = h.elems[j], h.elems[i] }
+func (h *logHeap[T]) Push(x any)         { h.elems = append(h.elems, x.(T)) }
+
+func (h *logHeap[T]) Pop() any {
+	last := h.elems[len(h.elems)-1]
+	h.elems = h.elems[:len(h.elems)-1]
+	return last
+}
+
+// LogValue implements slog.LogValuer, see LogValue.
+func (s threadUnsafeSet[T]) LogValue() slog.Value {
+	return LogValue[T](&s, DefaultLogValueLimit)
+}
+
+// LogValue implements slog.LogValuer, see LogValue.
+func (t *threadSafeSet[T]) LogValue() slog.Value {
+	return LogValue[T](t, DefaultLogValueLimit)
+}
+
+// LogValue implements slog.LogValuer, see LogValue.
+func (s *sortedSet[T]) LogValue() slog.Value {
+	return LogValue[T](s, DefaultLogValueLimit)
+}
@@ -0,0 +1,77 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_LogValue(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("loaded", "ids", ctor(makeRange(100)...))
+
+		want := `"ids":{"cardinality":100,"elements":[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31],"truncated":true}`
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %s, got: %s", want, buf.String())
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+	t.Run("Sorted", func(t *testing.T) {
+		test(t, func(vals ...int) Set[int] { return NewSortedSet(vals...) })
+	})
+}
+
+func Test_LogValueLimit(t *testing.T) {
+	v := LogValue[int](NewSet(10, -3, 2, 7), 2)
+	if got := v.String(); got != "[cardinality=4 elements=[-3 2] truncated=true]" {
+		t.Errorf("Expected the two smallest elements, got: %s", got)
+	}
+
+	v = LogValue[string](NewSet("b", "a"), 10)
+	if got := v.String(); got != "[cardinality=2 elements=[a b]]" {
+		t.Errorf("Expected every element without truncation, got: %s", got)
+	}
+
+	type pair struct{ a, b int }
+	v = LogValue[pair](NewSet(pair{2, 0}, pair{1, 9}), 0)
+	if got := v.String(); got != "[cardinality=2 elements=[] truncated=true]" {
+		t.Errorf("Expected no elements, got: %s", got)
+	}
+}
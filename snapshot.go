@@ -0,0 +1,297 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidSnapshot is reported when decoding data that is not a snapshot
+// written by WriteSnapshot, or a truncated one.
+var ErrInvalidSnapshot = errors.New("mapset: invalid snapshot")
+
+// ErrUnsupportedVersion is reported when decoding a snapshot written in a
+// format version newer than this package supports.
+var ErrUnsupportedVersion = errors.New("mapset: unsupported snapshot version")
+
+// snapshotMagic starts every snapshot.
+const snapshotMagic = "MSET"
+
+// snapshotVersion is the current snapshot format version. It only changes
+// when the layout of the payload changes incompatibly; header fields can
+// be added without bumping it, since readers skip the ones they don't
+// know about.
+const snapshotVersion = 1
+
+// maxSnapshotHeader bounds the length of snapshot headers, which hold a
+// few varints, so that a corrupt length doesn't make readers allocate
+// huge buffers.
+const maxSnapshotHeader = 1 << 12
+
+// snapshotEncoding identifies how the elements of a snapshot are encoded.
+type snapshotEncoding uint64
+
+const (
+	snapshotGob snapshotEncoding = 1
+	// snapshotIndexed encodes the elements in compressed blocks, preceded
+	// by an index of the blocks, see WriteIndexedSnapshot.
+	snapshotIndexed snapshotEncoding = 2
+)
+
+// Format identifies a serialization format of sets.
+type Format int
+
+const (
+	// FormatUnknown is any format not listed below.
+	FormatUnknown Format = iota
+	// FormatJSON is a JSON array, as written by MarshalJSON or EncodeJSON.
+	FormatJSON
+	// FormatSnapshot is the binary format written by WriteSnapshot.
+	FormatSnapshot
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatSnapshot:
+		return "snapshot"
+	}
+	return "unknown"
+}
+
+// snapshotHeader is the length-prefixed header that follows the magic and
+// the version of a snapshot.
+type snapshotHeader struct {
+	encoding snapshotEncoding
+	count    uint64
+}
+
+// WriteSnapshot writes the elements of s to w in the versioned binary
+// snapshot format, which starts with a magic number, the format version
+// and a header describing the payload, so that snapshots persisted by a
+// deployment can still be read after the package is upgraded. The elements
+// are encoded with encoding/gob.
+func WriteSnapshot[T comparable](w io.Writer, s Set[T]) error {
+	bw := bufio.NewWriter(w)
+
+	// the header holds the number of elements, so unlike EncodeJSON the
+	// elements are copied at once, from a consistent view of the set
+	elems := s.ToSlice()
+	h := snapshotHeader{encoding: snapshotGob, count: uint64(len(elems))}
+	if err := writeSnapshotHeader(bw, h); err != nil {
+		return err
+	}
+
+	enc := gob.NewEncoder(bw)
+	for i := range elems {
+		if err := enc.Encode(&elems[i]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSnapshotHeader(w *bufio.Writer, h snapshotHeader) error {
+	var buf [binary.MaxVarintLen64]byte
+	var header []byte
+	for _, field := range []uint64{uint64(h.encoding), h.count} {
+		n := binary.PutUvarint(buf[:], field)
+		header = append(header, buf[:n]...)
+	}
+
+	if _, err := w.WriteString(snapshotMagic); err != nil {
+		return err
+	}
+	for _, field := range []uint64{snapshotVersion, uint64(len(header))} {
+		n := binary.PutUvarint(buf[:], field)
+		if _, err := w.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(header)
+	return err
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot or
+// WriteIndexedSnapshot from r and adds its elements to s, in chunks. It
+// reports ErrInvalidSnapshot if r doesn't hold a snapshot and
+// ErrUnsupportedVersion if the snapshot was written by a newer,
+// incompatible version of this package. Header fields added by newer
+// versions are ignored.
+func ReadSnapshot[T comparable](r io.Reader, s Set[T]) error {
+	br := bufio.NewReader(r)
+	h, err := readSnapshotHeader(br)
+	if err != nil {
+		return err
+	}
+	switch h.encoding {
+	case snapshotGob:
+	case snapshotIndexed:
+		return readIndexedSnapshot(br, h, s)
+	default:
+		return fmt.Errorf("%w: unknown encoding %d", ErrUnsupportedVersion, h.encoding)
+	}
+
+	dec := gob.NewDecoder(br)
+	chunk := make([]T, 0, streamChunkSize)
+	for i := uint64(0); i < h.count; i++ {
+		var elem T
+		if err := dec.Decode(&elem); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		chunk = append(chunk, elem)
+		if len(chunk) == streamChunkSize {
+			s.Append(chunk...)
+			chunk = chunk[:0]
+		}
+	}
+	s.Append(chunk...)
+
+	return nil
+}
+
+func readSnapshotHeader(r *bufio.Reader) (snapshotHeader, error) {
+	var h snapshotHeader
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != snapshotMagic {
+		return h, ErrInvalidSnapshot
+	}
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return h, ErrInvalidSnapshot
+	}
+	if version > snapshotVersion {
+		return h, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil || length > maxSnapshotHeader {
+		return h, ErrInvalidSnapshot
+	}
+	header := make([]byte, length)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return h, ErrInvalidSnapshot
+	}
+
+	hr := bytes.NewReader(header)
+	for _, field := range []*uint64{(*uint64)(&h.encoding), &h.count} {
+		if *field, err = binary.ReadUvarint(hr); err != nil {
+			return h, ErrInvalidSnapshot
+		}
+	}
+	// the remaining bytes of the header are fields of newer versions
+	return h, nil
+}
+
+// detectWindow is the number of bytes inspected by DetectFormat.
+const detectWindow = 512
+
+// DetectFormat reports the format of the serialized set at the start of
+// r, without consuming it, so that data persisted in different formats
+// over time can be decoded with the right function. It inspects at most
+// the first 512 bytes of r: JSON arrays are recognized by their opening
+// bracket, after JSON whitespace, followed by a valid first token, so that
+// text starting with a bracket isn't mistaken for JSON. Arrays preceded by
+// more whitespace are reported as FormatUnknown. See also Decode.
+func DetectFormat(r *bufio.Reader) (Format, error) {
+	n := detectWindow
+	if n > r.Size() {
+		n = r.Size()
+	}
+	b, err := r.Peek(n)
+	complete := err == io.EOF
+	if err != nil && !complete {
+		return FormatUnknown, err
+	}
+
+	if bytes.HasPrefix(b, []byte(snapshotMagic)) {
+		return FormatSnapshot, nil
+	}
+
+	i := 0
+	for i < len(b) && isJSONSpace(b[i]) {
+		i++
+	}
+	if i == len(b) || b[i] != '[' {
+		return FormatUnknown, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b[i:]))
+	dec.Token() // the opening bracket
+	if _, err := dec.Token(); err != nil {
+		var syntaxErr *json.SyntaxError
+		if !complete && !errors.As(err, &syntaxErr) {
+			// the first token doesn't fit in the window
+			return FormatJSON, nil
+		}
+		return FormatUnknown, nil
+	}
+	return FormatJSON, nil
+}
+
+// isJSONSpace reports whether c is whitespace in JSON, which unlike
+// unicode.IsSpace excludes the other Unicode spaces.
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// Decode reads a set serialized in any of the formats recognized by
+// DetectFormat from r and adds its elements to s.
+func Decode[T comparable](r io.Reader, s Set[T]) error {
+	br := bufio.NewReader(r)
+	format, err := DetectFormat(br)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatJSON:
+		return DecodeJSON(br, s)
+	case FormatSnapshot:
+		return ReadSnapshot(br, s)
+	}
+	return errors.New("mapset: unknown serialization format")
+}
+
+// marshalBinary encodes s as a snapshot, for the MarshalBinary methods.
+func marshalBinary[T comparable](s Set[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,256 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_Snapshot(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		withStreamChunkSize(t, 7)
+
+		s := ctor(nrand(N)...)
+		var buf bytes.Buffer
+		if err := WriteSnapshot(&buf, s); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte("MSET\x01")) {
+			t.Errorf("the snapshot should start with the magic and the version, got %q", buf.Bytes()[:5])
+		}
+
+		decoded := ctor()
+		if err := ReadSnapshot(&buf, decoded); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !decoded.Equal(s) {
+			t.Errorf("Expected no difference, got: %v", decoded.SymmetricDifference(s))
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+// rawSnapshot builds a snapshot of strings with the given version and raw
+// header fields.
+func rawSnapshot(t *testing.T, version uint64, fields []uint64, elems ...string) []byte {
+	uvarint := func(b []byte, v uint64) []byte {
+		var buf [binary.MaxVarintLen64]byte
+		return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+	}
+
+	var header []byte
+	for _, f := range fields {
+		header = uvarint(header, f)
+	}
+
+	b := []byte(snapshotMagic)
+	b = uvarint(b, version)
+	b = uvarint(b, uint64(len(header)))
+	b = append(b, header...)
+
+	buf := bytes.NewBuffer(b)
+	enc := gob.NewEncoder(buf)
+	for _, e := range elems {
+		if err := enc.Encode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func Test_ReadSnapshotForwardCompatible(t *testing.T) {
+	// a header with a field this version doesn't know about
+	data := rawSnapshot(t, snapshotVersion, []uint64{uint64(snapshotGob), 2, 42}, "a", "b")
+
+	s := NewSet[string]()
+	if err := ReadSnapshot(bytes.NewReader(data), s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(NewSet("a", "b")) {
+		t.Errorf("unexpected set contents: %v", s)
+	}
+}
+
+func Test_ReadSnapshotErrors(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		err  error
+	}{
+		"empty":         {nil, ErrInvalidSnapshot},
+		"not snapshot":  {[]byte(`["a"]`), ErrInvalidSnapshot},
+		"newer version": {rawSnapshot(t, snapshotVersion+1, []uint64{uint64(snapshotGob), 0}), ErrUnsupportedVersion},
+		"unknown codec": {rawSnapshot(t, snapshotVersion, []uint64{99, 0}), ErrUnsupportedVersion},
+		"short header":  {rawSnapshot(t, snapshotVersion, []uint64{uint64(snapshotGob)}), ErrInvalidSnapshot},
+		"truncated":     {rawSnapshot(t, snapshotVersion, []uint64{uint64(snapshotGob), 3}, "a", "b"), ErrInvalidSnapshot},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ReadSnapshot(bytes.NewReader(tt.data), NewSet[string]())
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got: %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func Test_DetectFormat(t *testing.T) {
+	var snapshot bytes.Buffer
+	if err := WriteSnapshot[string](&snapshot, NewSet("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		data   string
+		format Format
+	}{
+		"snapshot":  {snapshot.String(), FormatSnapshot},
+		"json":      {`["a"]`, FormatJSON},
+		"json ws":   {" \n\t[\"a\"]", FormatJSON},
+		"lines":     {"a\nb\n", FormatUnknown},
+		"empty":     {"", FormatUnknown},
+		"only ws":   {"   ", FormatUnknown},
+		"huge ws":   {strings.Repeat(" ", 10000) + "[]", FormatUnknown},
+		"short mag": {"MS", FormatUnknown},
+		"brackets":  {"[a]\n[b]\n", FormatUnknown},
+		"nbsp":      {"\u00a0[\"a\"]", FormatUnknown},
+		"bracket":   {"[", FormatUnknown},
+		"long elem": {`["` + strings.Repeat("a", 1000) + `"]`, FormatJSON},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.data))
+			format, err := DetectFormat(r)
+			if err != nil {
+				t.Fatalf("Error should be nil: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("Expected %v, got %v", tt.format, format)
+			}
+			if rest, _ := r.Peek(len(tt.data)); len(tt.data) <= r.Size() && string(rest) != tt.data {
+				t.Error("DetectFormat should not consume the reader")
+			}
+		})
+	}
+}
+
+func Test_Decode(t *testing.T) {
+	expected := NewSet("a", "b", "c")
+
+	var snapshot bytes.Buffer
+	if err := WriteSnapshot(&snapshot, expected); err != nil {
+		t.Fatal(err)
+	}
+	jsonData, err := expected.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{snapshot.Bytes(), jsonData} {
+		s := NewSet[string]()
+		if err := Decode(bytes.NewReader(data), s); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !s.Equal(expected) {
+			t.Errorf("Expected no difference, got: %v", s.SymmetricDifference(expected))
+		}
+	}
+
+	if err := Decode(strings.NewReader("a\nb\n"), NewSet[string]()); err == nil {
+		t.Error("decoding an unknown format should fail")
+	}
+}
+
+func Test_MarshalBinary(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		s := ctor("a", "b", "c")
+		data, err := s.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+
+		decoded := ctor("d")
+		if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !decoded.Equal(ctor("a", "b", "c", "d")) {
+			t.Errorf("unexpected decoded set: %v", decoded)
+		}
+
+		if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("garbage")); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("expected ErrInvalidSnapshot, got %v", err)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_GobSet(t *testing.T) {
+	type config struct {
+		Name  string
+		Tags  Set[string]
+		Ports Set[int]
+	}
+	// the fields hold interfaces, whose implementations must be registered
+	gob.Register(NewSet[string]())
+	gob.Register(NewThreadUnsafeSet[int]())
+
+	in := config{Name: "svc", Tags: NewSet("a", "b"), Ports: NewThreadUnsafeSet(80, 443)}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	out := config{Tags: NewSet[string](), Ports: NewThreadUnsafeSet[int]()}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if out.Name != "svc" || !out.Tags.Equal(in.Tags) || !out.Ports.Equal(in.Ports) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+	if out.Tags.Add("c"); out.Tags.Cardinality() != 3 {
+		t.Errorf("the decoded set should be usable, got %v", out.Tags)
+	}
+}
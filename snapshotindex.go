@@ -0,0 +1,441 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"sync"
+)
+
+// SnapshotCodec identifies the compression of the blocks of an indexed
+// snapshot, see WriteIndexedSnapshotCodec.
+type SnapshotCodec uint64
+
+const (
+	// CodecDeflate compresses the blocks with DEFLATE. It is built in and
+	// used by WriteIndexedSnapshot.
+	CodecDeflate SnapshotCodec = 1
+
+	// CodecZstd compresses the blocks with Zstandard, which decompresses
+	// faster than DEFLATE. It must be registered with RegisterSnapshotCodec
+	// by the program, which keeps the dependency out of this package.
+	CodecZstd SnapshotCodec = 2
+)
+
+// ErrUnknownCodec is reported when an indexed snapshot is written or read
+// with a SnapshotCodec that was not registered.
+var ErrUnknownCodec = errors.New("mapset: unknown snapshot codec")
+
+type snapshotCodec struct {
+	newWriter func(io.Writer) (io.WriteCloser, error)
+	newReader func(io.Reader) (io.Reader, error)
+}
+
+var (
+	snapshotCodecsMu sync.RWMutex
+	snapshotCodecs   = map[SnapshotCodec]snapshotCodec{
+		CodecDeflate: {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return flate.NewWriter(w, flate.DefaultCompression)
+			},
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return flate.NewReader(r), nil
+			},
+		},
+	}
+)
+
+// RegisterSnapshotCodec makes the blocks of indexed snapshots compressible
+// with c, newWriter compressing and newReader decompressing them. If the
+// reader returned by newReader is an io.Closer, it is closed once the block
+// is read. For instance with github.com/klauspost/compress/zstd:
+//
+//	mapset.RegisterSnapshotCodec(mapset.CodecZstd, func(w io.Writer) (io.WriteCloser, error) {
+//		return zstd.NewWriter(w)
+//	}, func(r io.Reader) (io.Reader, error) {
+//		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+//		if err != nil {
+//			return nil, err
+//		}
+//		return d.IOReadCloser(), nil
+//	})
+func RegisterSnapshotCodec(c SnapshotCodec, newWriter func(io.Writer) (io.WriteCloser, error), newReader func(io.Reader) (io.Reader, error)) {
+	snapshotCodecsMu.Lock()
+	defer snapshotCodecsMu.Unlock()
+
+	snapshotCodecs[c] = snapshotCodec{newWriter: newWriter, newReader: newReader}
+}
+
+func lookupSnapshotCodec(c SnapshotCodec) (snapshotCodec, error) {
+	snapshotCodecsMu.RLock()
+	defer snapshotCodecsMu.RUnlock()
+
+	codec, found := snapshotCodecs[c]
+	if !found {
+		return codec, fmt.Errorf("%w %d", ErrUnknownCodec, c)
+	}
+	return codec, nil
+}
+
+// indexedBlockSize is the number of elements of every block of an indexed
+// snapshot but the last.
+var indexedBlockSize = 1024
+
+// snapshotBlock is an entry of the index of an indexed snapshot. Blocks
+// follow the index in order, so their offsets are the sums of the lengths
+// of the previous blocks.
+type snapshotBlock struct {
+	// first is the smallest element hash of the block.
+	first  uint64
+	count  uint64
+	length uint64
+}
+
+// WriteIndexedSnapshot writes the elements of s to w in the snapshot format,
+// like WriteSnapshot, but compressed in blocks with DEFLATE and ordered by
+// hash behind an index of the blocks. Indexed snapshots are read back by
+// ReadSnapshot and Decode like other snapshots, and membership can also be
+// checked against the file without loading it, with ContainsInSnapshot or
+// OpenIndexedSnapshot, for instance before the in-memory set is warm.
+//
+// Elements are hashed by their JSON encoding, so they must encode to JSON
+// in a deterministic way, like strings, numbers and structs of those.
+func WriteIndexedSnapshot[T comparable](w io.Writer, s Set[T]) error {
+	return WriteIndexedSnapshotCodec(w, s, CodecDeflate)
+}
+
+// WriteIndexedSnapshotCodec writes an indexed snapshot like
+// WriteIndexedSnapshot, compressing the blocks with c, for instance
+// CodecZstd. The codec is recorded in the snapshot, and must be registered
+// in the programs reading it too.
+func WriteIndexedSnapshotCodec[T comparable](w io.Writer, s Set[T], c SnapshotCodec) error {
+	codec, err := lookupSnapshotCodec(c)
+	if err != nil {
+		return err
+	}
+
+	elems := s.ToSlice()
+	hashes := make([]uint64, len(elems))
+	for i, elem := range elems {
+		hashes[i] = elementHash(elem)
+	}
+	sort.Sort(byHash[T]{elems: elems, hashes: hashes})
+
+	// blocks are compressed before writing the index, which holds their
+	// lengths
+	var blocks []snapshotBlock
+	var data bytes.Buffer
+	for start := 0; start < len(elems); start += indexedBlockSize {
+		end := start + indexedBlockSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		n := data.Len()
+		if err := writeSnapshotBlock(&data, codec, elems[start:end]); err != nil {
+			return err
+		}
+		blocks = append(blocks, snapshotBlock{
+			first:  hashes[start],
+			count:  uint64(end - start),
+			length: uint64(data.Len() - n),
+		})
+	}
+
+	bw := bufio.NewWriter(w)
+	h := snapshotHeader{encoding: snapshotIndexed, count: uint64(len(elems))}
+	if err := writeSnapshotHeader(bw, h); err != nil {
+		return err
+	}
+	var buf [binary.MaxVarintLen64]byte
+	for _, field := range []uint64{uint64(c), uint64(len(blocks))} {
+		n := binary.PutUvarint(buf[:], field)
+		if _, err := bw.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+	for _, b := range blocks {
+		binary.BigEndian.PutUint64(buf[:8], b.first)
+		if _, err := bw.Write(buf[:8]); err != nil {
+			return err
+		}
+		for _, field := range []uint64{b.count, b.length} {
+			n := binary.PutUvarint(buf[:], field)
+			if _, err := bw.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := data.WriteTo(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func writeSnapshotBlock[T comparable](w io.Writer, codec snapshotCodec, elems []T) error {
+	zw, err := codec.newWriter(w)
+	if err != nil {
+		return err
+	}
+	enc := gob.NewEncoder(zw)
+	for i := range elems {
+		if err := enc.Encode(&elems[i]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// readSnapshotIndex reads the codec and the index of an indexed snapshot,
+// which follow its header. The blocks are checked to hold count elements in
+// total.
+func readSnapshotIndex(r io.ByteReader, count uint64) (snapshotCodec, []snapshotBlock, error) {
+	var codec snapshotCodec
+	c, err := binary.ReadUvarint(r)
+	if err != nil {
+		return codec, nil, ErrInvalidSnapshot
+	}
+	if codec, err = lookupSnapshotCodec(SnapshotCodec(c)); err != nil {
+		return codec, nil, err
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return codec, nil, ErrInvalidSnapshot
+	}
+
+	var blocks []snapshotBlock
+	total := uint64(0)
+	for i := uint64(0); i < n; i++ {
+		var b snapshotBlock
+		for j := 0; j < 8; j++ {
+			c, err := r.ReadByte()
+			if err != nil {
+				return codec, nil, ErrInvalidSnapshot
+			}
+			b.first = b.first<<8 | uint64(c)
+		}
+		for _, field := range []*uint64{&b.count, &b.length} {
+			if *field, err = binary.ReadUvarint(r); err != nil {
+				return codec, nil, ErrInvalidSnapshot
+			}
+		}
+		if total += b.count; total > count || total < b.count {
+			return codec, nil, ErrInvalidSnapshot
+		}
+		blocks = append(blocks, b)
+	}
+	if total != count {
+		return codec, nil, ErrInvalidSnapshot
+	}
+	return codec, blocks, nil
+}
+
+// readSnapshotBlock decodes the elements of a block from r, calling cb
+// with every one of them until it returns true.
+func readSnapshotBlock[T comparable](r io.Reader, codec snapshotCodec, b snapshotBlock, cb func(T) bool) error {
+	zr, err := codec.newReader(io.LimitReader(r, int64(b.length)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if c, ok := zr.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	dec := gob.NewDecoder(zr)
+	for i := uint64(0); i < b.count; i++ {
+		var elem T
+		if err := dec.Decode(&elem); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		if cb(elem) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// readIndexedSnapshot reads the index and the blocks of an indexed snapshot
+// from r, positioned after its header, and adds the elements to s block by
+// block.
+func readIndexedSnapshot[T comparable](r *bufio.Reader, h snapshotHeader, s Set[T]) error {
+	codec, blocks, err := readSnapshotIndex(r, h.count)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range blocks {
+		var chunk []T
+		lr := &io.LimitedReader{R: r, N: int64(b.length)}
+		err := readSnapshotBlock(lr, codec, b, func(elem T) bool {
+			chunk = append(chunk, elem)
+			return false
+		})
+		if err != nil {
+			return err
+		}
+		// skip what the decompressor didn't consume, so that the next
+		// block is read from its start
+		if _, err := io.Copy(io.Discard, lr); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		if lr.N > 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidSnapshot, io.ErrUnexpectedEOF)
+		}
+		s.Append(chunk...)
+	}
+	return nil
+}
+
+// IndexedSnapshot checks the membership of elements in an indexed snapshot
+// written by WriteIndexedSnapshot without loading it: only its index is held
+// in memory, and every lookup reads and decompresses a single block. It is
+// safe for concurrent use if the underlying io.ReaderAt is, as os.File is.
+type IndexedSnapshot[T comparable] struct {
+	r      io.ReaderAt
+	count  uint64
+	codec  snapshotCodec
+	blocks []snapshotBlock
+	// offsets holds the offset of every block in r.
+	offsets []int64
+}
+
+// OpenIndexedSnapshot reads the header and the index of the indexed
+// snapshot held by r. It reports ErrInvalidSnapshot if r doesn't hold an
+// indexed snapshot, and ErrUnknownCodec if its codec is not registered.
+func OpenIndexedSnapshot[T comparable](r io.ReaderAt) (*IndexedSnapshot[T], error) {
+	cr := &countingReader{r: io.NewSectionReader(r, 0, 1<<63-1)}
+	br := bufio.NewReader(cr)
+	h, err := readSnapshotHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if h.encoding != snapshotIndexed {
+		return nil, fmt.Errorf("%w: not an indexed snapshot", ErrInvalidSnapshot)
+	}
+	codec, blocks, err := readSnapshotIndex(br, h.count)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make([]int64, len(blocks))
+	off := cr.n - int64(br.Buffered())
+	for i, b := range blocks {
+		offsets[i] = off
+		off += int64(b.length)
+	}
+	return &IndexedSnapshot[T]{r: r, count: h.count, codec: codec, blocks: blocks, offsets: offsets}, nil
+}
+
+// Cardinality returns the number of elements in the snapshot.
+func (s *IndexedSnapshot[T]) Cardinality() int {
+	return int(s.count)
+}
+
+// Contains returns whether v is in the snapshot. It only reads the blocks
+// that may hold v, usually a single one.
+func (s *IndexedSnapshot[T]) Contains(v T) (bool, error) {
+	h := elementHash(v)
+	// the blocks that may hold v start with the last one whose first hash
+	// is smaller than h, since elements with the hash h may be split
+	// across blocks
+	i := sort.Search(len(s.blocks), func(i int) bool { return s.blocks[i].first >= h })
+	if i > 0 {
+		i--
+	}
+
+	for ; i < len(s.blocks) && s.blocks[i].first <= h; i++ {
+		b := s.blocks[i]
+		found, past := false, false
+		r := io.NewSectionReader(s.r, s.offsets[i], int64(b.length))
+		err := readSnapshotBlock(r, s.codec, b, func(elem T) bool {
+			eh := elementHash(elem)
+			found = eh == h && elem == v
+			past = eh > h
+			return found || past
+		})
+		if err != nil || found || past {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// ContainsInSnapshot returns whether v is in the indexed snapshot written by
+// WriteIndexedSnapshot held by r, only reading its index and the block that
+// may hold v. Use OpenIndexedSnapshot to check several elements.
+func ContainsInSnapshot[T comparable](r io.ReaderAt, v T) (bool, error) {
+	s, err := OpenIndexedSnapshot[T](r)
+	if err != nil {
+		return false, err
+	}
+	return s.Contains(v)
+}
+
+// elementHash returns the 64-bit FNV-1a hash of the canonical encoding of
+// v, which is stable across processes.
+func elementHash[T comparable](v T) uint64 {
+	h := fnv.New64a()
+	h.Write(canonicalElement(v))
+	return h.Sum64()
+}
+
+// byHash sorts elements by their hash.
+type byHash[T comparable] struct {
+	elems  []T
+	hashes []uint64
+}
+
+func (b byHash[T]) Len() int { return len(b.elems) }
+
+func (b byHash[T]) Less(i, j int) bool { return b.hashes[i] < b.hashes[j] }
+
+func (b byHash[T]) Swap(i, j int) {
+	b.elems[i], b.elems[j] = b.elems[j], b.elems[i]
+	b.hashes[i], b.hashes[j] = b.hashes[j], b.hashes[i]
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,175 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withIndexedBlockSize(t *testing.T, size int) {
+	prev := indexedBlockSize
+	indexedBlockSize = size
+	t.Cleanup(func() { indexedBlockSize = prev })
+}
+
+func Test_IndexedSnapshot(t *testing.T) {
+	withIndexedBlockSize(t, 16)
+
+	elems := nrand(N)
+	var buf bytes.Buffer
+	if err := WriteIndexedSnapshot(&buf, NewSet(elems...)); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	data := buf.Bytes()
+
+	s := NewThreadUnsafeSet[int]()
+	if err := Decode(bytes.NewReader(data), s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !s.Equal(NewThreadUnsafeSet(elems...)) {
+		t.Errorf("Expected the snapshot to hold %d elements, got %d", N, s.Cardinality())
+	}
+
+	idx, err := OpenIndexedSnapshot[int](bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if idx.Cardinality() != s.Cardinality() {
+		t.Errorf("Expected the snapshot to hold %d elements, got %d", s.Cardinality(), idx.Cardinality())
+	}
+	for _, v := range elems {
+		if found, err := idx.Contains(v); err != nil || !found {
+			t.Fatalf("Expected %d to be in the snapshot, got %v, %v", v, found, err)
+		}
+	}
+	for _, v := range nrand(100) {
+		if found, err := idx.Contains(v); err != nil || found != s.ContainsOne(v) {
+			t.Fatalf("Expected Contains(%d) to be %v, got %v, %v", v, s.ContainsOne(v), found, err)
+		}
+	}
+}
+
+func Test_ContainsInSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.snap")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	defer f.Close()
+	if err := WriteIndexedSnapshot(f, NewSet("alice", "bob", "carol")); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	for v, want := range map[string]bool{"alice": true, "carol": true, "dave": false, "": false} {
+		if found, err := ContainsInSnapshot(f, v); err != nil || found != want {
+			t.Errorf("Expected ContainsInSnapshot(%q) to be %v, got %v, %v", v, want, found, err)
+		}
+	}
+
+	var empty bytes.Buffer
+	if err := WriteIndexedSnapshot(&empty, NewSet[string]()); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if found, err := ContainsInSnapshot(bytes.NewReader(empty.Bytes()), "alice"); err != nil || found {
+		t.Errorf("Expected an empty snapshot to hold nothing, got %v, %v", found, err)
+	}
+}
+
+func Test_IndexedSnapshotErrors(t *testing.T) {
+	var plain bytes.Buffer
+	if err := WriteSnapshot(&plain, NewSet("a")); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if _, err := OpenIndexedSnapshot[string](bytes.NewReader(plain.Bytes())); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("Expected %v for a plain snapshot, got: %v", ErrInvalidSnapshot, err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteIndexedSnapshot(&buf, NewSet("a", "b", "c")); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	data := buf.Bytes()
+	for _, n := range []int{len(data) - 1, len(data) / 2, 12} {
+		err := ReadSnapshot(bytes.NewReader(data[:n]), NewSet[string]())
+		if !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("Expected %v for %d bytes of %d, got: %v", ErrInvalidSnapshot, n, len(data), err)
+		}
+	}
+}
+
+// nopWriteCloser stores blocks uncompressed for Test_IndexedSnapshotCodec.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func Test_IndexedSnapshotCodec(t *testing.T) {
+	const codecStored SnapshotCodec = 100
+
+	var buf bytes.Buffer
+	if err := WriteIndexedSnapshotCodec(&buf, NewSet("a", "b"), codecStored); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("Expected %v before the codec is registered, got: %v", ErrUnknownCodec, err)
+	}
+
+	RegisterSnapshotCodec(codecStored, func(w io.Writer) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	}, func(r io.Reader) (io.Reader, error) {
+		return r, nil
+	})
+	t.Cleanup(func() {
+		snapshotCodecsMu.Lock()
+		delete(snapshotCodecs, codecStored)
+		snapshotCodecsMu.Unlock()
+	})
+
+	if err := WriteIndexedSnapshotCodec(&buf, NewSet("a", "b"), codecStored); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	data := buf.Bytes()
+	if !bytes.Contains(data, []byte("a")) {
+		t.Error("Expected the blocks to be stored as is")
+	}
+	if found, err := ContainsInSnapshot(bytes.NewReader(data), "b"); err != nil || !found {
+		t.Errorf("Expected b to be in the snapshot, got %v, %v", found, err)
+	}
+	s := NewSet[string]()
+	if err := ReadSnapshot(bytes.NewReader(data), s); err != nil || !s.Equal(NewSet("a", "b")) {
+		t.Errorf("Expected {a, b}, got %v, %v", s, err)
+	}
+
+	snapshotCodecsMu.Lock()
+	delete(snapshotCodecs, codecStored)
+	snapshotCodecsMu.Unlock()
+	if _, err := OpenIndexedSnapshot[string](bytes.NewReader(data)); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("Expected %v for an unregistered codec, got: %v", ErrUnknownCodec, err)
+	}
+}
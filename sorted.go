@@ -30,13 +30,86 @@ package mapset
 
 import (
 	"cmp"
+	"fmt"
 	"slices"
+	"strings"
 )
 
 // Sorted returns a sorted slice of a set of any ordered type in ascending order.
 // When sorting floating-point numbers, NaNs are ordered before other values.
 func Sorted[E cmp.Ordered](set Set[E]) []E {
+	if _, ok := unwrapSet(set).(*sortedSet[E]); ok {
+		return set.ToSlice()
+	}
 	s := set.ToSlice()
 	slices.Sort(s)
 	return s
 }
+
+// StringSorted returns the same representation of a set as String, but
+// with the elements in ascending order as returned by Sorted, so that the
+// output is deterministic, for instance in golden files or logs.
+func StringSorted[E cmp.Ordered](set Set[E]) string {
+	elems := Sorted(set)
+	items := make([]string, len(elems))
+	for i, elem := range elems {
+		items[i] = fmt.Sprintf("%v", elem)
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// SortedFunc returns a slice of the elements of a set sorted in ascending
+// order by cmp, which returns a negative number when a < b, a positive
+// number when a > b and zero when they are equivalent. It suits element
+// types that aren't ordered, such as structs.
+func SortedFunc[E comparable](set Set[E], cmp func(a, b E) int) []E {
+	s := set.ToSlice()
+	slices.SortFunc(s, cmp)
+	return s
+}
+
+// SortedElements returns an iterator that yields the elements of the set in
+// ascending order, see Sorted. Starting with Go 1.23, users can use a for
+// loop to iterate over it. The elements are copied and sorted when the
+// iteration starts, so the set may be modified while iterating.
+func SortedElements[E cmp.Ordered](set Set[E]) func(func(element E) bool) {
+	return func(yield func(element E) bool) {
+		for _, elem := range Sorted(set) {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// Min returns the smallest element of the set, or false if the set is
+// empty. Like Sorted, it orders NaNs before other values. The set is
+// scanned with Each, so thread-safe sets are read under a single lock and
+// no slice of the elements is allocated; sorted sets answer directly.
+func Min[T cmp.Ordered](s Set[T]) (T, bool) {
+	if sorted, ok := unwrapSet(s).(*sortedSet[T]); ok {
+		return sorted.Min()
+	}
+	return extremum(s, func(a, b T) bool { return cmp.Less(a, b) })
+}
+
+// Max returns the largest element of the set, or false if the set is
+// empty, see Min.
+func Max[T cmp.Ordered](s Set[T]) (T, bool) {
+	if sorted, ok := unwrapSet(s).(*sortedSet[T]); ok {
+		return sorted.Max()
+	}
+	return extremum(s, func(a, b T) bool { return cmp.Less(b, a) })
+}
+
+// extremum returns the element of s that goes before all the others
+// according to less.
+func extremum[T cmp.Ordered](s Set[T], less func(a, b T) bool) (m T, ok bool) {
+	s.Each(func(elem T) bool {
+		if !ok || less(elem, m) {
+			m, ok = elem, true
+		}
+		return false
+	})
+	return m, ok
+}
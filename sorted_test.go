@@ -29,6 +29,7 @@ SOFTWARE.
 package mapset
 
 import (
+	"math"
 	"testing"
 )
 
@@ -59,3 +60,79 @@ func Test_Sorted(t *testing.T) {
 		test(t, NewThreadUnsafeSet[string])
 	})
 }
+
+func Test_MinMax(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		if _, ok := Min(ctor()); ok {
+			t.Error("expected no minimum of an empty set")
+		}
+		if _, ok := Max(ctor()); ok {
+			t.Error("expected no maximum of an empty set")
+		}
+
+		s := ctor(5, -3, 12, 0)
+		if v, ok := Min(s); !ok || v != -3 {
+			t.Errorf("Expected -3, got: %v", v)
+		}
+		if v, ok := Max(s); !ok || v != 12 {
+			t.Errorf("Expected 12, got: %v", v)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+	t.Run("Sorted", func(t *testing.T) {
+		test(t, func(vals ...int) Set[int] { return NewSortedSet(vals...) })
+	})
+}
+
+func Test_MinMaxNaN(t *testing.T) {
+	s := NewSet(1.5, math.NaN(), -2)
+	if v, ok := Min(s); !ok || !math.IsNaN(v) {
+		t.Errorf("expected NaN to be the minimum like in Sorted, got %v", v)
+	}
+	if v, ok := Max(s); !ok || v != 1.5 {
+		t.Errorf("Expected 1.5, got: %v", v)
+	}
+}
+
+func Test_StringSorted(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		if s := StringSorted(ctor(10, -1, 3, 2)); s != "Set{-1, 2, 3, 10}" {
+			t.Errorf("Expected Set{-1, 2, 3, 10}, got: %s", s)
+		}
+		if s := StringSorted(ctor()); s != "Set{}" {
+			t.Errorf("Expected Set{}, got: %s", s)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_SortedFunc(t *testing.T) {
+	type point struct{ x, y int }
+	s := NewSet(point{2, 1}, point{1, 5}, point{1, 2})
+
+	sorted := SortedFunc(s, func(a, b point) int {
+		if a.x != b.x {
+			return a.x - b.x
+		}
+		return a.y - b.y
+	})
+	expected := []point{{1, 2}, {1, 5}, {2, 1}}
+	for i := range expected {
+		if sorted[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected, sorted)
+			break
+		}
+	}
+}
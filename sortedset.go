@@ -0,0 +1,640 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// SortedSet is a Set that keeps its elements in ascending order: ToSlice,
+// Each, Iter, Iterator, String and the encodings all visit the elements in
+// that order, and Pop removes the smallest element.
+//
+// The set operations accept any Set as argument, the other operand being
+// sorted first unless it is a SortedSet too. Sets returned by operations
+// such as Union or Clone are sorted sets. NaNs are considered equal to each
+// other and smaller than any other number.
+type SortedSet[T cmp.Ordered] interface {
+	Set[T]
+
+	// Min returns the smallest element of the set, and false if the set
+	// is empty.
+	Min() (T, bool)
+
+	// Max returns the largest element of the set, and false if the set
+	// is empty.
+	Max() (T, bool)
+
+	// RemoveRange removes the elements between lo and hi, both included,
+	// and returns how many were removed, for instance to prune expired ID
+	// ranges or time windows. It costs O(log n + k) for k removed
+	// elements, however many elements follow the range.
+	RemoveRange(lo, hi T) int
+}
+
+// NewSortedSet creates and returns a new sorted set with the given
+// elements. Operations on the resulting set are thread-safe.
+//
+// The elements are kept in a skip list: looking up, adding or removing an
+// element costs O(log n) on average, and iteration and set operations walk
+// the elements in order without sorting them.
+func NewSortedSet[T cmp.Ordered](vals ...T) SortedSet[T] {
+	return sortedFrom(sortedUnique(slices.Clone(vals)))
+}
+
+type sortedSet[T cmp.Ordered] struct {
+	mu   sync.RWMutex
+	list skipList[T]
+}
+
+// sortedFrom returns a sorted set of elems, which must be in ascending
+// order without duplicates.
+func sortedFrom[T cmp.Ordered](elems []T) *sortedSet[T] {
+	s := &sortedSet[T]{}
+	s.list.reset(elems)
+	return s
+}
+
+// sortedUnique sorts vs in place and removes its duplicates.
+func sortedUnique[T cmp.Ordered](vs []T) []T {
+	slices.Sort(vs)
+	return slices.CompactFunc(vs, func(a, b T) bool { return cmp.Compare(a, b) == 0 })
+}
+
+// sortedElements returns the elements of other in ascending order. It must
+// be called before locking the receiver, since other may be the receiver.
+func sortedElements[T cmp.Ordered](other Set[T]) []T {
+	if o, ok := unwrapSet(other).(*sortedSet[T]); ok {
+		o.mu.RLock()
+		defer o.mu.RUnlock()
+		return o.list.values()
+	}
+	return sortedUnique(other.ToSlice())
+}
+
+func (s *sortedSet[T]) newLike(cardinality int) Set[T] {
+	return &sortedSet[T]{}
+}
+
+// merge walks the sorted slices a and b and returns, in order, the elements
+// only in a if inA is set, only in b if inB is set and in both if inBoth is
+// set.
+func merge[T cmp.Ordered](a, b []T, inA, inB, inBoth bool) []T {
+	out := make([]T, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp.Compare(a[i], b[j]); {
+		case c < 0:
+			if inA {
+				out = append(out, a[i])
+			}
+			i++
+		case c > 0:
+			if inB {
+				out = append(out, b[j])
+			}
+			j++
+		default:
+			if inBoth {
+				out = append(out, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if inA {
+		out = append(out, a[i:]...)
+	}
+	if inB {
+		out = append(out, b[j:]...)
+	}
+	return out
+}
+
+func (s *sortedSet[T]) Add(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list.insert(v)
+}
+
+func (s *sortedSet[T]) Append(v ...T) int {
+	return s.appendAll(v)
+}
+
+func (s *sortedSet[T]) AppendFrom(other Set[T]) int {
+	return s.appendAll(sortedElements(other))
+}
+
+func (s *sortedSet[T]) appendAll(vs []T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, v := range vs {
+		if s.list.insert(v) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *sortedSet[T]) Cardinality() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.len
+}
+
+func (s *sortedSet[T]) Capabilities() Capabilities {
+	return CapThreadSafe | CapOrdered
+}
+
+func (s *sortedSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.list.reset(nil)
+}
+
+func (s *sortedSet[T]) Clone() Set[T] {
+	return sortedFrom(s.ToSlice())
+}
+
+func (s *sortedSet[T]) Contains(v ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, val := range v {
+		if !s.list.contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *sortedSet[T]) ContainsOne(v T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.contains(v)
+}
+
+func (s *sortedSet[T]) ContainsAny(v ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, val := range v {
+		if s.list.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sortedSet[T]) ContainsAnyElement(other Set[T]) bool {
+	o := sortedElements(other)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range o {
+		if s.list.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// combine returns the sorted set of the elements selected by merge.
+func (s *sortedSet[T]) combine(other Set[T], inS, inOther, inBoth bool) Set[T] {
+	o := sortedElements(other)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedFrom(merge(s.list.values(), o, inS, inOther, inBoth))
+}
+
+func (s *sortedSet[T]) Difference(other Set[T]) Set[T] {
+	return s.combine(other, true, false, false)
+}
+
+func (s *sortedSet[T]) Intersect(other Set[T]) Set[T] {
+	return s.combine(other, false, false, true)
+}
+
+func (s *sortedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return s.combine(other, true, true, false)
+}
+
+func (s *sortedSet[T]) Union(other Set[T]) Set[T] {
+	return s.combine(other, true, true, true)
+}
+
+func (s *sortedSet[T]) Equal(other Set[T]) bool {
+	o := sortedElements(other)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.len == len(o) && slices.Compare(s.list.values(), o) == 0
+}
+
+func (s *sortedSet[T]) IsEmpty() bool {
+	return s.Cardinality() == 0
+}
+
+// isSubset reports whether all elements of a are in b.
+func isSubset[T cmp.Ordered](a, b []T) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	j := 0
+	for _, v := range a {
+		for j < len(b) && cmp.Less(b[j], v) {
+			j++
+		}
+		if j == len(b) || cmp.Compare(b[j], v) != 0 {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+func (s *sortedSet[T]) IsProperSubset(other Set[T]) bool {
+	o := sortedElements(other)
+	elems := s.ToSlice()
+	return len(elems) < len(o) && isSubset(elems, o)
+}
+
+func (s *sortedSet[T]) IsProperSuperset(other Set[T]) bool {
+	o := sortedElements(other)
+	elems := s.ToSlice()
+	return len(o) < len(elems) && isSubset(o, elems)
+}
+
+func (s *sortedSet[T]) IsSubset(other Set[T]) bool {
+	return isSubset(s.ToSlice(), sortedElements(other))
+}
+
+func (s *sortedSet[T]) IsSuperset(other Set[T]) bool {
+	return isSubset(sortedElements(other), s.ToSlice())
+}
+
+func (s *sortedSet[T]) Each(cb func(T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.list.each(cb)
+}
+
+func (s *sortedSet[T]) EachErr(cb func(T) error) error {
+	s.mu.RLock()
+	// the deferred unlock also runs while a panic of cb unwinds the stack
+	defer s.mu.RUnlock()
+
+	var err error
+	s.list.each(func(elem T) bool {
+		err = cb(elem)
+		return err != nil
+	})
+	return err
+}
+
+func (s *sortedSet[T]) Filter(cb func(T) bool) Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var filtered []T
+	s.list.each(func(elem T) bool {
+		if cb(elem) {
+			filtered = append(filtered, elem)
+		}
+		return false
+	})
+	return sortedFrom(filtered)
+}
+
+func (s *sortedSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matching, rest []T
+	s.list.each(func(elem T) bool {
+		if pred(elem) {
+			matching = append(matching, elem)
+		} else {
+			rest = append(rest, elem)
+		}
+		return false
+	})
+	return sortedFrom(matching), sortedFrom(rest)
+}
+
+func (s *sortedSet[T]) PartitionN(n int, hash func(T) uint64) []Set[T] {
+	if n <= 0 {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shards := make([][]T, n)
+	for i := range shards {
+		shards[i] = make([]T, 0, s.list.len/n+1)
+	}
+	// appending in order keeps every shard sorted
+	s.list.each(func(elem T) bool {
+		i := hash(elem) % uint64(n)
+		shards[i] = append(shards[i], elem)
+		return false
+	})
+
+	parts := make([]Set[T], n)
+	for i := range shards {
+		parts[i] = sortedFrom(shards[i])
+	}
+	return parts
+}
+
+func (s *sortedSet[T]) All() func(yield func(element T) bool) {
+	return Elements[T](s)
+}
+
+func (s *sortedSet[T]) Iter() <-chan T {
+	elems := s.ToSlice()
+	ch := make(chan T)
+	go func() {
+		for _, elem := range elems {
+			ch <- elem
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (s *sortedSet[T]) Iterator() *Iterator[T] {
+	elems := s.ToSlice()
+	iterator, ch, stopCh := newIterator[T]()
+
+	go func() {
+	L:
+		for _, elem := range elems {
+			select {
+			case <-stopCh:
+				break L
+			case ch <- elem:
+			}
+		}
+		close(ch)
+	}()
+
+	return iterator
+}
+
+func (s *sortedSet[T]) Remove(v T) {
+	s.RemovedWhich(v)
+}
+
+func (s *sortedSet[T]) RemoveAll(i ...T) {
+	s.RemovedWhich(i...)
+}
+
+func (s *sortedSet[T]) RemovedWhich(i ...T) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []T
+	for _, v := range i {
+		if s.list.remove(v) {
+			removed = append(removed, v)
+		}
+	}
+	return removed
+}
+
+func (s *sortedSet[T]) removeIf(pred func(T) bool) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed, kept []T
+	s.list.each(func(elem T) bool {
+		if pred(elem) {
+			removed = append(removed, elem)
+		} else {
+			kept = append(kept, elem)
+		}
+		return false
+	})
+	if len(removed) > 0 {
+		s.list.reset(kept)
+	}
+	return removed
+}
+
+func (s *sortedSet[T]) update(fn func(MutableView[T])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(listView[T]{&s.list})
+}
+
+// listView is the view of the elements of a sorted set.
+type listView[T cmp.Ordered] struct {
+	list *skipList[T]
+}
+
+func (v listView[T]) Add(elem T) bool {
+	return v.list.insert(elem)
+}
+
+func (v listView[T]) Remove(elem T) bool {
+	return v.list.remove(elem)
+}
+
+func (v listView[T]) Contains(elem T) bool {
+	return v.list.contains(elem)
+}
+
+func (v listView[T]) Cardinality() int {
+	return v.list.len
+}
+
+func (s *sortedSet[T]) RemoveRange(lo, hi T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cmp.Less(hi, lo) {
+		return 0
+	}
+	return s.list.removeRange(lo, hi)
+}
+
+func (s *sortedSet[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return setCodec[T]{s}.ReconcileTo(target, add, remove)
+}
+
+func (s *sortedSet[T]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]string, 0, s.list.len)
+	s.list.each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// Format implements fmt.Formatter, see formatSet.
+func (s *sortedSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "NewSortedSet", s.ToSlice())
+}
+
+// Pop removes and returns the smallest element of the set.
+func (s *sortedSet[T]) Pop() (v T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if items := s.list.popFront(1); len(items) == 1 {
+		return items[0], true
+	}
+	return v, false
+}
+
+// PopN removes and returns the n smallest elements of the set.
+func (s *sortedSet[T]) PopN(n int) ([]T, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n <= 0 {
+		return make([]T, 0), 0
+	}
+	items := s.list.popFront(n)
+	return items, len(items)
+}
+
+func (s *sortedSet[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.values()
+}
+
+func (s *sortedSet[T]) Min() (v T, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if n, ok := s.list.first(); ok {
+		return n.value, true
+	}
+	return v, false
+}
+
+func (s *sortedSet[T]) Max() (v T, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if n, ok := s.list.last(); ok {
+		return n.value, true
+	}
+	return v, false
+}
+
+func (s *sortedSet[T]) CanonicalBytes() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	encoded := make([][]byte, 0, s.list.len)
+	s.list.each(func(elem T) bool {
+		encoded = append(encoded, canonicalElement(elem))
+		return false
+	})
+	return canonicalBytes(encoded)
+}
+
+// MarshalJSON creates a JSON array from the set, in ascending order.
+func (s *sortedSet[T]) MarshalJSON() ([]byte, error) {
+	return setCodec[T]{s}.MarshalJSON()
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set.
+func (s *sortedSet[T]) UnmarshalJSON(b []byte) error {
+	return setCodec[T]{s}.UnmarshalJSON(b)
+}
+
+// MarshalBinary encodes the set as a snapshot, in ascending order.
+func (s *sortedSet[T]) MarshalBinary() ([]byte, error) {
+	return setCodec[T]{s}.MarshalBinary()
+}
+
+// UnmarshalBinary adds the elements of a snapshot to the set.
+func (s *sortedSet[T]) UnmarshalBinary(data []byte) error {
+	return setCodec[T]{s}.UnmarshalBinary(data)
+}
+
+// MarshalText encodes the elements of the set as comma-separated text.
+func (s *sortedSet[T]) MarshalText() ([]byte, error) {
+	return setCodec[T]{s}.MarshalText()
+}
+
+// UnmarshalText adds the elements of comma-separated text to the set.
+func (s *sortedSet[T]) UnmarshalText(text []byte) error {
+	return setCodec[T]{s}.UnmarshalText(text)
+}
+
+// MarshalYAML returns the elements of the set in ascending order, which
+// YAML encoders write as a sequence.
+func (s *sortedSet[T]) MarshalYAML() (interface{}, error) {
+	return setCodec[T]{s}.MarshalYAML()
+}
+
+// UnmarshalYAML adds the elements of a YAML sequence to the set.
+func (s *sortedSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return setCodec[T]{s}.UnmarshalYAML(unmarshal)
+}
+
+// MarshalBSONValue creates a BSON array from the set, in ascending order.
+func (s *sortedSet[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return setCodec[T]{s}.MarshalBSONValue()
+}
+
+// UnmarshalBSONValue adds the elements of a BSON array to the set.
+func (s *sortedSet[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return setCodec[T]{s}.UnmarshalBSONValue(bt, b)
+}
@@ -0,0 +1,312 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_SortedSet(t *testing.T) {
+	s := NewSortedSet(5, 3, 9, 3)
+
+	if caps := CapabilitiesOf(s); caps != CapThreadSafe|CapOrdered {
+		t.Errorf("expected the set to be thread-safe and ordered, got %v", caps)
+	}
+	if !s.Add(1) || s.Add(5) {
+		t.Error("Add should report whether the element was added")
+	}
+	if n := s.Append(7, 2, 7, 9); n != 2 {
+		t.Errorf("expected 2 elements to be appended, got %d", n)
+	}
+	if n := s.AppendFrom(NewSet(4, 1)); n != 1 {
+		t.Errorf("expected 1 element to be appended, got %d", n)
+	}
+
+	expected := []int{1, 2, 3, 4, 5, 7, 9}
+	if !slices.Equal(s.ToSlice(), expected) {
+		t.Errorf("expected %v, got %v", expected, s.ToSlice())
+	}
+	var each []int
+	s.Each(func(v int) bool {
+		each = append(each, v)
+		return false
+	})
+	var iter []int
+	for v := range s.Iter() {
+		iter = append(iter, v)
+	}
+	var iterator []int
+	for v := range s.Iterator().C {
+		iterator = append(iterator, v)
+	}
+	if !slices.Equal(each, expected) || !slices.Equal(iter, expected) || !slices.Equal(iterator, expected) {
+		t.Errorf("iteration should be ordered, got %v, %v and %v", each, iter, iterator)
+	}
+	if s.String() != "Set{1, 2, 3, 4, 5, 7, 9}" {
+		t.Errorf("unexpected string: %s", s)
+	}
+	if b, _ := json.Marshal(s); string(b) != "[1,2,3,4,5,7,9]" {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+	if data, err := s.(encoding.BinaryMarshaler).MarshalBinary(); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	} else if decoded := NewSortedSet[int](); decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data) != nil || !decoded.Equal(s) {
+		t.Errorf("unexpected decoded set: %v", decoded)
+	}
+
+	if lo, _ := s.Min(); lo != 1 {
+		t.Errorf("expected minimum 1, got %d", lo)
+	}
+	if hi, _ := s.Max(); hi != 9 {
+		t.Errorf("expected maximum 9, got %d", hi)
+	}
+	if !s.Contains(1, 9) || s.Contains(1, 6) || !s.ContainsOne(4) || !s.ContainsAny(6, 7) || s.ContainsAny(6, 8) {
+		t.Error("unexpected membership")
+	}
+
+	if removed := RemovedWhich(s, 2, 6, 9); !slices.Equal(removed, []int{2, 9}) {
+		t.Errorf("expected 2 and 9 to be removed, got %v", removed)
+	}
+	s.Remove(4)
+	s.RemoveAll(5, 7)
+	if v, ok := s.Pop(); !ok || v != 1 {
+		t.Errorf("Pop should remove the smallest element, got %d", v)
+	}
+	if items, n := s.PopN(5); n != 1 || !slices.Equal(items, []int{3}) {
+		t.Errorf("expected PopN to remove 3, got %v", items)
+	}
+	if !s.IsEmpty() {
+		t.Errorf("expected an empty set, got %v", s)
+	}
+	if _, ok := s.Min(); ok {
+		t.Error("an empty set has no minimum")
+	}
+}
+
+func Test_SortedSetAlgebra(t *testing.T) {
+	s := NewSortedSet(1, 2, 3, 4)
+
+	operands := map[string]Set[int]{
+		"Sorted": NewSortedSet(3, 4, 5),
+		"Safe":   NewSet(3, 4, 5),
+		"Unsafe": NewThreadUnsafeSet(3, 4, 5),
+	}
+	for name, other := range operands {
+		t.Run(name, func(t *testing.T) {
+			check := func(op string, got Set[int], expected ...int) {
+				if _, ok := got.(*sortedSet[int]); !ok {
+					t.Errorf("%s should return a sorted set, got %T", op, got)
+				}
+				if !slices.Equal(got.ToSlice(), expected) {
+					t.Errorf("%s: expected %v, got %v", op, expected, got)
+				}
+			}
+			check("Union", s.Union(other), 1, 2, 3, 4, 5)
+			check("Intersect", s.Intersect(other), 3, 4)
+			check("Difference", s.Difference(other), 1, 2)
+			check("SymmetricDifference", s.SymmetricDifference(other), 1, 2, 5)
+
+			if !s.ContainsAnyElement(other) || s.ContainsAnyElement(NewSet(9)) {
+				t.Error("unexpected result of ContainsAnyElement")
+			}
+			if s.Equal(other) || !s.Equal(NewSet(4, 3, 2, 1)) {
+				t.Error("unexpected result of Equal")
+			}
+			if s.IsSubset(other) || s.IsSuperset(other) {
+				t.Error("the sets should not be subsets of each other")
+			}
+		})
+	}
+
+	if !s.IsSubset(s) || s.IsProperSubset(s) || !s.IsSuperset(s) || s.IsProperSuperset(s) {
+		t.Error("a set is a subset, but not a proper one, of itself")
+	}
+	if !NewSortedSet(2, 3).IsProperSubset(s) || !s.IsProperSuperset(NewSet(1, 4)) {
+		t.Error("unexpected result of the proper subset checks")
+	}
+
+	if f := s.Filter(func(v int) bool { return v%2 == 0 }); !slices.Equal(f.ToSlice(), []int{2, 4}) {
+		t.Errorf("unexpected filtered set: %v", f)
+	}
+	parts := PartitionN(s, 2, func(v int) uint64 { return uint64(v) })
+	if !slices.Equal(parts[0].ToSlice(), []int{2, 4}) || !slices.Equal(parts[1].ToSlice(), []int{1, 3}) {
+		t.Errorf("unexpected partitions: %v", parts)
+	}
+	if evens, odds := Partition(s, func(v int) bool { return v%2 == 0 }); !slices.Equal(evens.ToSlice(), []int{2, 4}) || !slices.Equal(odds.ToSlice(), []int{1, 3}) {
+		t.Errorf("unexpected partition: %v and %v", evens, odds)
+	}
+	if c := s.Clone(); !c.Equal(s) || !s.Add(10) || c.Contains(10) {
+		t.Error("Clone should return an independent copy")
+	}
+}
+
+func Test_SortedSetRemoveRange(t *testing.T) {
+	s := NewSortedSet(1, 3, 5, 7, 9, 11)
+
+	if n := s.RemoveRange(3, 7); n != 3 || !slices.Equal(s.ToSlice(), []int{1, 9, 11}) {
+		t.Errorf("expected 3, 5 and 7 to be removed, got %d and %v", n, s)
+	}
+	// bounds don't need to be elements
+	if n := s.RemoveRange(8, 10); n != 1 || !slices.Equal(s.ToSlice(), []int{1, 11}) {
+		t.Errorf("expected 9 to be removed, got %d and %v", n, s)
+	}
+	if n := s.RemoveRange(2, 10); n != 0 || s.Cardinality() != 2 {
+		t.Errorf("expected nothing to be removed, got %d and %v", n, s)
+	}
+	if n := s.RemoveRange(11, 1); n != 0 || s.Cardinality() != 2 {
+		t.Errorf("an empty range should remove nothing, got %d and %v", n, s)
+	}
+	if n := s.RemoveRange(-100, 100); n != 2 || !s.IsEmpty() {
+		t.Errorf("expected everything to be removed, got %d and %v", n, s)
+	}
+}
+
+func Test_SortedSetRemoveIf(t *testing.T) {
+	s := NewSortedSet(5, 4, 3, 2, 1)
+	if n := RemoveIf[int](s, func(v int) bool { return v%2 == 1 }); n != 3 || !slices.Equal(s.ToSlice(), []int{2, 4}) {
+		t.Errorf("Expected 3 removed and [2 4], got: %d and %v", n, s)
+	}
+	if s.Add(3); !slices.Equal(s.ToSlice(), []int{2, 3, 4}) {
+		t.Errorf("the elements should remain sorted, got %v", s)
+	}
+}
+
+func Test_SortedSetRandomOps(t *testing.T) {
+	s := NewSortedSet[int]()
+	model := map[int]bool{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		v := r.Intn(2000)
+		switch r.Intn(4) {
+		case 0, 1:
+			if s.Add(v) == model[v] {
+				t.Fatalf("Add(%d) disagrees with the model", v)
+			}
+			model[v] = true
+		case 2:
+			hi := v + r.Intn(50)
+			removed := 0
+			for k := range model {
+				if k >= v && k <= hi {
+					delete(model, k)
+					removed++
+				}
+			}
+			if n := s.RemoveRange(v, hi); n != removed {
+				t.Fatalf("RemoveRange(%d, %d): expected %d, got %d", v, hi, removed, n)
+			}
+		case 3:
+			s.Remove(v)
+			delete(model, v)
+		}
+	}
+
+	want := make([]int, 0, len(model))
+	for k := range model {
+		want = append(want, k)
+	}
+	slices.Sort(want)
+	if !slices.Equal(s.ToSlice(), want) || s.Cardinality() != len(want) {
+		t.Fatalf("Expected %d sorted elements, got: %d", len(want), s.Cardinality())
+	}
+	if hi, _ := s.Max(); hi != want[len(want)-1] {
+		t.Errorf("Expected max %d, got: %d", want[len(want)-1], hi)
+	}
+	if items, n := s.PopN(10); n != 10 || !slices.Equal(items, want[:10]) {
+		t.Errorf("Expected %v, got: %v", want[:10], items)
+	}
+	if lo, _ := s.Min(); lo != want[10] {
+		t.Errorf("Expected min %d, got: %d", want[10], lo)
+	}
+}
+
+func Test_SortedSetNaN(t *testing.T) {
+	s := NewSortedSet(2, math.NaN(), 1, math.NaN())
+	if s.Cardinality() != 3 || s.Add(math.NaN()) || !s.ContainsOne(math.NaN()) {
+		t.Errorf("NaNs should be considered equal, got %v", s)
+	}
+	if lo, _ := s.Min(); !math.IsNaN(lo) {
+		t.Errorf("NaN should be the smallest element, got %v", lo)
+	}
+}
+
+func Test_SortedSetSorted(t *testing.T) {
+	s := NewSortedSet("c", "a", "b")
+	if !slices.Equal(Sorted[string](s), []string{"a", "b", "c"}) {
+		t.Errorf("unexpected sorted elements: %v", Sorted[string](s))
+	}
+}
+
+func Test_SortedSetConcurrent(t *testing.T) {
+	s := NewSortedSet[int]()
+	ints := nrand(N)
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			s.Add(v)
+			s.Contains(v)
+			s.Union(s)
+		}(v)
+	}
+	wg.Wait()
+
+	if !s.Equal(NewSet(ints...)) {
+		t.Errorf("Expected no difference, got: %v", s.SymmetricDifference(NewSet(ints...)))
+	}
+	if !slices.IsSorted(s.ToSlice()) {
+		t.Error("the elements should remain sorted")
+	}
+}
+
+func Test_SortedSetFormat(t *testing.T) {
+	if got := fmt.Sprintf("%#v", NewSortedSet(3, 1, 2)); got != "mapset.NewSortedSet[int](1, 2, 3)" {
+		t.Errorf("Expected mapset.NewSortedSet[int](1, 2, 3), got: %s", got)
+	}
+}
+
+func Test_SortedSetUpdate(t *testing.T) {
+	s := NewSortedSet(3, 1)
+	Update[int](s, func(tx MutableView[int]) {
+		tx.Remove(3)
+		tx.Add(2)
+	})
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got: %v", got)
+	}
+}
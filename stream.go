@@ -0,0 +1,175 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// streamChunkSize is the number of elements encoded or decoded at a time
+// by the streaming encoders.
+var streamChunkSize = 4096
+
+// EncodeJSON writes the elements of s to w as a JSON array, the same
+// representation as MarshalJSON, without building an intermediate slice of
+// the whole set.
+//
+// For thread-safe sets the read lock is only held while copying chunks of
+// elements and is released while they are encoded and written, so a very
+// large set can be checkpointed without blocking writers for the whole
+// export. Elements added or removed concurrently may or may not be written.
+func EncodeJSON[T comparable](w io.Writer, s Set[T]) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte('['); err != nil {
+		return err
+	}
+
+	first := true
+	err := eachChunk(s, func(chunk []T) error {
+		for _, elem := range chunk {
+			b, err := json.Marshal(elem)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if err := bw.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := bw.Write(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := bw.WriteByte(']'); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// DecodeJSON reads a JSON array from r and adds its elements to s, in
+// chunks, without decoding the whole array into memory first.
+func DecodeJSON[T comparable](r io.Reader, s Set[T]) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("mapset: expected a JSON array, got %v", tok)
+	}
+
+	chunk := make([]T, 0, streamChunkSize)
+	for dec.More() {
+		var elem T
+		if err := dec.Decode(&elem); err != nil {
+			return err
+		}
+		chunk = append(chunk, elem)
+		if len(chunk) == streamChunkSize {
+			s.Append(chunk...)
+			chunk = chunk[:0]
+		}
+	}
+	s.Append(chunk...)
+
+	_, err = dec.Token()
+	return err
+}
+
+// EncodeGob writes the elements of s to w as a stream of gob values, one
+// per element, without building an intermediate slice of the whole set.
+// Locking behaves as for EncodeJSON.
+func EncodeGob[T comparable](w io.Writer, s Set[T]) error {
+	bw := bufio.NewWriter(w)
+	enc := gob.NewEncoder(bw)
+
+	err := eachChunk(s, func(chunk []T) error {
+		for i := range chunk {
+			if err := enc.Encode(&chunk[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// DecodeGob reads a stream of gob values written by EncodeGob from r until
+// EOF and adds them to s, in chunks.
+func DecodeGob[T comparable](r io.Reader, s Set[T]) error {
+	dec := gob.NewDecoder(r)
+
+	chunk := make([]T, 0, streamChunkSize)
+	for {
+		var elem T
+		err := dec.Decode(&elem)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		chunk = append(chunk, elem)
+		if len(chunk) == streamChunkSize {
+			s.Append(chunk...)
+			chunk = chunk[:0]
+		}
+	}
+	s.Append(chunk...)
+
+	return nil
+}
+
+// eachChunk calls cb with consecutive chunks of the elements of s until
+// all elements have been visited or cb returns an error. Thread-safe sets
+// are only locked while a chunk is being filled.
+func eachChunk[T comparable](s Set[T], cb func([]T) error) error {
+	var err error
+	size := streamChunkSize
+	if t, ok := unwrapSet(s).(*threadSafeSet[T]); ok && t.iterChunk > 0 {
+		size = t.iterChunk
+	}
+	EachChunk(s, size, func(chunk []T) bool {
+		err = cb(chunk)
+		return err != nil
+	})
+	return err
+}
@@ -0,0 +1,166 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func withStreamChunkSize(t *testing.T, size int) {
+	prev := streamChunkSize
+	streamChunkSize = size
+	t.Cleanup(func() { streamChunkSize = prev })
+}
+
+func Test_EncodeDecodeJSON(t *testing.T) {
+	withStreamChunkSize(t, 7)
+
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(nrand(100)...)
+
+		var buf bytes.Buffer
+		if err := EncodeJSON(&buf, s); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+
+		// the streamed output is compatible with MarshalJSON/UnmarshalJSON
+		unmarshaled := ctor()
+		if err := json.Unmarshal(buf.Bytes(), unmarshaled); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !s.Equal(unmarshaled) {
+			t.Errorf("Expected no difference, got: %v", s.SymmetricDifference(unmarshaled))
+		}
+
+		decoded := ctor()
+		if err := DecodeJSON(&buf, decoded); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !s.Equal(decoded) {
+			t.Errorf("Expected no difference, got: %v", s.SymmetricDifference(decoded))
+		}
+
+		buf.Reset()
+		if err := EncodeJSON(&buf, ctor()); err != nil || buf.String() != "[]" {
+			t.Errorf("expected an empty JSON array, got %q (%v)", buf.String(), err)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_DecodeJSONInvalid(t *testing.T) {
+	for _, input := range []string{`{"a": 1}`, `[1, "a"]`, `[1, 2`} {
+		if err := DecodeJSON(strings.NewReader(input), NewSet[int]()); err == nil {
+			t.Errorf("expected an error decoding %s", input)
+		}
+	}
+}
+
+func Test_EncodeDecodeGob(t *testing.T) {
+	withStreamChunkSize(t, 7)
+
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		s := ctor("a", "b", "c", "", "d", "e", "f", "g", "h", "i")
+
+		var buf bytes.Buffer
+		if err := EncodeGob(&buf, s); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+
+		decoded := ctor()
+		if err := DecodeGob(&buf, decoded); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !s.Equal(decoded) {
+			t.Errorf("Expected no difference, got: %v", s.SymmetricDifference(decoded))
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_EachChunkReleasesLock(t *testing.T) {
+	s := newThreadSafeSet[int]()
+	s.Append(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	visited := 0
+	s.eachChunk(3, func(chunk []int) bool {
+		visited += len(chunk)
+		// this would deadlock if the read lock was still held
+		s.Remove(10)
+		return false
+	})
+
+	if visited < 9 || visited > 10 {
+		t.Errorf("expected 9 or 10 elements to be visited, got %d", visited)
+	}
+}
+
+func Test_EncodeJSONConcurrent(t *testing.T) {
+	withStreamChunkSize(t, 16)
+
+	s := NewSet(nrand(N)...)
+	stable := s.Clone()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < N; i++ {
+			s.Add(-i - 1)
+			s.Remove(-i - 1)
+		}
+	}()
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	wg.Wait()
+
+	decoded := NewSet[int]()
+	if err := DecodeJSON(&buf, decoded); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !stable.IsSubset(decoded) {
+		t.Error("elements that were not modified concurrently should all be encoded")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultTextSeparator separates the elements encoded by MarshalText.
+const DefaultTextSeparator = ","
+
+// ErrInvalidText is returned when an element cannot be encoded as text,
+// because it is empty or contains the separator.
+var ErrInvalidText = errors.New("mapset: element cannot be encoded as text")
+
+// EncodeText encodes the elements of s as text, in ascending order and
+// separated by sep, so that sets can be used in simple text configuration
+// values such as flags or environment variables. Strings are written as
+// they are, elements implementing encoding.TextMarshaler are written with
+// it and other elements with fmt's %v.
+func EncodeText[T comparable](s Set[T], sep string) ([]byte, error) {
+	items := make([]string, 0, s.Cardinality())
+	var err error
+	s.Each(func(elem T) bool {
+		var item string
+		if item, err = elementText(elem); err != nil {
+			return true
+		}
+		if item == "" || strings.Contains(item, sep) {
+			err = fmt.Errorf("%w: %q", ErrInvalidText, item)
+			return true
+		}
+		items = append(items, item)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(items)
+	return []byte(strings.Join(items, sep)), nil
+}
+
+// DecodeText splits text on sep and adds the parsed elements to s. Spaces
+// around the elements and empty elements are ignored, so "a, b," holds a
+// and b. Strings are taken as they are, elements implementing
+// encoding.TextUnmarshaler are parsed with it and other elements are
+// decoded as JSON, which covers numbers and booleans.
+func DecodeText[T comparable](text []byte, s Set[T], sep string) error {
+	var elems []T
+	for _, item := range strings.Split(string(text), sep) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		elem, err := parseElement[T](item)
+		if err != nil {
+			return fmt.Errorf("mapset: cannot parse %q: %w", item, err)
+		}
+		elems = append(elems, elem)
+	}
+	s.Append(elems...)
+	return nil
+}
+
+func elementText[T comparable](elem T) (string, error) {
+	switch e := any(elem).(type) {
+	case string:
+		return e, nil
+	case encoding.TextMarshaler:
+		b, err := e.MarshalText()
+		return string(b), err
+	}
+	return fmt.Sprintf("%v", elem), nil
+}
+
+func parseElement[T comparable](item string) (T, error) {
+	var elem T
+	if u, ok := any(&elem).(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(item))
+		return elem, err
+	}
+	if v := reflect.ValueOf(&elem).Elem(); v.Kind() == reflect.String {
+		v.SetString(item)
+		return elem, nil
+	}
+	err := json.Unmarshal([]byte(item), &elem)
+	return elem, err
+}
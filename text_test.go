@@ -0,0 +1,121 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type color string
+
+// level is encoded by its name as text.
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("!", int(l))), nil
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	*l = level(len(text))
+	return nil
+}
+
+func Test_MarshalText(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		text, err := ctor("b", "c", "a").(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if string(text) != "a,b,c" {
+			t.Errorf("unexpected text: %s", text)
+		}
+
+		decoded := ctor("d")
+		if err := decoded.(encoding.TextUnmarshaler).UnmarshalText([]byte(" a, b ,,c,")); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if !decoded.Equal(ctor("a", "b", "c", "d")) {
+			t.Errorf("unexpected decoded set: %v", decoded)
+		}
+
+		if _, err := ctor("a,b").(encoding.TextMarshaler).MarshalText(); !errors.Is(err, ErrInvalidText) {
+			t.Errorf("expected ErrInvalidText, got %v", err)
+		}
+		if _, err := ctor("").(encoding.TextMarshaler).MarshalText(); !errors.Is(err, ErrInvalidText) {
+			t.Errorf("expected ErrInvalidText, got %v", err)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
+
+func Test_EncodeDecodeText(t *testing.T) {
+	text, err := EncodeText(NewSet(3, 1, 2), " | ")
+	if err != nil || string(text) != "1 | 2 | 3" {
+		t.Errorf("unexpected text %q, error: %v", text, err)
+	}
+	ints := NewSet[int]()
+	if err := DecodeText(text, ints, "|"); err != nil || !ints.Equal(NewSet(1, 2, 3)) {
+		t.Errorf("unexpected set %v, error: %v", ints, err)
+	}
+	if err := DecodeText([]byte("1|x"), ints, "|"); err == nil {
+		t.Error("expected an error for an invalid integer")
+	}
+
+	colors := NewSet[color]()
+	if err := DecodeText([]byte("red;green"), colors, ";"); err != nil || !colors.Equal(NewSet[color]("red", "green")) {
+		t.Errorf("unexpected set %v, error: %v", colors, err)
+	}
+
+	levels := NewSet[level](1, 3)
+	text, err = EncodeText(levels, ",")
+	if err != nil || string(text) != "!,!!!" {
+		t.Errorf("unexpected text %q, error: %v", text, err)
+	}
+	decoded := NewSet[level]()
+	if err := DecodeText(text, decoded, ","); err != nil || !decoded.Equal(levels) {
+		t.Errorf("unexpected set %v, error: %v", decoded, err)
+	}
+}
+
+func Test_TextMapKey(t *testing.T) {
+	b, err := json.Marshal(map[Set[string]]int{NewSet("b", "a"): 1})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if string(b) != `{"a,b":1}` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+}
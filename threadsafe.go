@@ -26,6 +26,8 @@ SOFTWARE.
 package mapset
 
 import (
+	"fmt"
+	"reflect"
 	"sync"
 
 	"go.mongodb.org/mongo-driver/bson/bsontype"
@@ -34,6 +36,72 @@ import (
 type threadSafeSet[T comparable] struct {
 	sync.RWMutex
 	uss *threadUnsafeSet[T]
+
+	// locker replaces the embedded RWMutex when a LockPolicy other than
+	// the default one is selected.
+	locker rwLocker
+
+	// iterChunk is the number of elements copied at a time by iterations
+	// when they are chunked, see WithChunkedIteration.
+	iterChunk int
+
+	// shared reports that uss may be shared with a snapshot, so that it
+	// must be copied before it is modified, see Snapshot. It is guarded by
+	// the write lock.
+	shared bool
+}
+
+// Lock locks the set for writing. As every mutation takes it, it also
+// copies the elements shared with a snapshot.
+func (t *threadSafeSet[T]) Lock() {
+	t.lock()
+	if t.shared {
+		uss := threadUnsafeSet[T](mapclone(*t.uss))
+		t.uss = &uss
+		t.shared = false
+	}
+}
+
+func (t *threadSafeSet[T]) lock() {
+	if t.locker != nil {
+		t.locker.Lock()
+		return
+	}
+	t.RWMutex.Lock()
+}
+
+// snapshot returns a set sharing the elements of t until either set is
+// modified.
+func (t *threadSafeSet[T]) snapshot() Set[T] {
+	t.lock()
+	defer t.Unlock()
+
+	t.shared = true
+	return &threadSafeSet[T]{uss: t.uss, shared: true}
+}
+
+func (t *threadSafeSet[T]) Unlock() {
+	if t.locker != nil {
+		t.locker.Unlock()
+		return
+	}
+	t.RWMutex.Unlock()
+}
+
+func (t *threadSafeSet[T]) RLock() {
+	if t.locker != nil {
+		t.locker.RLock()
+		return
+	}
+	t.RWMutex.RLock()
+}
+
+func (t *threadSafeSet[T]) RUnlock() {
+	if t.locker != nil {
+		t.locker.RUnlock()
+		return
+	}
+	t.RWMutex.RUnlock()
 }
 
 func newThreadSafeSet[T comparable]() *threadSafeSet[T] {
@@ -48,6 +116,34 @@ func newThreadSafeSetWithSize[T comparable](cardinality int) *threadSafeSet[T] {
 	}
 }
 
+// lockOrder reports whether a must be locked before b. Operations holding
+// the locks of two sets take them in address order, so that they cannot
+// deadlock with each other whatever the order of their operands, see Move.
+func lockOrder[T comparable](a, b *threadSafeSet[T]) bool {
+	return reflect.ValueOf(a).Pointer() < reflect.ValueOf(b).Pointer()
+}
+
+// rlockBoth read-locks a and b in address order. The lock is only taken
+// once if they are the same set, as recursive read locking can deadlock.
+func rlockBoth[T comparable](a, b *threadSafeSet[T]) {
+	if a == b {
+		a.RLock()
+		return
+	}
+	if !lockOrder(a, b) {
+		a, b = b, a
+	}
+	a.RLock()
+	b.RLock()
+}
+
+func runlockBoth[T comparable](a, b *threadSafeSet[T]) {
+	a.RUnlock()
+	if a != b {
+		b.RUnlock()
+	}
+}
+
 func (t *threadSafeSet[T]) Add(v T) bool {
 	t.Lock()
 	ret := t.uss.Add(v)
@@ -63,10 +159,19 @@ func (t *threadSafeSet[T]) Append(v ...T) int {
 }
 
 func (t *threadSafeSet[T]) AppendFrom(other Set[T]) int {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
+	if o == t {
+		// every element is already present, and locking o would deadlock
+		return 0
+	}
 
-	t.Lock()  // Write Lock
-	o.RLock() // Read Lock
+	if lockOrder(t, o) {
+		t.Lock()
+		o.RLock()
+	} else {
+		o.RLock()
+		t.Lock()
+	}
 	defer t.Unlock()
 	defer o.RUnlock()
 
@@ -98,15 +203,13 @@ func (t *threadSafeSet[T]) ContainsAny(v ...T) bool {
 }
 
 func (t *threadSafeSet[T]) ContainsAnyElement(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	ret := t.uss.ContainsAnyElement(o.uss)
 
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
@@ -115,24 +218,20 @@ func (t *threadSafeSet[T]) IsEmpty() bool {
 }
 
 func (t *threadSafeSet[T]) IsSubset(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	ret := t.uss.IsSubset(o.uss)
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
 func (t *threadSafeSet[T]) IsProperSubset(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	defer t.RUnlock()
-	o.RLock()
-	defer o.RUnlock()
+	rlockBoth(t, o)
+	defer runlockBoth(t, o)
 
 	return t.uss.IsProperSubset(o.uss)
 }
@@ -146,54 +245,46 @@ func (t *threadSafeSet[T]) IsProperSuperset(other Set[T]) bool {
 }
 
 func (t *threadSafeSet[T]) Union(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	unsafeUnion := t.uss.Union(o.uss).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeUnion}
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
 func (t *threadSafeSet[T]) Intersect(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	unsafeIntersection := t.uss.Intersect(o.uss).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeIntersection}
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
 func (t *threadSafeSet[T]) Difference(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	unsafeDifference := t.uss.Difference(o.uss).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeDifference}
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
 func (t *threadSafeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	unsafeDifference := t.uss.SymmetricDifference(o.uss).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeDifference}
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
@@ -215,13 +306,68 @@ func (t *threadSafeSet[T]) RemoveAll(i ...T) {
 	t.Unlock()
 }
 
+func (t *threadSafeSet[T]) RemovedWhich(i ...T) []T {
+	t.Lock()
+	ret := t.uss.RemovedWhich(i...)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeSet[T]) removeIf(pred func(T) bool) []T {
+	t.Lock()
+	defer t.Unlock()
+	return t.uss.removeIf(pred)
+}
+
+func (t *threadSafeSet[T]) update(fn func(MutableView[T])) {
+	t.Lock()
+	// the deferred unlock also runs while a panic of fn unwinds the stack
+	defer t.Unlock()
+	fn(mapView[T](*t.uss))
+}
+
+func (t *threadSafeSet[T]) retainAll(o *threadSafeSet[T]) int {
+	if o == t {
+		// every element is kept, and locking o would deadlock
+		return 0
+	}
+
+	if lockOrder(t, o) {
+		t.Lock()
+		o.RLock()
+	} else {
+		o.RLock()
+		t.Lock()
+	}
+	defer t.Unlock()
+	defer o.RUnlock()
+
+	return t.uss.retainAll(*o.uss)
+}
+
 func (t *threadSafeSet[T]) Cardinality() int {
 	t.RLock()
 	defer t.RUnlock()
 	return len(*t.uss)
 }
 
+func (t *threadSafeSet[T]) Capabilities() Capabilities {
+	return CapThreadSafe
+}
+
 func (t *threadSafeSet[T]) Each(cb func(T) bool) {
+	if t.iterChunk > 0 {
+		t.eachChunk(t.iterChunk, func(chunk []T) bool {
+			for _, elem := range chunk {
+				if cb(elem) {
+					return true
+				}
+			}
+			return false
+		})
+		return
+	}
+
 	t.RLock()
 	defer t.RUnlock()
 	for elem := range *t.uss {
@@ -231,6 +377,61 @@ func (t *threadSafeSet[T]) Each(cb func(T) bool) {
 	}
 }
 
+func (t *threadSafeSet[T]) EachErr(cb func(T) error) error {
+	if t.iterChunk > 0 {
+		var err error
+		t.eachChunk(t.iterChunk, func(chunk []T) bool {
+			for _, elem := range chunk {
+				if err = cb(elem); err != nil {
+					return true
+				}
+			}
+			return false
+		})
+		return err
+	}
+
+	t.RLock()
+	// the deferred unlock also runs while a panic of cb unwinds the stack
+	defer t.RUnlock()
+	return t.uss.EachErr(cb)
+}
+
+// eachChunk calls cb with consecutive chunks of at most size elements, stopping
+// early if cb returns true. The read lock is only held while a chunk is being
+// filled and it is released while cb runs, so writers are not blocked for the
+// whole iteration. The chunk slice is reused between calls.
+//
+// Iteration is weakly consistent: elements added or removed while the lock is
+// released may or may not be visited, and an element that is removed and added
+// back may be visited twice. Other elements are visited exactly once.
+func (t *threadSafeSet[T]) eachChunk(size int, cb func([]T) bool) {
+	chunk := make([]T, 0, size)
+
+	t.RLock()
+	// The Go specification allows a map to be modified while it is being
+	// ranged over, so releasing the lock in the middle of the loop is safe
+	// as long as every step of the iteration happens with the lock held.
+	for elem := range *t.uss {
+		chunk = append(chunk, elem)
+		if len(chunk) < size {
+			continue
+		}
+
+		t.RUnlock()
+		if cb(chunk) {
+			return
+		}
+		chunk = chunk[:0]
+		t.RLock()
+	}
+	t.RUnlock()
+
+	if len(chunk) > 0 {
+		cb(chunk)
+	}
+}
+
 func (t *threadSafeSet[T]) Filter(cb func(T) bool) Set[T] {
 	t.RLock()
 	defer t.RUnlock()
@@ -243,9 +444,47 @@ func (t *threadSafeSet[T]) Filter(cb func(T) bool) Set[T] {
 	return mappedSet
 }
 
+func (t *threadSafeSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	t.RLock()
+	matching, rest := t.uss.partition(pred)
+	t.RUnlock()
+
+	return &threadSafeSet[T]{uss: matching}, &threadSafeSet[T]{uss: rest}
+}
+
+func (t *threadSafeSet[T]) PartitionN(n int, hash func(T) uint64) []Set[T] {
+	t.RLock()
+	shards := t.uss.partitionN(n, hash)
+	t.RUnlock()
+	if shards == nil {
+		return nil
+	}
+
+	parts := make([]Set[T], n)
+	for i := range shards {
+		parts[i] = &threadSafeSet[T]{uss: shards[i]}
+	}
+	return parts
+}
+
+func (t *threadSafeSet[T]) All() func(yield func(element T) bool) {
+	return Elements[T](t)
+}
+
 func (t *threadSafeSet[T]) Iter() <-chan T {
 	ch := make(chan T)
 	go func() {
+		if t.iterChunk > 0 {
+			t.eachChunk(t.iterChunk, func(chunk []T) bool {
+				for _, elem := range chunk {
+					ch <- elem
+				}
+				return false
+			})
+			close(ch)
+			return
+		}
+
 		t.RLock()
 
 		for elem := range *t.uss {
@@ -262,6 +501,21 @@ func (t *threadSafeSet[T]) Iterator() *Iterator[T] {
 	iterator, ch, stopCh := newIterator[T]()
 
 	go func() {
+		if t.iterChunk > 0 {
+			t.eachChunk(t.iterChunk, func(chunk []T) bool {
+				for _, elem := range chunk {
+					select {
+					case <-stopCh:
+						return true
+					case ch <- elem:
+					}
+				}
+				return false
+			})
+			close(ch)
+			return
+		}
+
 		t.RLock()
 	L:
 		for elem := range *t.uss {
@@ -279,14 +533,12 @@ func (t *threadSafeSet[T]) Iterator() *Iterator[T] {
 }
 
 func (t *threadSafeSet[T]) Equal(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
+	o := unwrapSet(other).(*threadSafeSet[T])
 
-	t.RLock()
-	o.RLock()
+	rlockBoth(t, o)
 
 	ret := t.uss.Equal(o.uss)
-	t.RUnlock()
-	o.RUnlock()
+	runlockBoth(t, o)
 	return ret
 }
 
@@ -299,6 +551,10 @@ func (t *threadSafeSet[T]) Clone() Set[T] {
 	return ret
 }
 
+func (t *threadSafeSet[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return reconcile[T](t, target, add, remove)
+}
+
 func (t *threadSafeSet[T]) String() string {
 	t.RLock()
 	ret := t.uss.String()
@@ -306,6 +562,11 @@ func (t *threadSafeSet[T]) String() string {
 	return ret
 }
 
+// Format implements fmt.Formatter, see formatSet.
+func (t *threadSafeSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "NewSet", t.ToSlice())
+}
+
 func (t *threadSafeSet[T]) Pop() (T, bool) {
 	t.Lock()
 	defer t.Unlock()
@@ -319,6 +580,15 @@ func (t *threadSafeSet[T]) PopN(n int) ([]T, int) {
 }
 
 func (t *threadSafeSet[T]) ToSlice() []T {
+	if t.iterChunk > 0 {
+		keys := make([]T, 0, t.Cardinality())
+		t.eachChunk(t.iterChunk, func(chunk []T) bool {
+			keys = append(keys, chunk...)
+			return false
+		})
+		return keys
+	}
+
 	t.RLock()
 	l := len(*t.uss)
 	keys := make([]T, 0, l)
@@ -329,6 +599,14 @@ func (t *threadSafeSet[T]) ToSlice() []T {
 	return keys
 }
 
+func (t *threadSafeSet[T]) CanonicalBytes() []byte {
+	t.RLock()
+	b := t.uss.CanonicalBytes()
+	t.RUnlock()
+
+	return b
+}
+
 func (t *threadSafeSet[T]) MarshalJSON() ([]byte, error) {
 	t.RLock()
 	b, err := t.uss.MarshalJSON()
@@ -345,6 +623,83 @@ func (t *threadSafeSet[T]) UnmarshalJSON(p []byte) error {
 	return err
 }
 
+func (t *threadSafeSet[T]) MarshalBinary() ([]byte, error) {
+	t.RLock()
+	b, err := t.uss.MarshalBinary()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeSet[T]) UnmarshalBinary(data []byte) error {
+	// decode without holding the lock
+	decoded := newThreadUnsafeSet[T]()
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	t.Lock()
+	if t.uss == nil {
+		t.uss = decoded
+	} else {
+		for elem := range *decoded {
+			t.uss.add(elem)
+		}
+	}
+	t.Unlock()
+
+	return nil
+}
+
+func (t *threadSafeSet[T]) MarshalText() ([]byte, error) {
+	t.RLock()
+	b, err := t.uss.MarshalText()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeSet[T]) UnmarshalText(text []byte) error {
+	// decode without holding the lock
+	decoded := newThreadUnsafeSet[T]()
+	if err := decoded.UnmarshalText(text); err != nil {
+		return err
+	}
+
+	t.Lock()
+	if t.uss == nil {
+		t.uss = decoded
+	} else {
+		for elem := range *decoded {
+			t.uss.add(elem)
+		}
+	}
+	t.Unlock()
+
+	return nil
+}
+
+func (t *threadSafeSet[T]) MarshalYAML() (interface{}, error) {
+	return t.ToSlice(), nil
+}
+
+func (t *threadSafeSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// decode without holding the lock
+	var i []T
+	if err := unmarshal(&i); err != nil {
+		return err
+	}
+
+	t.Lock()
+	if t.uss == nil {
+		t.uss = newThreadUnsafeSetWithSize[T](len(i))
+	}
+	t.uss.append(i...)
+	t.Unlock()
+
+	return nil
+}
+
 func (t *threadSafeSet[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	t.RLock()
 	bt, b, err := t.uss.MarshalBSONValue()
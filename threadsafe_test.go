@@ -536,6 +536,32 @@ func Test_RemoveConcurrent(t *testing.T) {
 	}
 }
 
+func Test_RemovedWhichConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewSet[int]()
+	ints := rand.Perm(N)
+	s.Append(ints...)
+
+	var removed int64
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for w := 0; w < 4; w++ {
+		go func() {
+			atomic.AddInt64(&removed, int64(len(RemovedWhich(s, ints...))))
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if removed != N {
+		t.Errorf("Expected every element to be reported removed exactly once; got %v", removed)
+	}
+	if s.Cardinality() != 0 {
+		t.Errorf("Expected cardinality 0; got %v", s.Cardinality())
+	}
+}
+
 func Test_StringConcurrent(t *testing.T) {
 	runtime.GOMAXPROCS(2)
 
@@ -802,3 +828,127 @@ func Test_MarshalBSONValue(t *testing.T) {
 		t.Errorf("Expected no difference, got: %v", expected.Difference(actual))
 	}
 }
+
+// yamlMarshaler and yamlUnmarshaler are the interfaces of gopkg.in/yaml.v2.
+type yamlMarshaler interface {
+	MarshalYAML() (interface{}, error)
+}
+
+type yamlUnmarshaler interface {
+	UnmarshalYAML(func(interface{}) error) error
+}
+
+// yamlRoundTrip emulates a YAML library: it encodes what MarshalYAML
+// returns and decodes it through the unmarshal callback of UnmarshalYAML.
+// JSON arrays stand in for YAML sequences.
+func yamlRoundTrip(from, to interface{}) error {
+	v, err := from.(yamlMarshaler).MarshalYAML()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return to.(yamlUnmarshaler).UnmarshalYAML(func(out interface{}) error {
+		return json.Unmarshal(b, out)
+	})
+}
+
+func Test_MarshalYAML(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		actual := ctor("c")
+		if err := yamlRoundTrip(ctor("a", "b"), actual); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if expected := ctor("a", "b", "c"); !expected.Equal(actual) {
+			t.Errorf("Expected no difference, got: %v", expected.SymmetricDifference(actual))
+		}
+
+		v, _ := ctor("a").(yamlMarshaler).MarshalYAML()
+		if elems, ok := v.([]string); !ok || len(elems) != 1 {
+			t.Errorf("expected a slice of strings, got %#v", v)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+	t.Run("Options", func(t *testing.T) {
+		s := NewSetWithOptions(RejectZero[string]())
+		if err := yamlRoundTrip(NewSet("", "a"), s); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if !s.Equal(NewSet("a")) {
+			t.Errorf("the options should apply to decoded elements, got %v", s)
+		}
+	})
+}
+
+func Test_PopNConcurrent(t *testing.T) {
+	s := NewSet[int]()
+	ints := nrand(N)
+	s.Append(ints...)
+	expected := s.Clone()
+
+	var mu sync.Mutex
+	popped := NewThreadUnsafeSet[int]()
+	var wg sync.WaitGroup
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				items, n := s.PopN(7)
+				if n == 0 {
+					return
+				}
+				if len(items) != n || cap(items) > 7 {
+					t.Errorf("unexpected slice of len %d and cap %d for %d items", len(items), cap(items), n)
+				}
+				mu.Lock()
+				for _, v := range items {
+					if !popped.Add(v) {
+						t.Errorf("%d was popped twice", v)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !popped.Equal(NewThreadUnsafeSet(expected.ToSlice()...)) {
+		t.Errorf("every element should be popped once, missing %v", expected.Difference(NewSet(popped.ToSlice()...)))
+	}
+}
+
+func Test_LockOrderNoDeadlock(t *testing.T) {
+	a, b := NewSet(1, 2), NewSet(2, 3)
+
+	// operations holding the locks of both sets in opposite argument
+	// orders, mixed with writers, must not deadlock
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			x, y := a, b
+			if g%2 == 1 {
+				x, y = b, a
+			}
+			for i := 0; i < N; i++ {
+				x.AppendFrom(y)
+				x.Union(y)
+				x.Union(x)
+				x.Equal(y)
+				Move(x, y, i%4)
+				x.Add(i % 4)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
@@ -26,6 +26,7 @@ SOFTWARE.
 package mapset
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -74,7 +75,7 @@ func (s *threadUnsafeSet[T]) append(vs ...T) {
 }
 
 func (s *threadUnsafeSet[T]) AppendFrom(other Set[T]) int {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
 	prevLen := s.Cardinality()
 	for elem := range *o {
@@ -87,6 +88,10 @@ func (s *threadUnsafeSet[T]) Cardinality() int {
 	return len(*s)
 }
 
+func (s *threadUnsafeSet[T]) Capabilities() Capabilities {
+	return 0
+}
+
 func (s *threadUnsafeSet[T]) Clear() {
 	// Constructions like this are optimised by compiler, and replaced by
 	// mapclear() function, defined in
@@ -124,7 +129,7 @@ func (s *threadUnsafeSet[T]) ContainsAny(v ...T) bool {
 }
 
 func (s *threadUnsafeSet[T]) ContainsAnyElement(other Set[T]) bool {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
 	// loop over smaller set
 	if s.Cardinality() < other.Cardinality() {
@@ -150,12 +155,12 @@ func (s *threadUnsafeSet[T]) contains(v T) (ok bool) {
 }
 
 func (s *threadUnsafeSet[T]) Difference(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
-	diff := make(threadUnsafeSet[T], s.Cardinality())
+	diff := make(threadUnsafeSet[T], len(*s))
 	for elem := range *s {
-		if !o.contains(elem) {
-			diff.add(elem)
+		if _, found := (*o)[elem]; !found {
+			diff[elem] = struct{}{}
 		}
 	}
 	return &diff
@@ -169,6 +174,15 @@ func (s *threadUnsafeSet[T]) Each(cb func(T) bool) {
 	}
 }
 
+func (s *threadUnsafeSet[T]) EachErr(cb func(T) error) error {
+	for elem := range *s {
+		if err := cb(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *threadUnsafeSet[T]) Filter(cb func(T) bool) Set[T] {
 	mappedSet := newThreadUnsafeSetWithSize[T](s.Cardinality())
 	for elem := range *s {
@@ -179,8 +193,29 @@ func (s *threadUnsafeSet[T]) Filter(cb func(T) bool) Set[T] {
 	return mappedSet
 }
 
+func (s *threadUnsafeSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching, rest := s.partition(pred)
+	return matching, rest
+}
+
+// private version of Partition which returns the concrete sets
+func (s *threadUnsafeSet[T]) partition(pred func(T) bool) (*threadUnsafeSet[T], *threadUnsafeSet[T]) {
+	// presize both halves for an even split to avoid map growth
+	size := s.Cardinality()/2 + 1
+	matching := newThreadUnsafeSetWithSize[T](size)
+	rest := newThreadUnsafeSetWithSize[T](size)
+	for elem := range *s {
+		if pred(elem) {
+			matching.add(elem)
+		} else {
+			rest.add(elem)
+		}
+	}
+	return matching, rest
+}
+
 func (s *threadUnsafeSet[T]) Equal(other Set[T]) bool {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
 	if s.Cardinality() != other.Cardinality() {
 		return false
@@ -194,23 +229,17 @@ func (s *threadUnsafeSet[T]) Equal(other Set[T]) bool {
 }
 
 func (s *threadUnsafeSet[T]) Intersect(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
-	var intersection threadUnsafeSet[T]
 	// loop over smaller set
-	if s.Cardinality() < other.Cardinality() {
-		intersection = make(threadUnsafeSet[T], s.Cardinality())
-		for elem := range *s {
-			if o.contains(elem) {
-				intersection.add(elem)
-			}
-		}
-	} else {
-		intersection = make(threadUnsafeSet[T], o.Cardinality())
-		for elem := range *o {
-			if s.contains(elem) {
-				intersection.add(elem)
-			}
+	small, large := *s, *o
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	intersection := make(threadUnsafeSet[T], len(small))
+	for elem := range small {
+		if _, found := large[elem]; found {
+			intersection[elem] = struct{}{}
 		}
 	}
 	return &intersection
@@ -229,7 +258,7 @@ func (s *threadUnsafeSet[T]) IsProperSuperset(other Set[T]) bool {
 }
 
 func (s *threadUnsafeSet[T]) IsSubset(other Set[T]) bool {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 	if s.Cardinality() > other.Cardinality() {
 		return false
 	}
@@ -245,6 +274,41 @@ func (s *threadUnsafeSet[T]) IsSuperset(other Set[T]) bool {
 	return other.IsSubset(s)
 }
 
+func (s *threadUnsafeSet[T]) PartitionN(n int, hash func(T) uint64) []Set[T] {
+	shards := s.partitionN(n, hash)
+	if shards == nil {
+		return nil
+	}
+
+	parts := make([]Set[T], n)
+	for i := range shards {
+		parts[i] = shards[i]
+	}
+	return parts
+}
+
+// private version of PartitionN which returns the concrete shards
+func (s *threadUnsafeSet[T]) partitionN(n int, hash func(T) uint64) []*threadUnsafeSet[T] {
+	if n <= 0 {
+		return nil
+	}
+
+	// presize every shard for an even split to avoid map growth
+	size := s.Cardinality()/n + 1
+	shards := make([]*threadUnsafeSet[T], n)
+	for i := range shards {
+		shards[i] = newThreadUnsafeSetWithSize[T](size)
+	}
+	for elem := range *s {
+		shards[hash(elem)%uint64(n)].add(elem)
+	}
+	return shards
+}
+
+func (s *threadUnsafeSet[T]) All() func(yield func(element T) bool) {
+	return Elements[T](s)
+}
+
 func (s *threadUnsafeSet[T]) Iter() <-chan T {
 	ch := make(chan T)
 	go func() {
@@ -294,7 +358,7 @@ func (s *threadUnsafeSet[T]) PopN(n int) (items []T, count int) {
 		n = sn
 	}
 
-	items = make([]T, 0, sn)
+	items = make([]T, 0, n)
 	for item := range *s {
 		if count >= n {
 			break
@@ -316,6 +380,47 @@ func (s threadUnsafeSet[T]) RemoveAll(i ...T) {
 	}
 }
 
+func (s threadUnsafeSet[T]) RemovedWhich(i ...T) []T {
+	removed := make([]T, 0, len(i))
+	for _, elem := range i {
+		if _, found := s[elem]; found {
+			delete(s, elem)
+			removed = append(removed, elem)
+		}
+	}
+	return removed
+}
+
+func (s threadUnsafeSet[T]) removeIf(pred func(T) bool) []T {
+	var removed []T
+	for elem := range s {
+		if pred(elem) {
+			delete(s, elem)
+			removed = append(removed, elem)
+		}
+	}
+	return removed
+}
+
+func (s threadUnsafeSet[T]) update(fn func(MutableView[T])) {
+	fn(mapView[T](s))
+}
+
+func (s threadUnsafeSet[T]) retainAll(o threadUnsafeSet[T]) int {
+	n := 0
+	for elem := range s {
+		if !o.contains(elem) {
+			delete(s, elem)
+			n++
+		}
+	}
+	return n
+}
+
+func (s *threadUnsafeSet[T]) ReconcileTo(target Set[T], add func(T) error, remove func(T) error) error {
+	return reconcile[T](s, target, add, remove)
+}
+
 func (s threadUnsafeSet[T]) String() string {
 	items := make([]string, 0, len(s))
 
@@ -325,20 +430,25 @@ func (s threadUnsafeSet[T]) String() string {
 	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
 }
 
+// Format implements fmt.Formatter, see formatSet.
+func (s threadUnsafeSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "NewThreadUnsafeSet", s.ToSlice())
+}
+
 func (s *threadUnsafeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
 	// maximum number of elements is the sum of s and o cardinalities (when s and o are disjoint)
-	n := s.Cardinality() + o.Cardinality()
+	n := len(*s) + len(*o)
 	sd := make(threadUnsafeSet[T], n)
 	for elem := range *s {
-		if !o.contains(elem) {
-			sd.add(elem)
+		if _, found := (*o)[elem]; !found {
+			sd[elem] = struct{}{}
 		}
 	}
 	for elem := range *o {
-		if !s.contains(elem) {
-			sd.add(elem)
+		if _, found := (*s)[elem]; !found {
+			sd[elem] = struct{}{}
 		}
 	}
 	return &sd
@@ -353,22 +463,43 @@ func (s threadUnsafeSet[T]) ToSlice() []T {
 	return keys
 }
 
+// appendTo appends the elements to dst, growing it at most once.
+func (s threadUnsafeSet[T]) appendTo(dst []T) []T {
+	if n := len(dst) + len(s); n > cap(dst) {
+		grown := make([]T, len(dst), n)
+		copy(grown, dst)
+		dst = grown
+	}
+	for elem := range s {
+		dst = append(dst, elem)
+	}
+	return dst
+}
+
 func (s threadUnsafeSet[T]) Union(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	o := unwrapSet(other).(*threadUnsafeSet[T])
 
 	// maximum number of elements is the sum of s and o cardinalities (when s and o are disjoint)
-	n := s.Cardinality() + o.Cardinality()
+	n := len(s) + len(*o)
 	unionedSet := make(threadUnsafeSet[T], n)
 
 	for elem := range s {
-		unionedSet.add(elem)
+		unionedSet[elem] = struct{}{}
 	}
 	for elem := range *o {
-		unionedSet.add(elem)
+		unionedSet[elem] = struct{}{}
 	}
 	return &unionedSet
 }
 
+func (s threadUnsafeSet[T]) CanonicalBytes() []byte {
+	encoded := make([][]byte, 0, len(s))
+	for elem := range s {
+		encoded = append(encoded, canonicalElement(elem))
+	}
+	return canonicalBytes(encoded)
+}
+
 // MarshalJSON creates a JSON array from the set, it marshals all elements
 func (s threadUnsafeSet[T]) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.ToSlice())
@@ -387,6 +518,52 @@ func (s *threadUnsafeSet[T]) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalBinary encodes the set as a snapshot.
+func (s *threadUnsafeSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary[T](s)
+}
+
+// UnmarshalBinary adds the elements of a snapshot to the set, creating it
+// if needed as gob decodes into zero values.
+func (s *threadUnsafeSet[T]) UnmarshalBinary(data []byte) error {
+	if *s == nil {
+		*s = make(threadUnsafeSet[T])
+	}
+	return ReadSnapshot[T](bytes.NewReader(data), s)
+}
+
+// MarshalText encodes the elements of the set as comma-separated text.
+func (s *threadUnsafeSet[T]) MarshalText() ([]byte, error) {
+	return EncodeText[T](s, DefaultTextSeparator)
+}
+
+// UnmarshalText adds the elements of comma-separated text to the set.
+func (s *threadUnsafeSet[T]) UnmarshalText(text []byte) error {
+	if *s == nil {
+		*s = make(threadUnsafeSet[T])
+	}
+	return DecodeText[T](text, s, DefaultTextSeparator)
+}
+
+// MarshalYAML returns the elements of the set as a slice, which YAML
+// encoders write as a sequence.
+func (s threadUnsafeSet[T]) MarshalYAML() (interface{}, error) {
+	return s.ToSlice(), nil
+}
+
+// UnmarshalYAML adds the elements of a YAML sequence to the set.
+func (s *threadUnsafeSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var i []T
+	if err := unmarshal(&i); err != nil {
+		return err
+	}
+	if *s == nil {
+		*s = make(threadUnsafeSet[T], len(i))
+	}
+	s.append(i...)
+	return nil
+}
+
 // MarshalBSON creates a BSON array from the set.
 func (s threadUnsafeSet[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(s.ToSlice())
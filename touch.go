@@ -0,0 +1,300 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TouchSet is a thread-safe set that remembers when each member was last
+// added or touched, so that members that haven't been referenced recently
+// can be found and expired without a separate bookkeeping map.
+//
+// Members are kept in recency order, so TouchedSince and IdleSince only
+// visit the members they return.
+type TouchSet[T comparable] struct {
+	mu sync.RWMutex
+	// members holds when each member was last touched.
+	members *recencyList[T, touchMeta]
+	now     func() time.Time
+	// seq numbers the touches, so that the order of members touched at the
+	// same time is known.
+	seq     uint64
+	windows map[time.Duration]*touchWindow[T]
+}
+
+type touchMeta struct {
+	touched time.Time
+	seq     uint64
+}
+
+// touchWindow counts the members touched within a trailing window, which
+// are the members of the recency list from the front to oldest.
+type touchWindow[T comparable] struct {
+	d      time.Duration
+	count  int
+	oldest *list.Element
+}
+
+// NewTouchSet creates and returns a new TouchSet with the given elements,
+// all touched now.
+func NewTouchSet[T comparable](vs ...T) *TouchSet[T] {
+	s := &TouchSet[T]{
+		members: newRecencyList[T, touchMeta](len(vs)),
+		now:     time.Now,
+	}
+	for _, v := range vs {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add adds an element to the set, or touches it if it is already present.
+// Returns whether the element was added.
+func (s *TouchSet[T]) Add(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.touch(v) {
+		return false
+	}
+	s.seq++
+	s.members.push(v, touchMeta{touched: s.now(), seq: s.seq})
+	for _, w := range s.windows {
+		w.count++
+		if w.oldest == nil {
+			w.oldest = s.members.order.Front()
+		}
+	}
+	return true
+}
+
+// Touch records that the element was referenced now. Returns whether the
+// element is in the set; absent elements are not added.
+func (s *TouchSet[T]) Touch(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.touch(v)
+}
+
+// touch must be called with the write lock held.
+func (s *TouchSet[T]) touch(v T) bool {
+	elem, found := s.members.members[v]
+	if !found {
+		return false
+	}
+	for _, w := range s.windows {
+		switch {
+		case !w.counts(elem):
+			w.count++
+			if w.oldest == nil {
+				w.oldest = elem
+			}
+		case elem == w.oldest && elem.Prev() != nil:
+			// the member moves to the front, so the oldest one counted is
+			// now the one touched after it
+			w.oldest = elem.Prev()
+		}
+	}
+
+	e, _ := s.members.touch(v)
+	s.seq++
+	e.meta = touchMeta{touched: s.now(), seq: s.seq}
+	return true
+}
+
+// remove must be called with the write lock held.
+func (s *TouchSet[T]) remove(v T) bool {
+	elem, found := s.members.members[v]
+	if !found {
+		return false
+	}
+	for _, w := range s.windows {
+		if w.counts(elem) {
+			w.count--
+			if elem == w.oldest {
+				w.oldest = elem.Prev()
+			}
+		}
+	}
+	return s.members.remove(v)
+}
+
+// counts reports whether the window counts the member of elem.
+func (w *touchWindow[T]) counts(elem *list.Element) bool {
+	return w.oldest != nil && touchMetaOf[T](elem).seq >= touchMetaOf[T](w.oldest).seq
+}
+
+// advance stops counting the members touched before the window ending at
+// now.
+func (w *touchWindow[T]) advance(now time.Time) {
+	since := now.Add(-w.d)
+	for w.oldest != nil && touchMetaOf[T](w.oldest).touched.Before(since) {
+		w.count--
+		w.oldest = w.oldest.Prev()
+	}
+}
+
+func touchMetaOf[T comparable](elem *list.Element) touchMeta {
+	return elem.Value.(*recencyEntry[T, touchMeta]).meta
+}
+
+// Contains returns whether the given element is in the set, without
+// touching it.
+func (s *TouchSet[T]) Contains(v T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, found := s.members.get(v)
+	return found
+}
+
+// LastTouched returns when the element was last added or touched, and
+// whether it is in the set.
+func (s *TouchSet[T]) LastTouched(v T) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, found := s.members.get(v)
+	if !found {
+		return time.Time{}, false
+	}
+	return e.meta.touched, true
+}
+
+// Remove removes a single element from the set.
+func (s *TouchSet[T]) Remove(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(v)
+}
+
+// Cardinality returns the number of elements in the set.
+func (s *TouchSet[T]) Cardinality() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.members.len()
+}
+
+// TouchedSince returns a new set with the members that were added or
+// touched at or after t.
+func (s *TouchSet[T]) TouchedSince(t time.Time) Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	touched := newThreadSafeSet[T]()
+	s.members.newestFirst(func(e *recencyEntry[T, touchMeta]) bool {
+		if e.meta.touched.Before(t) {
+			return true
+		}
+		touched.uss.add(e.value)
+		return false
+	})
+	return touched
+}
+
+// IdleSince returns a new set with the members that were not added or
+// touched since t.
+func (s *TouchSet[T]) IdleSince(t time.Time) Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	idle := newThreadSafeSet[T]()
+	s.members.oldestFirst(func(e *recencyEntry[T, touchMeta]) bool {
+		if !e.meta.touched.Before(t) {
+			return true
+		}
+		idle.uss.add(e.value)
+		return false
+	})
+	return idle
+}
+
+// RemoveIdleSince removes the members that were not added or touched since
+// t, and returns them as a new set.
+func (s *TouchSet[T]) RemoveIdleSince(t time.Time) Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idle := newThreadSafeSet[T]()
+	for e, ok := s.members.oldest(); ok && e.meta.touched.Before(t); e, ok = s.members.oldest() {
+		s.remove(e.value)
+		idle.uss.add(e.value)
+	}
+	return idle
+}
+
+// DistinctOverWindows returns, for every trailing window, the number of
+// members added or touched within it, that is at or after now minus the
+// window, such as the distinct counts over the last minute, 5 minutes and
+// hour emitted by deduplication stages of streaming systems.
+//
+// Every distinct window is counted from its first use on and then
+// maintained incrementally: adding, touching and removing members cost
+// O(1) per window and a call only visits the members that left a window
+// since the previous one.
+func (s *TouchSet[T]) DistinctOverWindows(windows []time.Duration) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	counts := make([]int, len(windows))
+	for i, d := range windows {
+		w, found := s.windows[d]
+		if !found {
+			w = s.newWindow(d)
+		}
+		w.advance(now)
+		counts[i] = w.count
+	}
+	return counts
+}
+
+// newWindow must be called with the write lock held.
+func (s *TouchSet[T]) newWindow(d time.Duration) *touchWindow[T] {
+	w := &touchWindow[T]{d: d}
+	// count every member for now, advance drops the ones out of the window
+	w.count = s.members.len()
+	w.oldest = s.members.order.Back()
+	if s.windows == nil {
+		s.windows = make(map[time.Duration]*touchWindow[T])
+	}
+	s.windows[d] = w
+	return w
+}
+
+// ToSet returns the members of the set as a new thread-safe Set.
+func (s *TouchSet[T]) ToSet() Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.members.toSet()
+}
@@ -0,0 +1,157 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_TouchSet(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := NewTouchSet[string]()
+	now, advance := manualClock(start)
+	s.now = now
+
+	for _, v := range []string{"a", "b", "c"} {
+		advance(time.Second)
+		s.Add(v)
+	} // a, b and c added at 1s, 2s and 3s
+	advance(time.Second)
+	if s.Add("a") {
+		t.Error("adding an existing element should report false")
+	} // a touched at 4s
+	advance(time.Second)
+	if !s.Touch("b") {
+		t.Error("touching an existing element should report true")
+	} // b touched at 5s
+	if s.Touch("d") {
+		t.Error("touching a missing element should report false")
+	}
+
+	if s.Cardinality() != 3 || !s.Contains("c") || s.Contains("d") {
+		t.Error("Touch should not add elements")
+	}
+	if at, _ := s.LastTouched("a"); !at.Equal(start.Add(4 * time.Second)) {
+		t.Errorf("expected a to be last touched at 4s, got %v", at.Sub(start))
+	}
+
+	since := start.Add(4 * time.Second)
+	if touched := s.TouchedSince(since); !touched.Equal(NewSet("a", "b")) {
+		t.Errorf("expected {a, b} touched since 4s, got %v", touched)
+	}
+	if idle := s.IdleSince(since); !idle.Equal(NewSet("c")) {
+		t.Errorf("expected {c} idle since 4s, got %v", idle)
+	}
+
+	if removed := s.RemoveIdleSince(start.Add(5 * time.Second)); !removed.Equal(NewSet("a", "c")) {
+		t.Errorf("expected {a, c} to be removed, got %v", removed)
+	}
+	if !s.ToSet().Equal(NewSet("b")) {
+		t.Errorf("expected only b to remain, got %v", s.ToSet())
+	}
+
+	s.Remove("b")
+	if s.Cardinality() != 0 || !s.IdleSince(start.Add(time.Hour)).IsEmpty() {
+		t.Error("expected the set to be empty")
+	}
+}
+
+func Test_TouchSetDistinctOverWindows(t *testing.T) {
+	s := NewTouchSet[int]()
+	now, advance := manualClock(time.Unix(0, 0))
+	s.now = now
+
+	windows := []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		// a zero step touches several members at the same time
+		advance(time.Duration(r.Intn(3)) * time.Second)
+		v := r.Intn(300)
+		switch r.Intn(10) {
+		case 0, 1, 2, 3:
+			s.Add(v)
+		case 4, 5, 6:
+			s.Touch(v)
+		case 7, 8:
+			s.Remove(v)
+		case 9:
+			s.RemoveIdleSince(now().Add(-30 * time.Minute))
+		}
+
+		if i%50 != 0 && i < 4900 {
+			continue
+		}
+		if i == 4900 {
+			// a window first used on a populated set
+			windows = append(windows, 10*time.Minute)
+		}
+		counts := s.DistinctOverWindows(windows)
+		for j, d := range windows {
+			if want := s.TouchedSince(now().Add(-d)).Cardinality(); counts[j] != want {
+				t.Fatalf("step %d: expected %d members touched within %v, got %d", i, want, d, counts[j])
+			}
+		}
+	}
+
+	advance(2 * time.Hour)
+	if counts := s.DistinctOverWindows(windows); counts[0] != 0 || counts[2] != 0 {
+		t.Errorf("expected every window to be empty, got %v", counts)
+	}
+}
+
+func Test_TouchSetConcurrent(t *testing.T) {
+	s := NewTouchSet[int]()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < N; i++ {
+			s.Add(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < N; i++ {
+			s.Touch(i)
+			s.TouchedSince(time.Now().Add(-time.Minute))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < N; i++ {
+			s.RemoveIdleSince(time.Unix(0, 0))
+		}
+	}()
+	wg.Wait()
+
+	if s.Cardinality() != N {
+		t.Errorf("expected %d elements, got %d", N, s.Cardinality())
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// MutableView is the unsynchronized access to a set given to the function
+// run by Update.
+type MutableView[T comparable] interface {
+	// Add adds v and reports whether it was absent.
+	Add(v T) bool
+	// Remove removes v and reports whether it was present.
+	Remove(v T) bool
+	// Contains reports whether v is in the set.
+	Contains(v T) bool
+	// Cardinality returns the number of elements in the set.
+	Cardinality() int
+}
+
+// Update runs fn with a view of s and applies the changes it makes to s
+// atomically, so that compound operations such as removing an element and
+// then adding another one if absent can't interleave with the operations
+// of other goroutines. The sets created by NewSet, NewSortedSet and
+// NewShardedSet hold their write lock while fn runs, all of them for a
+// ShardedSet, so fn must not use s nor keep the view after it returns.
+//
+// The changes go through the decorators of s: they are validated,
+// observed and persisted like the changes of other mutations, except that
+// a set bounded with WithMaxCardinality rejects elements beyond its
+// maximum whatever its FullPolicy. For other implementations of Set, fn
+// uses s directly and the changes are not atomic.
+func Update[T comparable](s Set[T], fn func(tx MutableView[T])) {
+	if u, ok := implementation[interface{ update(func(MutableView[T])) }](s); ok {
+		u.update(fn)
+		return
+	}
+	fn(setView[T]{s})
+}
+
+// mapView is the view of the elements of a thread-unsafe set.
+type mapView[T comparable] threadUnsafeSet[T]
+
+func (m mapView[T]) Add(v T) bool {
+	if _, ok := m[v]; ok {
+		return false
+	}
+	m[v] = struct{}{}
+	return true
+}
+
+func (m mapView[T]) Remove(v T) bool {
+	if _, ok := m[v]; !ok {
+		return false
+	}
+	delete(m, v)
+	return true
+}
+
+func (m mapView[T]) Contains(v T) bool {
+	_, ok := m[v]
+	return ok
+}
+
+func (m mapView[T]) Cardinality() int {
+	return len(m)
+}
+
+// setView is the view of a set that applies each change on its own.
+type setView[T comparable] struct {
+	s Set[T]
+}
+
+func (v setView[T]) Add(elem T) bool {
+	return v.s.Add(elem)
+}
+
+func (v setView[T]) Remove(elem T) bool {
+	return len(RemovedWhich(v.s, elem)) > 0
+}
+
+func (v setView[T]) Contains(elem T) bool {
+	return v.s.ContainsOne(elem)
+}
+
+func (v setView[T]) Cardinality() int {
+	return v.s.Cardinality()
+}
+
+// changesView forwards to a view and records the net changes applied
+// through it.
+type changesView[T comparable] struct {
+	MutableView[T]
+	changes map[T]Op
+}
+
+func (v *changesView[T]) record(elem T, op Op) {
+	// the changes of an element alternate, so a change cancels the
+	// recorded opposite one
+	if _, ok := v.changes[elem]; ok {
+		delete(v.changes, elem)
+	} else {
+		v.changes[elem] = op
+	}
+}
+
+func (v *changesView[T]) Add(elem T) bool {
+	if !v.MutableView.Add(elem) {
+		return false
+	}
+	v.record(elem, OpAdd)
+	return true
+}
+
+func (v *changesView[T]) Remove(elem T) bool {
+	if !v.MutableView.Remove(elem) {
+		return false
+	}
+	v.record(elem, OpRemove)
+	return true
+}
+
+// split returns the elements added and removed.
+func (v *changesView[T]) split() (added, removed []T) {
+	for elem, op := range v.changes {
+		if op == OpAdd {
+			added = append(added, elem)
+		} else {
+			removed = append(removed, elem)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,119 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_Update(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2)
+		Update(s, func(tx MutableView[int]) {
+			if tx.Remove(1) && !tx.Contains(3) {
+				tx.Add(3)
+			}
+			if tx.Add(2) || tx.Remove(9) || tx.Cardinality() != 2 {
+				t.Error("unexpected result of operations on the view")
+			}
+		})
+		if !s.Equal(ctor(2, 3)) {
+			t.Errorf("Expected {2, 3}, got: %v", s)
+		}
+
+		Update[int](foreignSet[int]{s}, func(tx MutableView[int]) {
+			tx.Remove(2)
+			tx.Add(4)
+		})
+		if s.Cardinality() != 2 || !s.Contains(3, 4) {
+			t.Errorf("Expected {3, 4}, got: %v", s)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_UpdateAtomic(t *testing.T) {
+	s := NewSet(0)
+
+	// swapping the element under Update never leaves the set empty
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				Update(s, func(tx MutableView[int]) {
+					if tx.Remove(0) {
+						tx.Add(1)
+					} else if tx.Remove(1) {
+						tx.Add(0)
+					}
+				})
+			}
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		if n := s.Cardinality(); n != 1 {
+			t.Fatalf("Expected a single element, got: %d", n)
+		}
+	}
+	wg.Wait()
+}
+
+func Test_UpdateDecorators(t *testing.T) {
+	h := NewHistorySet[int](NewSet(1), 10)
+	Update[int](h, func(tx MutableView[int]) {
+		tx.Add(2)
+		tx.Remove(1)
+		tx.Add(3)
+		tx.Remove(3)
+	})
+	if got := h.History(); len(got) != 2 || got[0].Op != OpRemove || got[1].Op != OpAdd || got[1].Elems[0] != 2 {
+		t.Errorf("Expected the removal of 1 and the addition of 2, got: %v", got)
+	}
+
+	g := NewSetWithOptions(RejectZero[int](), WithMaxCardinality[int](2, EvictArbitrary[int]()))
+	g.Add(1)
+	Update(g, func(tx MutableView[int]) {
+		if tx.Add(0) {
+			t.Error("the zero value should be rejected")
+		}
+		tx.Add(2)
+		if tx.Add(3) {
+			t.Error("the full set should reject new elements")
+		}
+	})
+	if !g.Equal(NewSet(1, 2)) {
+		t.Errorf("Expected {1, 2}, got: %v", g)
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// VersionedSet is a set that records its mutations as successive versions,
+// so that they can be undone and redone, for instance by an editing user
+// interface, and so that past versions can be read back. Only operations
+// that actually changed the set create a version; a bulk operation such as
+// Append, or the changes of an Update, make a single version.
+//
+// Undoing and then mutating the set discards the versions that could have
+// been redone. Mutations, Undo and Redo are serialized; read operations run
+// concurrently.
+type VersionedSet[T comparable] struct {
+	Set[T]
+
+	base  Set[T]
+	limit int
+
+	mu sync.Mutex
+	// changes[i] turns version first+i into version first+i+1
+	changes [][]Mutation[T]
+	first   int
+	version int
+	// group collects the mutations of an Update while grouping is set
+	grouping bool
+	group    []Mutation[T]
+	now      func() time.Time
+}
+
+// NewVersionedSet returns a set that forwards all operations to s and
+// records its mutations as versions, starting at version 0. At most limit
+// versions can be undone, the older ones being discarded, and a
+// non-positive limit keeps all of them.
+//
+// Mutations must go through the returned set to be recorded; s itself
+// should no longer be used directly.
+func NewVersionedSet[T comparable](s Set[T], limit int) *VersionedSet[T] {
+	v := &VersionedSet[T]{base: s, limit: limit, now: time.Now}
+	v.Set = newObservedSet(s, v.record)
+	return v
+}
+
+func (v *VersionedSet[T]) unwrap() Set[T] {
+	return v.Set
+}
+
+// record is the observer of the set; it is called with mu held, as every
+// mutation holds it.
+func (v *VersionedSet[T]) record(op Op, vs []T) {
+	m := Mutation[T]{Op: op, Elems: vs, Time: v.now()}
+	if v.grouping {
+		v.group = append(v.group, m)
+		return
+	}
+	v.push([]Mutation[T]{m})
+}
+
+// push records a new version, discarding the versions that could have
+// been redone and the ones beyond the limit.
+func (v *VersionedSet[T]) push(change []Mutation[T]) {
+	v.changes = append(v.changes[:v.version-v.first], change)
+	v.version++
+	if v.limit > 0 && len(v.changes) > v.limit {
+		v.changes[0] = nil
+		v.changes = v.changes[1:]
+		v.first++
+	}
+}
+
+// Version returns the current version of the set.
+func (v *VersionedSet[T]) Version() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.version
+}
+
+// Undo reverts the mutations of the current version, going back to the
+// previous version, and reports whether there was one to go back to.
+func (v *VersionedSet[T]) Undo() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.version == v.first {
+		return false
+	}
+	v.version--
+	applyChange(v.base, v.changes[v.version-v.first], false)
+	return true
+}
+
+// Redo applies again the mutations of the next version, after Undo, and
+// reports whether there was one to apply.
+func (v *VersionedSet[T]) Redo() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.version == v.first+len(v.changes) {
+		return false
+	}
+	applyChange(v.base, v.changes[v.version-v.first], true)
+	v.version++
+	return true
+}
+
+// At returns a copy of the set as it was at the given version, which may
+// also be a version that can be redone, or false if the version is not
+// recorded.
+func (v *VersionedSet[T]) At(version int) (Set[T], bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if version < v.first || version > v.first+len(v.changes) {
+		return nil, false
+	}
+	s := v.base.Clone()
+	for i := v.version; i > version; i-- {
+		applyChange(s, v.changes[i-1-v.first], false)
+	}
+	for i := v.version; i < version; i++ {
+		applyChange(s, v.changes[i-v.first], true)
+	}
+	return s, true
+}
+
+// applyChange applies the mutations of change to s, or reverts them in
+// reverse order if forward is false.
+func applyChange[T comparable](s Set[T], change []Mutation[T], forward bool) {
+	for i := range change {
+		m := change[i]
+		if !forward {
+			m = change[len(change)-1-i]
+		}
+		if (m.Op == OpAdd) == forward {
+			s.Append(m.Elems...)
+		} else {
+			s.RemoveAll(m.Elems...)
+		}
+	}
+}
+
+func (v *VersionedSet[T]) Add(val T) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.Set.Add(val)
+}
+
+func (v *VersionedSet[T]) Append(vals ...T) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.Set.Append(vals...)
+}
+
+func (v *VersionedSet[T]) AppendFrom(other Set[T]) int {
+	// other may be v, whose elements are read without the lock
+	vals := other.ToSlice()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.Set.Append(vals...)
+}
+
+func (v *VersionedSet[T]) Clear() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.Set.Clear()
+}
+
+func (v *VersionedSet[T]) Remove(val T) {
+	v.RemovedWhich(val)
+}
+
+func (v *VersionedSet[T]) RemoveAll(vals ...T) {
+	v.RemovedWhich(vals...)
+}
+
+func (v *VersionedSet[T]) RemovedWhich(vals ...T) []T {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return RemovedWhich(v.Set, vals...)
+}
+
+func (v *VersionedSet[T]) removeIf(pred func(T) bool) []T {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return removeIf(v.Set, pred)
+}
+
+func (v *VersionedSet[T]) update(fn func(MutableView[T])) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.grouping = true
+	defer func() {
+		v.grouping = false
+		if len(v.group) > 0 {
+			v.push(v.group)
+		}
+		v.group = nil
+	}()
+	Update(v.Set, fn)
+}
+
+func (v *VersionedSet[T]) Pop() (T, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.Set.Pop()
+}
+
+func (v *VersionedSet[T]) PopN(n int) ([]T, int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.Set.PopN(n)
+}
+
+func (v *VersionedSet[T]) UnmarshalJSON(b []byte) error {
+	return setCodec[T]{v}.UnmarshalJSON(b)
+}
+
+func (v *VersionedSet[T]) MarshalBinary() ([]byte, error) {
+	return setCodec[T]{v}.MarshalBinary()
+}
+
+func (v *VersionedSet[T]) UnmarshalBinary(data []byte) error {
+	return setCodec[T]{v}.UnmarshalBinary(data)
+}
+
+func (v *VersionedSet[T]) MarshalText() ([]byte, error) {
+	return setCodec[T]{v}.MarshalText()
+}
+
+func (v *VersionedSet[T]) UnmarshalText(text []byte) error {
+	return setCodec[T]{v}.UnmarshalText(text)
+}
+
+func (v *VersionedSet[T]) MarshalYAML() (interface{}, error) {
+	return setCodec[T]{v}.MarshalYAML()
+}
+
+func (v *VersionedSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return setCodec[T]{v}.UnmarshalYAML(unmarshal)
+}
+
+func (v *VersionedSet[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return setCodec[T]{v}.UnmarshalBSONValue(bt, b)
+}
@@ -0,0 +1,117 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_VersionedSet(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := NewVersionedSet(ctor(1), 0)
+		s.Append(2, 3) // version 1
+		s.Add(2)       // no change
+		s.Remove(1)    // version 2
+		s.Clear()      // version 3
+		if s.Version() != 3 {
+			t.Fatalf("Expected version 3, got: %d", s.Version())
+		}
+
+		for _, want := range []Set[int]{ctor(2, 3), ctor(1, 2, 3), ctor(1)} {
+			if !s.Undo() || !s.Equal(want) {
+				t.Errorf("Expected %v after Undo, got: %v", want, s)
+			}
+		}
+		if s.Undo() || s.Version() != 0 {
+			t.Errorf("Expected nothing to undo at version 0, got version %d", s.Version())
+		}
+
+		if !s.Redo() || !s.Equal(ctor(1, 2, 3)) {
+			t.Errorf("Expected {1, 2, 3} after Redo, got: %v", s)
+		}
+		if at, ok := s.At(2); !ok || !at.Equal(ctor(2, 3)) {
+			t.Errorf("Expected {2, 3} at version 2, got: %v", at)
+		}
+		if at, ok := s.At(0); !ok || !at.Equal(ctor(1)) {
+			t.Errorf("Expected {1} at version 0, got: %v", at)
+		}
+		if _, ok := s.At(4); ok {
+			t.Error("version 4 should not exist")
+		}
+
+		// mutating after Undo discards the versions that could be redone
+		s.Add(4)
+		if s.Redo() || s.Version() != 2 || !s.Equal(ctor(1, 2, 3, 4)) {
+			t.Errorf("Expected {1, 2, 3, 4} at version 2 without redo, got: %v at %d", s, s.Version())
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_VersionedSetLimit(t *testing.T) {
+	s := NewVersionedSet(NewSet[int](), 2)
+	for i := 0; i < 5; i++ {
+		s.Add(i)
+	}
+	if _, ok := s.At(2); ok {
+		t.Error("version 2 should be discarded")
+	}
+	if !s.Undo() || !s.Undo() || s.Undo() {
+		t.Error("Expected exactly 2 versions to undo")
+	}
+	if !s.Equal(NewSet(0, 1, 2)) || s.Version() != 3 {
+		t.Errorf("Expected {0, 1, 2} at version 3, got: %v at %d", s, s.Version())
+	}
+}
+
+func Test_VersionedSetUpdate(t *testing.T) {
+	s := NewVersionedSet(NewSet(1), 0)
+	Update[int](s, func(tx MutableView[int]) {
+		tx.Remove(1)
+		tx.Add(2)
+	})
+	if s.Version() != 1 || !s.Equal(NewSet(2)) {
+		t.Fatalf("Expected {2} at version 1, got: %v at %d", s, s.Version())
+	}
+	if n := RetainAll[int](s, NewSet[int]()); n != 1 || s.Version() != 2 {
+		t.Fatalf("Expected 1 removal at version 2, got: %d at %d", n, s.Version())
+	}
+	if !s.Undo() || !s.Equal(NewSet(2)) {
+		t.Errorf("Expected {2}, got: %v", s)
+	}
+	if !s.Undo() || !s.Equal(NewSet(1)) {
+		t.Errorf("Expected the whole update to be undone, got: %v", s)
+	}
+	if !Move[int](s, NewSet[int](), 1) || s.Version() != 1 {
+		t.Errorf("Expected the move to make a version, got: %d", s.Version())
+	}
+}
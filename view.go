@@ -0,0 +1,138 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetView is the part of Set that reads the elements of a single set, with
+// no other set as operand. It is implemented by every Set and by the sets
+// that are only views of elements, such as the ones returned by FilterView
+// and Frozen, which can't be the operand of the methods of a Set. See
+// ReadOnlySet, which adds the predicates comparing two sets.
+type SetView[T comparable] interface {
+	// Cardinality returns the number of elements in the set.
+	Cardinality() int
+
+	// Contains returns whether the given items
+	// are all in the set.
+	Contains(val ...T) bool
+
+	// ContainsOne returns whether the given item
+	// is in the set.
+	//
+	// Contains may cause the argument to escape to the heap.
+	// See: https://github.com/deckarep/golang-set/issues/118
+	ContainsOne(val T) bool
+
+	// Each iterates over elements and executes the passed func against each element.
+	// If passed func returns true, stop iteration at the time.
+	Each(func(T) bool)
+
+	// IsEmpty determines if there are elements in the set.
+	IsEmpty() bool
+
+	// String provides a convenient string representation
+	// of the current state of the set.
+	String() string
+
+	// ToSlice returns the members of the set as a slice.
+	ToSlice() []T
+}
+
+// FilterView returns a live, read-only view of the elements of s that
+// satisfy pred. The view is never copied: every call reflects the current
+// contents of s, so derived subsets stay up to date without any
+// copy-on-change code. pred must be safe for concurrent use if the view is
+// used concurrently, and must not call back into s.
+//
+// Membership checks cost as much as on s, while Cardinality, Each and
+// ToSlice visit all the elements of s.
+func FilterView[T comparable](s Set[T], pred func(T) bool) SetView[T] {
+	return &filterView[T]{parent: s, pred: pred}
+}
+
+type filterView[T comparable] struct {
+	parent Set[T]
+	pred   func(T) bool
+}
+
+func (v *filterView[T]) Cardinality() int {
+	n := 0
+	v.Each(func(T) bool {
+		n++
+		return false
+	})
+	return n
+}
+
+func (v *filterView[T]) Contains(val ...T) bool {
+	for _, elem := range val {
+		if !v.pred(elem) {
+			return false
+		}
+	}
+	return v.parent.Contains(val...)
+}
+
+func (v *filterView[T]) ContainsOne(val T) bool {
+	return v.pred(val) && v.parent.ContainsOne(val)
+}
+
+func (v *filterView[T]) Each(cb func(T) bool) {
+	v.parent.Each(func(elem T) bool {
+		return v.pred(elem) && cb(elem)
+	})
+}
+
+func (v *filterView[T]) IsEmpty() bool {
+	empty := true
+	v.Each(func(T) bool {
+		empty = false
+		return true
+	})
+	return empty
+}
+
+func (v *filterView[T]) String() string {
+	var items []string
+	v.Each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+func (v *filterView[T]) ToSlice() []T {
+	var elems []T
+	v.Each(func(elem T) bool {
+		elems = append(elems, elem)
+		return false
+	})
+	return elems
+}
@@ -0,0 +1,86 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_FilterView(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3, 4)
+		even := FilterView(s, func(v int) bool { return v%2 == 0 })
+
+		if even.Cardinality() != 2 || even.IsEmpty() {
+			t.Errorf("expected 2 even elements, got %v", even)
+		}
+		if !even.Contains(2, 4) || even.Contains(2, 3) || even.ContainsOne(3) || even.ContainsOne(6) {
+			t.Error("unexpected membership")
+		}
+
+		// the view follows the changes of the parent
+		s.Append(6, 7)
+		s.Remove(2)
+		if !NewSet(even.ToSlice()...).Equal(NewSet(4, 6)) {
+			t.Errorf("expected the view to hold 4 and 6, got %v", even)
+		}
+		if str := even.String(); str != "Set{4, 6}" && str != "Set{6, 4}" {
+			t.Errorf("unexpected string: %s", str)
+		}
+
+		n := 0
+		even.Each(func(int) bool {
+			n++
+			return true
+		})
+		if n != 1 {
+			t.Errorf("Each should stop when the callback returns true, got %d calls", n)
+		}
+
+		s.RemoveAll(4, 6)
+		if !even.IsEmpty() || even.Cardinality() != 0 || len(even.ToSlice()) != 0 {
+			t.Errorf("expected the view to be empty, got %v", even)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_SetView(t *testing.T) {
+	// every set is a SetView
+	var sets []SetView[int]
+	sets = append(sets, NewSet(1), NewThreadUnsafeSet(1), FilterView(NewSet(1), func(int) bool { return true }))
+	for _, s := range sets {
+		if !s.ContainsOne(1) {
+			t.Errorf("%v should contain 1", s)
+		}
+	}
+}
@@ -0,0 +1,474 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Names of the files of a WALSet directory.
+const (
+	walSnapshotFile = "snapshot"
+	walLogFile      = "wal"
+)
+
+// maxWALRecord bounds the length of log records, so that a corrupt length
+// doesn't make OpenWALSet allocate huge buffers.
+const maxWALRecord = 1 << 30
+
+// CompactionPolicy controls when a WALSet compacts its log, by writing a
+// snapshot of the set and truncating the log. A zero field disables the
+// corresponding limit, and the zero policy only compacts on Compact.
+type CompactionPolicy struct {
+	// MaxLogSize compacts the log once it holds more than this many bytes.
+	MaxLogSize int64
+	// MaxLogAge compacts the log once its oldest record is older than this.
+	// The age is only checked when a mutation is logged, since the log
+	// doesn't grow otherwise, and records replayed by OpenWALSet count as
+	// written when the set was opened.
+	MaxLogAge time.Duration
+}
+
+// WALSet is a thread-safe set persisted in a directory: a snapshot of the
+// set, as written by WriteSnapshot, and a write-ahead log of the mutations
+// applied since the snapshot. Every mutation is appended to the log before
+// the mutating method returns, and the log is compacted according to a
+// CompactionPolicy so that long-running sets don't accumulate unbounded
+// logs. The elements are encoded with encoding/gob.
+//
+// Records are written to the operating system on every mutation and
+// flushed to stable storage by Sync, Compact and Close. A record torn by a
+// crash is dropped when the set is opened again.
+//
+// Mutations are serialized so that the log replays them in the order they
+// were applied; read operations run concurrently. Writing the log can't
+// fail a mutation, so the first write error is kept and reported by Err
+// and Close, and the set keeps working in memory.
+type WALSet[T comparable] struct {
+	Set[T]
+
+	mu     sync.Mutex
+	dir    string
+	policy CompactionPolicy
+	log    *os.File
+	size   int64
+	// oldest is when the first record of the log was written, or the zero
+	// time if the log is empty.
+	oldest time.Time
+	err    error
+}
+
+// OpenWALSet opens the set persisted in dir, creating dir if needed. The
+// snapshot is loaded and the log replayed on top of it, and the set then
+// logs its mutations to dir until it is closed. dir must not be used by
+// another WALSet at the same time.
+func OpenWALSet[T comparable](dir string, policy CompactionPolicy) (*WALSet[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	base := newThreadSafeSet[T]()
+	if err := readWALSnapshot[T](filepath.Join(dir, walSnapshotFile), base); err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, walLogFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	size, err := replayWAL[T](log, base)
+	if err == nil {
+		// drop a record torn by a crash, and append after the last good one
+		err = log.Truncate(size)
+	}
+	if err == nil {
+		_, err = log.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	w := &WALSet[T]{dir: dir, policy: policy, log: log, size: size}
+	if size > 0 {
+		w.oldest = time.Now()
+	}
+	w.Set = newObservedSet[T](base, w.append)
+	return w, nil
+}
+
+func readWALSnapshot[T comparable](path string, s Set[T]) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return ReadSnapshot[T](f, s)
+}
+
+// replayWAL applies the records of log to s and returns the length of the
+// well-formed prefix of log.
+func replayWAL[T comparable](log io.Reader, s Set[T]) (int64, error) {
+	br := bufio.NewReader(log)
+	var size int64
+	for {
+		op, vs, n, err := readWALRecord[T](br)
+		if err == io.EOF {
+			return size, nil
+		}
+		if err != nil {
+			var corrupt walCorruptError
+			if errors.As(err, &corrupt) {
+				return size, nil
+			}
+			return 0, err
+		}
+		switch op {
+		case OpAdd:
+			s.Append(vs...)
+		case OpRemove:
+			s.RemoveAll(vs...)
+		}
+		size += n
+	}
+}
+
+// walCorruptError reports a truncated or corrupt log record.
+type walCorruptError struct {
+	reason string
+}
+
+func (e walCorruptError) Error() string {
+	return "mapset: corrupt log record: " + e.reason
+}
+
+// readWALRecord reads a record, made of the length of its payload as a
+// uvarint, the CRC-32 of the payload and the payload itself: the Op
+// followed by the gob encoding of the elements. It returns the size of
+// the record, and io.EOF if r is at the end of the log.
+func readWALRecord[T comparable](r *bufio.Reader) (Op, []T, int64, error) {
+	length, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return 0, nil, 0, io.EOF
+	}
+	if err != nil || length == 0 || length > maxWALRecord {
+		return 0, nil, 0, walCorruptError{"invalid length"}
+	}
+
+	record := make([]byte, 4+length)
+	if _, err := io.ReadFull(r, record); err != nil {
+		return 0, nil, 0, walCorruptError{"truncated"}
+	}
+	payload := record[4:]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(record) {
+		return 0, nil, 0, walCorruptError{"checksum mismatch"}
+	}
+
+	var vs []T
+	if err := gob.NewDecoder(bytes.NewReader(payload[1:])).Decode(&vs); err != nil {
+		return 0, nil, 0, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	n := binary.PutUvarint(make([]byte, binary.MaxVarintLen64), length)
+	return Op(payload[0]), vs, int64(n) + int64(len(record)), nil
+}
+
+func encodeWALRecord[T comparable](op Op, vs []T) ([]byte, error) {
+	var payload bytes.Buffer
+	payload.WriteByte(byte(op))
+	if err := gob.NewEncoder(&payload).Encode(vs); err != nil {
+		return nil, err
+	}
+
+	record := make([]byte, binary.MaxVarintLen64+4, binary.MaxVarintLen64+4+payload.Len())
+	n := binary.PutUvarint(record, uint64(payload.Len()))
+	binary.LittleEndian.PutUint32(record[n:], crc32.ChecksumIEEE(payload.Bytes()))
+	return append(record[:n+4], payload.Bytes()...), nil
+}
+
+// append logs a mutation. It is called by the observed set, with the lock
+// held by the mutating method.
+func (w *WALSet[T]) append(op Op, vs []T) {
+	if w.err != nil || w.log == nil {
+		return
+	}
+	record, err := encodeWALRecord(op, vs)
+	if err == nil {
+		_, err = w.log.Write(record)
+	}
+	if err != nil {
+		w.err = err
+		return
+	}
+
+	w.size += int64(len(record))
+	if w.oldest.IsZero() {
+		w.oldest = time.Now()
+	}
+	if (w.policy.MaxLogSize > 0 && w.size > w.policy.MaxLogSize) ||
+		(w.policy.MaxLogAge > 0 && time.Since(w.oldest) > w.policy.MaxLogAge) {
+		w.err = w.compact()
+	}
+}
+
+// Compact writes a snapshot of the set and truncates the log. The snapshot
+// is written to a temporary file that atomically replaces the previous
+// one, so a crash at any point leaves either the old snapshot and the full
+// log, or the new snapshot, possibly with records it already reflects,
+// which replay to the same set.
+func (w *WALSet[T]) Compact() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.log == nil {
+		return os.ErrClosed
+	}
+	return w.compact()
+}
+
+// compact must be called with the lock held.
+func (w *WALSet[T]) compact() error {
+	tmp, err := os.CreateTemp(w.dir, walSnapshotFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = WriteSnapshot[T](tmp, w.Set)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(w.dir, walSnapshotFile))
+	}
+	if err == nil {
+		err = syncDir(w.dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := w.log.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.log.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.size, w.oldest = 0, time.Time{}
+	return w.log.Sync()
+}
+
+// syncDir flushes the entries of dir, such as a renamed file, to stable
+// storage. Some platforms can't sync directories, which is not an error.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return nil
+}
+
+// Sync flushes the log to stable storage.
+func (w *WALSet[T]) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.log == nil {
+		return os.ErrClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	return w.log.Sync()
+}
+
+// Err returns the first error met while writing the log or compacting it.
+func (w *WALSet[T]) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// Close syncs and closes the log. The set can still be used in memory, but
+// its mutations are no longer persisted. Close returns the first error met
+// while writing the log, if any.
+func (w *WALSet[T]) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.log == nil {
+		return os.ErrClosed
+	}
+	err := w.log.Sync()
+	if closeErr := w.log.Close(); err == nil {
+		err = closeErr
+	}
+	w.log = nil
+	if w.err != nil {
+		return w.err
+	}
+	return err
+}
+
+func (w *WALSet[T]) unwrap() Set[T] {
+	return w.Set
+}
+
+// Capabilities reports the set as thread-safe and persistent.
+func (w *WALSet[T]) Capabilities() Capabilities {
+	return CapThreadSafe | CapPersistent
+}
+
+func (w *WALSet[T]) Add(v T) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.Set.Add(v)
+}
+
+func (w *WALSet[T]) Append(vs ...T) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.Set.Append(vs...)
+}
+
+func (w *WALSet[T]) AppendFrom(other Set[T]) int {
+	// other may be w, whose elements are read without the lock
+	vs := other.ToSlice()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.Set.Append(vs...)
+}
+
+func (w *WALSet[T]) Clear() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.Set.Clear()
+}
+
+func (w *WALSet[T]) Remove(v T) {
+	w.RemovedWhich(v)
+}
+
+func (w *WALSet[T]) RemoveAll(vs ...T) {
+	w.RemovedWhich(vs...)
+}
+
+func (w *WALSet[T]) RemovedWhich(vs ...T) []T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return RemovedWhich(w.Set, vs...)
+}
+
+func (w *WALSet[T]) removeIf(pred func(T) bool) []T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return removeIf(w.Set, pred)
+}
+
+func (w *WALSet[T]) update(fn func(MutableView[T])) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	Update(w.Set, fn)
+}
+
+func (w *WALSet[T]) Pop() (T, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.Set.Pop()
+}
+
+func (w *WALSet[T]) PopN(n int) ([]T, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.Set.PopN(n)
+}
+
+func (w *WALSet[T]) UnmarshalJSON(b []byte) error {
+	return setCodec[T]{w}.UnmarshalJSON(b)
+}
+
+func (w *WALSet[T]) MarshalBinary() ([]byte, error) {
+	return setCodec[T]{w}.MarshalBinary()
+}
+
+func (w *WALSet[T]) UnmarshalBinary(data []byte) error {
+	return setCodec[T]{w}.UnmarshalBinary(data)
+}
+
+func (w *WALSet[T]) MarshalText() ([]byte, error) {
+	return setCodec[T]{w}.MarshalText()
+}
+
+func (w *WALSet[T]) UnmarshalText(text []byte) error {
+	return setCodec[T]{w}.UnmarshalText(text)
+}
+
+func (w *WALSet[T]) MarshalYAML() (interface{}, error) {
+	return setCodec[T]{w}.MarshalYAML()
+}
+
+func (w *WALSet[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return setCodec[T]{w}.UnmarshalYAML(unmarshal)
+}
+
+func (w *WALSet[T]) UnmarshalBSONValue(bt bsontype.Type, b []byte) error {
+	return setCodec[T]{w}.UnmarshalBSONValue(bt, b)
+}
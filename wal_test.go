@@ -0,0 +1,189 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openWALSet(t *testing.T, dir string, policy CompactionPolicy) *WALSet[string] {
+	t.Helper()
+	s, err := OpenWALSet[string](dir, policy)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	return s
+}
+
+func Test_WALSet(t *testing.T) {
+	dir := t.TempDir()
+	s := openWALSet(t, dir, CompactionPolicy{})
+	s.Add("a")
+	s.Append("b", "c", "d")
+	s.Remove("b")
+	s.Pop()
+	s.Add("b")
+	if !Move[string](s, NewSet[string](), "b") {
+		t.Fatal("b should be moved")
+	}
+	want := s.Clone()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	s = openWALSet(t, dir, CompactionPolicy{})
+	if !s.Equal(want) {
+		t.Errorf("Expected %v, got: %v", want, s)
+	}
+	if caps := CapabilitiesOf[string](s); !caps.Has(CapPersistent | CapThreadSafe) {
+		t.Errorf("expected a persistent set, got %v", caps)
+	}
+
+	s.Clear()
+	s.Add("e")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	s = openWALSet(t, dir, CompactionPolicy{})
+	defer s.Close()
+	if !s.Equal(NewSet("e")) {
+		t.Errorf("Expected {e}, got: %v", s)
+	}
+}
+
+func Test_WALSetTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	s := openWALSet(t, dir, CompactionPolicy{})
+	s.Add("a")
+	s.Add("b")
+	s.Close()
+
+	path := filepath.Join(dir, walLogFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	s = openWALSet(t, dir, CompactionPolicy{})
+	if !s.Equal(NewSet("a")) {
+		t.Errorf("the torn record should be dropped, got %v", s)
+	}
+	// new records follow the last good one
+	s.Add("c")
+	s.Close()
+	s = openWALSet(t, dir, CompactionPolicy{})
+	defer s.Close()
+	if !s.Equal(NewSet("a", "c")) {
+		t.Errorf("Expected {a, c}, got: %v", s)
+	}
+}
+
+func Test_WALSetCompaction(t *testing.T) {
+	dir := t.TempDir()
+	s := openWALSet(t, dir, CompactionPolicy{MaxLogSize: 512})
+	for i := 0; i < 100; i++ {
+		s.Add("elem")
+		s.Remove("elem")
+	}
+	s.Add("kept")
+	if err := s.Err(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if s.size > 512 {
+		t.Errorf("expected the log to be compacted, got %d bytes", s.size)
+	}
+	if _, err := os.Stat(filepath.Join(dir, walSnapshotFile)); err != nil {
+		t.Errorf("expected a snapshot: %v", err)
+	}
+	s.Close()
+
+	s = openWALSet(t, dir, CompactionPolicy{MaxLogAge: time.Nanosecond})
+	if !s.Equal(NewSet("kept")) {
+		t.Errorf("Expected {kept}, got: %v", s)
+	}
+	s.Add("new")
+	if s.size != 0 {
+		t.Errorf("expected an old log to be compacted, got %d bytes", s.size)
+	}
+	s.Close()
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected only the snapshot and the log, got %v", entries)
+	}
+}
+
+func Test_WALSetReplayAfterSnapshot(t *testing.T) {
+	// a crash between the snapshot rename and the log truncation leaves
+	// records that the snapshot already reflects
+	dir := t.TempDir()
+	s := openWALSet(t, dir, CompactionPolicy{})
+	s.Append("a", "b")
+	s.Remove("a")
+	s.Add("c")
+	log, err := os.ReadFile(filepath.Join(dir, walLogFile))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := s.Compact(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	s.Close()
+	if err := os.WriteFile(filepath.Join(dir, walLogFile), log, 0o644); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	s = openWALSet(t, dir, CompactionPolicy{})
+	defer s.Close()
+	if !s.Equal(NewSet("b", "c")) {
+		t.Errorf("Expected {b, c}, got: %v", s)
+	}
+}
+
+func Test_WALSetUpdate(t *testing.T) {
+	dir := t.TempDir()
+	s := openWALSet(t, dir, CompactionPolicy{})
+	s.Append("a", "b")
+	Update[string](s, func(tx MutableView[string]) {
+		tx.Remove("a")
+		tx.Add("c")
+	})
+	if err := s.Close(); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	s = openWALSet(t, dir, CompactionPolicy{})
+	defer s.Close()
+	if !s.Equal(NewSet("b", "c")) {
+		t.Errorf("Expected {b, c}, got: %v", s)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ChangeEvent is a change of a set delivered by ObservableSet.Watch. An
+// event of Op OpAdd or OpRemove holds the elements added or removed, while
+// clearing the set is delivered as an OpClear event without elements.
+type ChangeEvent[T comparable] struct {
+	Mutation[T]
+	// Coalesced is the number of mutations merged into the event, along
+	// with the other events delivered with it if any. It is one unless the
+	// watch coalesces events, see WithCoalescing.
+	Coalesced int
+}
+
+// WatchOption configures ObservableSet.Watch.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	buffer   int
+	coalesce bool
+}
+
+// WithWatchBuffer sets the capacity of the channel returned by Watch, so
+// that up to n events can be pending before the watch applies backpressure
+// or coalesces events.
+func WithWatchBuffer(n int) WatchOption {
+	return func(o *watchOptions) {
+		o.buffer = n
+	}
+}
+
+// WithCoalescing makes a watch merge the changes pending while the receiver
+// lags behind instead of blocking the mutations of the set. The merged
+// changes are delivered as their net effect, in at most one clear, one
+// removal and one addition: an element added and then removed again is not
+// delivered at all, and the changes before a clear are replaced by it.
+func WithCoalescing() WatchOption {
+	return func(o *watchOptions) {
+		o.coalesce = true
+	}
+}
+
+// Watch returns a channel delivering the changes of the set until ctx is
+// done, when the channel is closed. By default, mutations block until the
+// receiver takes their change or ctx is done, so that a slow receiver slows
+// the writers down; WithCoalescing merges the pending changes instead.
+func (o *ObservableSet[T]) Watch(ctx context.Context, opts ...WatchOption) <-chan ChangeEvent[T] {
+	var wo watchOptions
+	for _, opt := range opts {
+		opt(&wo)
+	}
+
+	w := &watcher[T]{ctx: ctx, ch: make(chan ChangeEvent[T], wo.buffer)}
+	if wo.coalesce {
+		w.pending = make(map[T]Op)
+		w.ready = make(chan struct{}, 1)
+		cancel := o.Subscribe(w.merge)
+		go func() {
+			// only the pump sends on the channel, and closes it when it stops
+			w.pump()
+			cancel()
+		}()
+	} else {
+		cancel := o.Subscribe(w.send)
+		go func() {
+			<-ctx.Done()
+			cancel()
+			w.close()
+		}()
+	}
+	return w.ch
+}
+
+// watcher delivers the mutations of a set to the channel of a Watch.
+// Without coalescing, the subscription sends on the channel itself.
+type watcher[T comparable] struct {
+	ctx context.Context
+
+	// mu serializes the sends on ch with closing it, since a mutation may
+	// still be published after the subscription is canceled.
+	mu     sync.Mutex
+	ch     chan ChangeEvent[T]
+	closed bool
+
+	// pending holds the net changes not delivered yet when coalescing,
+	// since the last clear if cleared, and ready signals the pump that
+	// there are some.
+	pending map[T]Op
+	cleared bool
+	merged  int
+	last    time.Time
+	ready   chan struct{}
+}
+
+func (w *watcher[T]) send(m Mutation[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	if m.Op == OpClear {
+		m.Elems = nil
+	}
+	select {
+	case w.ch <- ChangeEvent[T]{Mutation: m, Coalesced: 1}:
+	case <-w.ctx.Done():
+	}
+}
+
+func (w *watcher[T]) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	close(w.ch)
+}
+
+func (w *watcher[T]) merge(m Mutation[T]) {
+	w.mu.Lock()
+	if m.Op == OpClear {
+		// the set is empty, whatever the pending changes were
+		w.pending, w.cleared = make(map[T]Op), true
+	} else {
+		for _, v := range m.Elems {
+			// the changes of an element alternate, so a change cancels
+			// the pending opposite one
+			if _, ok := w.pending[v]; ok {
+				delete(w.pending, v)
+			} else {
+				w.pending[v] = m.Op
+			}
+		}
+	}
+	w.merged++
+	w.last = m.Time
+	w.mu.Unlock()
+
+	select {
+	case w.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pump delivers the pending changes whenever the receiver is ready, until
+// the context of the watch is done.
+func (w *watcher[T]) pump() {
+	defer close(w.ch)
+
+	for {
+		select {
+		case <-w.ready:
+		case <-w.ctx.Done():
+			return
+		}
+
+		w.mu.Lock()
+		var added, removed []T
+		for v, op := range w.pending {
+			if op == OpAdd {
+				added = append(added, v)
+			} else {
+				removed = append(removed, v)
+			}
+		}
+		merged, last, cleared := w.merged, w.last, w.cleared
+		w.pending, w.merged, w.cleared = make(map[T]Op), 0, false
+		w.mu.Unlock()
+
+		events := make([]Mutation[T], 0, 3)
+		if cleared {
+			events = append(events, Mutation[T]{Op: OpClear})
+		}
+		for _, m := range []Mutation[T]{{Op: OpRemove, Elems: removed}, {Op: OpAdd, Elems: added}} {
+			if len(m.Elems) > 0 {
+				events = append(events, m)
+			}
+		}
+		for _, m := range events {
+			m.Time = last
+			select {
+			case w.ch <- ChangeEvent[T]{Mutation: m, Coalesced: merged}:
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}
+}
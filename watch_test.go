@@ -0,0 +1,154 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// receive returns the next event of ch, failing the test if none comes.
+func receive[T comparable](t *testing.T, ch <-chan ChangeEvent[T]) (ChangeEvent[T], bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		return ev, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	panic("unreachable")
+}
+
+func Test_Watch(t *testing.T) {
+	s := NewObservableSet(NewSet[int]())
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := s.Watch(ctx)
+
+	go func() {
+		s.Append(1, 2)
+		s.Remove(1)
+		s.Clear()
+	}()
+	for _, want := range []Mutation[int]{
+		{Op: OpAdd, Elems: []int{1, 2}},
+		{Op: OpRemove, Elems: []int{1}},
+		{Op: OpClear},
+	} {
+		ev, ok := receive(t, ch)
+		if !ok || ev.Op != want.Op || len(ev.Elems) != len(want.Elems) || !NewSet(ev.Elems...).Equal(NewSet(want.Elems...)) || ev.Coalesced != 1 {
+			t.Errorf("Expected %v %v, got: %v %v", want.Op, want.Elems, ev.Op, ev.Elems)
+		}
+	}
+
+	cancel()
+	for {
+		if _, ok := receive(t, ch); !ok {
+			break
+		}
+	}
+	// nobody receives anymore, which must not block the set
+	s.Add(3)
+}
+
+func Test_WatchCoalescing(t *testing.T) {
+	s := NewObservableSet(NewSet[int]())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := s.Watch(ctx, WithCoalescing())
+
+	// nobody receives yet, so the mutations must not block
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+		if i%2 == 1 {
+			s.Remove(i)
+		}
+	}
+
+	// the events may be merged in any way, but their net effect is the set
+	mirror := NewSet[int]()
+	for !mirror.Equal(s) {
+		ev, _ := receive(t, ch)
+		switch ev.Op {
+		case OpAdd:
+			mirror.Append(ev.Elems...)
+		case OpRemove:
+			mirror.RemoveAll(ev.Elems...)
+		case OpClear:
+			mirror.Clear()
+		}
+	}
+}
+
+func Test_WatchCoalescingClear(t *testing.T) {
+	s := NewObservableSet(NewSet(1, 2))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := s.Watch(ctx, WithCoalescing())
+
+	// the clear replaces the changes before it
+	s.Add(3)
+	s.Remove(1)
+	s.Clear()
+	s.Append(4, 5)
+	s.Remove(5)
+
+	// the pump may have taken some changes before the clear already, but
+	// the clear is delivered once and without elements
+	mirror, clears := NewSet(1, 2), 0
+	for clears == 0 || !mirror.Equal(s) {
+		ev, _ := receive(t, ch)
+		switch ev.Op {
+		case OpAdd:
+			mirror.Append(ev.Elems...)
+		case OpRemove:
+			mirror.RemoveAll(ev.Elems...)
+		case OpClear:
+			clears++
+			if len(ev.Elems) != 0 {
+				t.Errorf("Expected a clear without elements, got: %v", ev.Elems)
+			}
+			mirror.Clear()
+		}
+	}
+	if clears != 1 || !mirror.Equal(NewSet(4)) {
+		t.Errorf("Expected a single clear and {4}, got: %d and %v", clears, mirror)
+	}
+}
+
+func Test_WatchBuffer(t *testing.T) {
+	s := NewObservableSet(NewThreadUnsafeSet[string]())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := s.Watch(ctx, WithWatchBuffer(2))
+
+	// the buffer holds both events without a receiver
+	s.Add("a")
+	s.Add("b")
+	if len(ch) != 2 {
+		t.Errorf("Expected 2 buffered events, got: %d", len(ch))
+	}
+}